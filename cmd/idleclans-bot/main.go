@@ -7,11 +7,13 @@ import (
 	"path"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"github.com/jirwin/idleclans/pkg/bot"
 	"github.com/jirwin/idleclans/pkg/quests"
+	"github.com/jirwin/idleclans/pkg/tracker"
 	"github.com/jirwin/idleclans/pkg/web"
 	icPlugin "github.com/jirwin/idleclans/plugins/idleclans"
 	"go.uber.org/zap"
@@ -27,7 +29,31 @@ func (a *botAdapter) SendMessage(channelID, message string) error {
 }
 
 func (a *botAdapter) SendMessageWithEmbed(channelID, content string, embed *web.DiscordEmbed) error {
-	// Convert web.DiscordEmbed to discordgo.MessageEmbed
+	return a.bot.SendMessageWithEmbed(channelID, content, toDiscordEmbed(embed))
+}
+
+func (a *botAdapter) SendMessageWithButtons(channelID, content string, embed *web.DiscordEmbed, buttons []web.DiscordButton) error {
+	row := discordgo.ActionsRow{}
+	for _, button := range buttons {
+		style := discordgo.SuccessButton
+		if button.Danger {
+			style = discordgo.DangerButton
+		}
+		row.Components = append(row.Components, discordgo.Button{
+			Label:    button.Label,
+			Style:    style,
+			CustomID: button.CustomID,
+		})
+	}
+	return a.bot.SendMessageWithComponents(channelID, content, toDiscordEmbed(embed), []discordgo.MessageComponent{row})
+}
+
+func (a *botAdapter) SendDirectMessageWithEmbed(userID, content string, embed *web.DiscordEmbed) error {
+	return a.bot.SendDirectMessageWithEmbed(userID, content, toDiscordEmbed(embed))
+}
+
+// toDiscordEmbed converts a web.DiscordEmbed to a discordgo.MessageEmbed
+func toDiscordEmbed(embed *web.DiscordEmbed) *discordgo.MessageEmbed {
 	dgEmbed := &discordgo.MessageEmbed{
 		Title:       embed.Title,
 		Description: embed.Description,
@@ -42,7 +68,49 @@ func (a *botAdapter) SendMessageWithEmbed(channelID, content string, embed *web.
 		})
 	}
 
-	return a.bot.SendMessageWithEmbed(channelID, content, dgEmbed)
+	return dgEmbed
+}
+
+// screenshotAdapter adapts the web server's screenshot analysis to the plugin's ScreenshotAnalyzer interface
+type screenshotAdapter struct {
+	server *web.Server
+}
+
+func (a *screenshotAdapter) AnalyzeQuestScreenshot(imageData []byte, imageType string) (map[string]int, error) {
+	results, err := a.server.AnalyzeQuestScreenshot(imageData, imageType)
+	if err != nil {
+		return nil, err
+	}
+	bosses := make(map[string]int, len(results))
+	for _, r := range results {
+		bosses[r.Name] = r.Kills
+	}
+	return bosses, nil
+}
+
+func (a *screenshotAdapter) AnalyzeKeyScreenshot(imageData []byte, imageType string) (map[string]int, error) {
+	results, err := a.server.AnalyzeKeyScreenshot(imageData, imageType)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]int, len(results))
+	for _, r := range results {
+		keys[r.Type] = r.Count
+	}
+	return keys, nil
+}
+
+// partyAdapter adapts the web server's party sessions to the plugin's PartyCreator interface
+type partyAdapter struct {
+	server *web.Server
+}
+
+func (a *partyAdapter) CreateParty(ctx context.Context, players []string) (string, error) {
+	partyID, err := a.server.CreatePartySession(ctx, players, nil)
+	if err != nil {
+		return "", err
+	}
+	return a.server.PartyURL(partyID), nil
 }
 
 func initLogging(ctx context.Context) context.Context {
@@ -99,6 +167,18 @@ func getEnvInt(name string, defaultVal int) int {
 	return i
 }
 
+func getEnvFloat(name string, defaultVal float64) float64 {
+	val := os.Getenv(name)
+	if val == "" {
+		return defaultVal
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return defaultVal
+	}
+	return f
+}
+
 func getEnvString(name, defaultVal string) string {
 	val := os.Getenv(name)
 	if val == "" {
@@ -121,6 +201,8 @@ func main() {
 
 	// Initialize web server if configured
 	var webServer *web.Server
+	var webDB *quests.DB
+	var plannerConfig icPlugin.PlannerConfig
 	discordClientID := getCredential("discord_client_id", "DISCORD_CLIENT_ID")
 	discordClientSecret := getCredential("discord_client_secret", "DISCORD_CLIENT_SECRET")
 
@@ -136,7 +218,8 @@ func main() {
 		}
 		
 		// Create database connection for web server
-		webDB, err := quests.NewDB(dbURL)
+		var err error
+		webDB, err = quests.NewDB(dbURL)
 		if err != nil {
 			l.Error("Failed to open database for web server", zap.Error(err), zap.String("db_url", dbURL))
 			os.Exit(1)
@@ -145,6 +228,12 @@ func main() {
 
 		enableMarket = os.Getenv("ENABLE_MARKET") == "true" || os.Getenv("ENABLE_MARKET") == "1"
 
+		plannerObjective, err := quests.ParsePlanObjective(os.Getenv("PLANNER_OBJECTIVE"))
+		if err != nil {
+			l.Warn("Invalid PLANNER_OBJECTIVE, using the default objective", zap.Error(err))
+			plannerObjective = quests.DefaultPlanObjective()
+		}
+
 		webConfig := &web.Config{
 			PublicPort:          getEnvInt("WEB_PUBLIC_PORT", 8080),
 			AdminPort:           getEnvInt("WEB_ADMIN_PORT", 8081),
@@ -157,6 +246,19 @@ func main() {
 			OpenAIAPIKey:        getCredential("openai_api_key", "OPENAI_API_KEY"),
 			OpenAIModel:         getEnvString("OPENAI_MODEL", "gpt-4o"),
 			EnableMarket:        enableMarket,
+			SnapshotInterval:    time.Duration(getEnvInt("PLAYER_SNAPSHOT_INTERVAL_MINUTES", 60)) * time.Minute,
+			SnapshotRetention:   time.Duration(getEnvInt("PLAYER_SNAPSHOT_RETENTION_DAYS", 30)) * 24 * time.Hour,
+			QuestReminderHours:  getEnvInt("QUEST_REMINDER_HOURS", 0),
+			PlannerFairness:     getEnvFloat("PLANNER_FAIRNESS_WEIGHT", 0),
+			PartyReminderLead:   time.Duration(getEnvInt("PARTY_REMINDER_MINUTES", 15)) * time.Minute,
+			PlannerObjective:    plannerObjective,
+			PlannerSearchBudget: time.Duration(getEnvInt("PLANNER_SEARCH_MS", int(quests.DefaultSearchBudget/time.Millisecond))) * time.Millisecond,
+		}
+
+		plannerConfig = icPlugin.PlannerConfig{
+			FairnessWeight: webConfig.PlannerFairness,
+			Objective:      webConfig.PlannerObjective,
+			SearchBudget:   webConfig.PlannerSearchBudget,
 		}
 
 		if webConfig.BaseURL == "" {
@@ -204,6 +306,25 @@ func main() {
 
 	// If web server is running, connect notifications
 	if webServer != nil {
+		if p, ok := plugin.(interface {
+			SetQuestsDB(*quests.DB)
+		}); ok {
+			p.SetQuestsDB(webDB)
+			l.Info("Connected web server database to bot plugin")
+		}
+
+		if p, ok := plugin.(interface {
+			SetTracker(*tracker.Tracker)
+		}); ok {
+			p.SetTracker(webServer.Tracker())
+		}
+
+		if p, ok := plugin.(interface {
+			SetPlannerConfig(icPlugin.PlannerConfig)
+		}); ok {
+			p.SetPlannerConfig(plannerConfig)
+		}
+
 		if p, ok := plugin.(interface {
 			SetNotifyFunc(icPlugin.DataChangeNotifier)
 		}); ok {
@@ -212,6 +333,20 @@ func main() {
 		} else {
 			l.Warn("Failed to connect web server notifications - type assertion failed")
 		}
+
+		if p, ok := plugin.(interface {
+			SetScreenshotAnalyzer(icPlugin.ScreenshotAnalyzer)
+		}); ok {
+			p.SetScreenshotAnalyzer(&screenshotAdapter{server: webServer})
+			l.Info("Connected screenshot analysis to bot plugin")
+		}
+
+		if p, ok := plugin.(interface {
+			SetPartyCreator(icPlugin.PartyCreator)
+		}); ok {
+			p.SetPartyCreator(&partyAdapter{server: webServer})
+			l.Info("Connected party sessions to bot plugin")
+		}
 	}
 
 	b.LoadPlugins(ctx, []bot.Plugin{
@@ -229,6 +364,10 @@ func main() {
 		webServer.SetDiscordSender(&botAdapter{bot: b})
 		l.Info("Connected Discord message sender to web server")
 
+		// Start player snapshot tracker
+		webServer.StartTracker(ctx)
+		l.Info("Player snapshot tracker started")
+
 		// Start market collector now that everything is initialized
 		if enableMarket {
 			webServer.StartMarketCollector(ctx)
@@ -245,8 +384,9 @@ func main() {
 	l.Info("Shutting down...")
 
 	if webServer != nil {
-		// Stop market collector first
+		// Stop background jobs first
 		webServer.StopMarketCollector()
+		webServer.StopTracker()
 
 		if err := webServer.Stop(ctx); err != nil {
 			l.Error("Error stopping web server", zap.Error(err))
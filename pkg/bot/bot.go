@@ -68,3 +68,22 @@ func (b *Bot) SendMessageWithEmbed(channelID, content string, embed *discordgo.M
 	})
 	return err
 }
+
+// SendMessageWithComponents sends a message with an embed and interactive components such as buttons
+func (b *Bot) SendMessageWithComponents(channelID, content string, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error {
+	_, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:    content,
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: components,
+	})
+	return err
+}
+
+// SendDirectMessageWithEmbed sends a message with an embed to a user's DMs
+func (b *Bot) SendDirectMessageWithEmbed(userID, content string, embed *discordgo.MessageEmbed) error {
+	channel, err := b.session.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+	return b.SendMessageWithEmbed(channel.ID, content, embed)
+}
@@ -33,3 +33,11 @@ func WithMessageHandler(handler func(*discordgo.Session, *discordgo.MessageCreat
 		b.session.AddHandler(handler)
 	}
 }
+
+type InteractionHandler func(*discordgo.Session, *discordgo.InteractionCreate)
+
+func WithInteractionHandler(handler func(*discordgo.Session, *discordgo.InteractionCreate)) Option {
+	return func(b *Bot) {
+		b.session.AddHandler(handler)
+	}
+}
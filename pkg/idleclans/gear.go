@@ -0,0 +1,113 @@
+package idleclans
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// GearSlot is an equipment slot and the item equipped in it
+type GearSlot struct {
+	Slot   string `json:"slot"`
+	ItemID int    `json:"item_id"`
+	NameID string `json:"name_id,omitempty"` // Item catalog name, e.g. "gold_pickaxe"
+	Name   string `json:"name"`              // Display name, e.g. "Gold Pickaxe"
+	Empty  bool   `json:"empty"`             // Nothing equipped in the slot
+	Known  bool   `json:"known"`             // Item was found in the item catalog
+}
+
+// GetItemName returns the catalog name for an item ID. The catalog is loaded by Run.
+func (c *Client) GetItemName(itemID int) (string, bool) {
+	return c.itemManager.GetItem(strconv.Itoa(itemID))
+}
+
+// GetItemID resolves an item name such as "Gold Pickaxe" or a numeric item ID to an item ID
+func (c *Client) GetItemID(name string) (int, bool) {
+	if id, err := strconv.Atoi(strings.TrimSpace(name)); err == nil {
+		return id, true
+	}
+	id, ok := c.itemManager.GetItemID(strings.TrimSpace(name))
+	if !ok {
+		return 0, false
+	}
+	itemID, err := strconv.Atoi(id)
+	return itemID, err == nil
+}
+
+// ItemDisplayName converts a catalog name such as "gold_pickaxe" to "Gold Pickaxe"
+func ItemDisplayName(nameID string) string {
+	return cases.Title(language.English).String(strings.ReplaceAll(nameID, "_", " "))
+}
+
+// EquipmentSlots lists the equipment slots in display order
+var EquipmentSlots = []string{
+	"head", "body", "legs", "boots", "gloves", "belt", "cape", "leftHand", "rightHand",
+	"ammunition", "amulet", "earrings", "bracelet", "jewellery", "pet",
+}
+
+// EquipmentBySlot returns the player's equipped item IDs keyed by slot
+func (p *Player) EquipmentBySlot() map[string]int {
+	e := p.Equipment
+	return map[string]int{
+		"head":       e.Head,
+		"body":       e.Body,
+		"legs":       e.Legs,
+		"boots":      e.Boots,
+		"gloves":     e.Gloves,
+		"belt":       e.Belt,
+		"cape":       e.Cape,
+		"leftHand":   e.LeftHand,
+		"rightHand":  e.RightHand,
+		"ammunition": e.Ammunition,
+		"amulet":     e.Amulet,
+		"earrings":   e.Earrings,
+		"bracelet":   e.Bracelet,
+		"jewellery":  e.Jewellery,
+		"pet":        e.Pet,
+	}
+}
+
+// ResolveGear maps a player's equipment to item names, slot by slot.
+// Empty slots and items missing from the catalog are marked as such.
+func (c *Client) ResolveGear(p *Player) []GearSlot {
+	return c.ResolveEquipment(p.EquipmentBySlot())
+}
+
+// ResolveEquipment maps equipped item IDs keyed by slot to item names
+func (c *Client) ResolveEquipment(equipment map[string]int) []GearSlot {
+	gear := make([]GearSlot, 0, len(EquipmentSlots))
+	for _, slot := range EquipmentSlots {
+		itemID := equipment[slot]
+		g := GearSlot{
+			Slot:   slot,
+			ItemID: itemID,
+		}
+		// The API reports empty slots as 0 or -1
+		if itemID <= 0 {
+			g.Empty = true
+			g.Name = "Empty"
+		} else if nameID, ok := c.GetItemName(itemID); ok {
+			g.Known = true
+			g.NameID = nameID
+			g.Name = ItemDisplayName(nameID)
+		} else {
+			g.Name = "Unknown item #" + strconv.Itoa(itemID)
+		}
+		gear = append(gear, g)
+	}
+	return gear
+}
+
+// SlotDisplayName converts an equipment slot key such as "leftHand" to "Left Hand"
+func SlotDisplayName(slot string) string {
+	var sb strings.Builder
+	for i, r := range slot {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			sb.WriteRune(' ')
+		}
+		sb.WriteRune(r)
+	}
+	return cases.Title(language.English).String(sb.String())
+}
@@ -0,0 +1,106 @@
+package idleclans
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// MaxSkillLevel is the highest real level in the experience table
+	MaxSkillLevel = 120
+	// MaxVirtualLevel is the highest virtual level the calculator will extrapolate to
+	MaxVirtualLevel = 150
+	// virtualLevelGrowth is the per-level growth applied past the cap.
+	// The official table roughly doubles the cost of a level every seven levels.
+	virtualLevelGrowth = 1.104
+)
+
+// virtualExpTable holds the extrapolated experience for levels past MaxSkillLevel.
+// virtualExpTable[0] is the experience required for MaxSkillLevel+1.
+var virtualExpTable = buildVirtualExpTable()
+
+func buildVirtualExpTable() []int64 {
+	table := make([]int64, 0, MaxVirtualLevel-MaxSkillLevel)
+	last := int64(expTable[len(expTable)-1])
+	step := float64(expTable[len(expTable)-1] - expTable[len(expTable)-2])
+	for level := MaxSkillLevel + 1; level <= MaxVirtualLevel; level++ {
+		step *= virtualLevelGrowth
+		last += int64(math.Round(step))
+		table = append(table, last)
+	}
+	return table
+}
+
+// ExperienceForLevel returns the total experience required to reach a level.
+// Levels above MaxSkillLevel are virtual and follow the extrapolated curve.
+func ExperienceForLevel(level int) (int64, error) {
+	switch {
+	case level < 1:
+		return 0, fmt.Errorf("level must be at least 1")
+	case level <= MaxSkillLevel:
+		return int64(expTable[level-1]), nil
+	case level <= MaxVirtualLevel:
+		return virtualExpTable[level-MaxSkillLevel-1], nil
+	default:
+		return 0, fmt.Errorf("level must be at most %d", MaxVirtualLevel)
+	}
+}
+
+// GetVirtualSkillLevel returns the level for an amount of experience, continuing
+// past MaxSkillLevel into virtual levels.
+func GetVirtualSkillLevel(exp int64) int {
+	for i := len(virtualExpTable) - 1; i >= 0; i-- {
+		if exp >= virtualExpTable[i] {
+			return MaxSkillLevel + i + 1
+		}
+	}
+	level, _ := GetSkillLevel(int(exp))
+	return level
+}
+
+// XPProgress describes how far a skill is from a target level
+type XPProgress struct {
+	Experience       int64
+	Level            int
+	VirtualLevel     int
+	TargetLevel      int
+	TargetExperience int64
+	Remaining        int64
+}
+
+// CalculateXPToLevel returns the progress of exp towards targetLevel.
+// Remaining is zero when the target has already been reached.
+func CalculateXPToLevel(exp int64, targetLevel int) (*XPProgress, error) {
+	targetExp, err := ExperienceForLevel(targetLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	level, _ := GetSkillLevel(int(exp))
+	progress := &XPProgress{
+		Experience:       exp,
+		Level:            level,
+		VirtualLevel:     GetVirtualSkillLevel(exp),
+		TargetLevel:      targetLevel,
+		TargetExperience: targetExp,
+	}
+	if exp < targetExp {
+		progress.Remaining = targetExp - exp
+	}
+
+	return progress, nil
+}
+
+// EstimateTimeToLevel returns how long it takes to gain remaining experience at xpPerHour.
+// It returns false if the rate is not positive.
+func EstimateTimeToLevel(remaining int64, xpPerHour float64) (time.Duration, bool) {
+	if xpPerHour <= 0 {
+		return 0, false
+	}
+	if remaining <= 0 {
+		return 0, true
+	}
+	hours := float64(remaining) / xpPerHour
+	return time.Duration(hours * float64(time.Hour)), true
+}
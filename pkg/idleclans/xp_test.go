@@ -0,0 +1,55 @@
+package idleclans
+
+import "testing"
+
+func TestExperienceForLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   int
+		want    int64
+		wantErr bool
+	}{
+		{name: "first level", level: 1, want: 0},
+		{name: "second level", level: 2, want: 75},
+		{name: "tenth level", level: 10, want: 988},
+		{name: "max real level", level: MaxSkillLevel, want: int64(expTable[MaxSkillLevel-1])},
+		{name: "first virtual level", level: MaxSkillLevel + 1, want: virtualExpTable[0]},
+		{name: "max virtual level", level: MaxVirtualLevel, want: virtualExpTable[len(virtualExpTable)-1]},
+		{name: "zero", level: 0, wantErr: true},
+		{name: "past max virtual level", level: MaxVirtualLevel + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExperienceForLevel(tt.level)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ExperienceForLevel(%d) = %d, want an error", tt.level, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExperienceForLevel(%d) returned error: %v", tt.level, err)
+			}
+			if got != tt.want {
+				t.Errorf("ExperienceForLevel(%d) = %d, want %d", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExperienceForLevelIncreases(t *testing.T) {
+	prev, _ := ExperienceForLevel(1)
+	for level := 2; level <= MaxVirtualLevel; level++ {
+		exp, err := ExperienceForLevel(level)
+		if err != nil {
+			t.Fatalf("ExperienceForLevel(%d) returned error: %v", level, err)
+		}
+		if exp <= prev {
+			t.Errorf("ExperienceForLevel(%d) = %d, want more than level %d's %d", level, exp, level-1, prev)
+		}
+		if got := GetVirtualSkillLevel(exp); got != level {
+			t.Errorf("GetVirtualSkillLevel(%d) = %d, want %d", exp, got, level)
+		}
+		prev = exp
+	}
+}
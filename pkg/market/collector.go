@@ -41,6 +41,8 @@ type TriggeredWatch struct {
 	WatchType       string // "buy" or "sell"
 	Threshold       int
 	CurrentPrice    int
+	Delivery        string // "channel" or "dm"
+	ChannelID       string // Channel for "channel" delivery; empty for the shared channel
 }
 
 // Collector fetches price data from the IdleClans API
@@ -373,6 +375,10 @@ func (c *Collector) processWatches(ctx context.Context) {
 					WatchType:       watch.WatchType,
 					Threshold:       watch.Threshold,
 					CurrentPrice:    currentPrice,
+					Delivery:        watch.Delivery,
+				}
+				if watch.ChannelID != nil {
+					triggeredWatch.ChannelID = *watch.ChannelID
 				}
 
 				if err := c.watchNotifier.NotifyWatchTriggered(ctx, triggeredWatch); err != nil {
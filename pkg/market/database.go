@@ -79,6 +79,22 @@ func (d *DB) runMigrations() {
 		d.logger.Debug("Migration: history_backfilled column may already exist", zap.Error(err))
 	}
 
+	// Watches can be delivered by DM or to the channel they were created in
+	_, err = d.db.Exec(`
+		ALTER TABLE market_watches 
+		ADD COLUMN IF NOT EXISTS delivery TEXT NOT NULL DEFAULT 'channel'
+	`)
+	if err != nil {
+		d.logger.Debug("Migration: delivery column may already exist", zap.Error(err))
+	}
+	_, err = d.db.Exec(`
+		ALTER TABLE market_watches 
+		ADD COLUMN IF NOT EXISTS channel_id TEXT
+	`)
+	if err != nil {
+		d.logger.Debug("Migration: channel_id column may already exist", zap.Error(err))
+	}
+
 	// Add index for priority queue queries
 	_, _ = d.db.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_market_items_price_last_collected 
@@ -581,6 +597,66 @@ func (d *DB) GetAllItemsWithPrices(ctx context.Context) ([]ItemWithPrice, error)
 	return items, err
 }
 
+// GetCategories returns the categories of items with price data, sorted
+func (d *DB) GetCategories(ctx context.Context) ([]string, error) {
+	var categories []string
+	query := `
+		SELECT DISTINCT mi.category
+		FROM market_items mi
+		INNER JOIN market_prices mp ON mi.id = mp.item_id
+		WHERE mi.category IS NOT NULL AND mi.category != ''
+		ORDER BY mi.category
+	`
+	err := d.db.SelectContext(ctx, &categories, query)
+	return categories, err
+}
+
+// GetItemsWithPricesByCategory retrieves the items in a category with their latest prices,
+// most actively listed first
+func (d *DB) GetItemsWithPricesByCategory(ctx context.Context, category string, limit int) ([]ItemWithPrice, error) {
+	var items []ItemWithPrice
+	query := `
+		WITH latest_prices AS (
+			SELECT DISTINCT ON (mp.item_id)
+				mp.item_id,
+				mp.lowest_sell_price,
+				mp.lowest_price_volume,
+				mp.highest_buy_price,
+				mp.highest_price_volume,
+				mp.time
+			FROM market_prices mp
+			INNER JOIN market_items mi ON mi.id = mp.item_id
+			WHERE mi.category = $1
+			ORDER BY mp.item_id, mp.time DESC
+		)
+		SELECT 
+			mi.id,
+			mi.name_id,
+			mi.display_name,
+			mi.category,
+			lp.lowest_sell_price,
+			lp.lowest_price_volume,
+			lp.highest_buy_price,
+			lp.highest_price_volume,
+			CASE 
+				WHEN lp.highest_buy_price > 0 THEN lp.lowest_sell_price - lp.highest_buy_price
+				ELSE 0
+			END as spread,
+			CASE 
+				WHEN lp.highest_buy_price > 0 THEN 
+					((lp.lowest_sell_price - lp.highest_buy_price)::float / lp.highest_buy_price) * 100
+				ELSE 0
+			END as spread_percent,
+			to_char(lp.time, 'YYYY-MM-DD"T"HH24:MI:SS"Z"') as last_updated
+		FROM market_items mi
+		INNER JOIN latest_prices lp ON mi.id = lp.item_id
+		ORDER BY lp.lowest_price_volume + lp.highest_price_volume DESC, mi.display_name
+		LIMIT $2
+	`
+	err := d.db.SelectContext(ctx, &items, query, category, limit)
+	return items, err
+}
+
 // PaginatedItemsResult holds paginated items with total count
 type PaginatedItemsResult struct {
 	Items []ItemWithPrice `json:"items"`
@@ -1413,6 +1489,15 @@ func (d *DB) GetMostTradedOptimized(ctx context.Context, limit int) ([]PriceChan
 	return results, err
 }
 
+// MaxWatchesPerUser is the most untriggered watches a user can have
+const MaxWatchesPerUser = 10
+
+// Watch delivery methods
+const (
+	WatchDeliveryChannel = "channel" // Post in the watch's channel, or the shared channel if it has none
+	WatchDeliveryDM      = "dm"      // Direct message the user
+)
+
 // Watch represents a market price watch/alert
 type Watch struct {
 	ID          int        `db:"id" json:"id"`
@@ -1424,6 +1509,8 @@ type Watch struct {
 	TriggeredAt *time.Time `db:"triggered_at" json:"triggered_at,omitempty"`
 	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
 	ExpiresAt   *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+	Delivery    string     `db:"delivery" json:"delivery"`               // "channel" or "dm"
+	ChannelID   *string    `db:"channel_id" json:"channel_id,omitempty"` // Channel for "channel" delivery; nil for the shared channel
 }
 
 // WatchWithItem represents a watch with item details and current prices
@@ -1435,19 +1522,33 @@ type WatchWithItem struct {
 	CurrentSellPrice int    `db:"current_sell_price" json:"current_sell_price"`
 }
 
-// CreateWatch creates a new market watch for a user
+// CreateWatch creates a new market watch for a user, delivered to the shared channel
 func (d *DB) CreateWatch(ctx context.Context, userID string, itemID int, watchType string, threshold int) (*Watch, error) {
+	return d.CreateWatchWithDelivery(ctx, userID, itemID, watchType, threshold, WatchDeliveryChannel, "")
+}
+
+// CreateWatchWithDelivery creates a new market watch for a user.
+// For channel delivery, an empty channelID uses the shared notification channel.
+func (d *DB) CreateWatchWithDelivery(ctx context.Context, userID string, itemID int, watchType string, threshold int, delivery, channelID string) (*Watch, error) {
 	if watchType != "buy" && watchType != "sell" {
 		return nil, fmt.Errorf("invalid watch type: must be 'buy' or 'sell'")
 	}
+	if delivery != WatchDeliveryChannel && delivery != WatchDeliveryDM {
+		return nil, fmt.Errorf("invalid delivery: must be '%s' or '%s'", WatchDeliveryChannel, WatchDeliveryDM)
+	}
+
+	var channel *string
+	if channelID != "" {
+		channel = &channelID
+	}
 
 	query := `
-		INSERT INTO market_watches (user_id, item_id, watch_type, threshold, created_at)
-		VALUES ($1, $2, $3, $4, NOW())
-		RETURNING id, user_id, item_id, watch_type, threshold, triggered, triggered_at, created_at, expires_at
+		INSERT INTO market_watches (user_id, item_id, watch_type, threshold, delivery, channel_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, user_id, item_id, watch_type, threshold, triggered, triggered_at, created_at, expires_at, delivery, channel_id
 	`
 	var watch Watch
-	err := d.db.GetContext(ctx, &watch, query, userID, itemID, watchType, threshold)
+	err := d.db.GetContext(ctx, &watch, query, userID, itemID, watchType, threshold, delivery, channel)
 	if err != nil {
 		return nil, err
 	}
@@ -1468,6 +1569,7 @@ func (d *DB) GetWatchesByUser(ctx context.Context, userID string) ([]WatchWithIt
 		SELECT 
 			w.id, w.user_id, w.item_id, w.watch_type, w.threshold, 
 			w.triggered, w.triggered_at, w.created_at, w.expires_at,
+			w.delivery, w.channel_id,
 			mi.name_id as item_name_id,
 			COALESCE(mi.display_name, mi.name_id) as item_display_name,
 			COALESCE(lp.highest_buy_price, 0) as current_buy_price,
@@ -1487,7 +1589,7 @@ func (d *DB) GetWatchesByUser(ctx context.Context, userID string) ([]WatchWithIt
 // GetActiveWatches retrieves all non-triggered watches for processing
 func (d *DB) GetActiveWatches(ctx context.Context) ([]Watch, error) {
 	query := `
-		SELECT id, user_id, item_id, watch_type, threshold, triggered, triggered_at, created_at, expires_at
+		SELECT id, user_id, item_id, watch_type, threshold, triggered, triggered_at, created_at, expires_at, delivery, channel_id
 		FROM market_watches
 		WHERE triggered = FALSE
 		ORDER BY item_id
@@ -0,0 +1,180 @@
+package quests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidBoss is returned by SaveBoss when a catalog entry fails validation
+var ErrInvalidBoss = errors.New("invalid boss")
+
+var bossNamePattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// MaxBossPartySize caps a boss's maximum party size. The plan search enumerates every party of
+// up to that many players, which grows combinatorially with the size.
+const MaxBossPartySize = 5
+
+const bossCatalogColumns = `name, display_name, aliases, key_type, key_color, emoji, pvm_stat, min_party_size, max_party_size, raid, sort_order`
+
+// seedBossCatalog fills an empty boss catalog with the default bosses
+func (d *DB) seedBossCatalog(ctx context.Context) error {
+	var count int
+	if err := d.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM boss_catalog`); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	for _, b := range defaultBosses {
+		if err := d.upsertBoss(ctx, b); err != nil {
+			return fmt.Errorf("failed to seed boss %s: %w", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetBossCatalog returns every boss in the catalog, in display order
+func (d *DB) GetBossCatalog(ctx context.Context) ([]Boss, error) {
+	query := `SELECT ` + bossCatalogColumns + ` FROM boss_catalog ORDER BY sort_order, name`
+	var bosses []Boss
+	if err := d.db.SelectContext(ctx, &bosses, query); err != nil {
+		return nil, err
+	}
+	return bosses, nil
+}
+
+// LoadBossCatalog makes the package's boss lookups use the catalog stored in the database
+func (d *DB) LoadBossCatalog(ctx context.Context) error {
+	bosses, err := d.GetBossCatalog(ctx)
+	if err != nil {
+		return err
+	}
+	if len(bosses) == 0 {
+		return nil
+	}
+	setBossCatalog(bosses)
+	return nil
+}
+
+// SaveBoss adds a boss to the catalog or replaces it, then reloads the catalog
+func (d *DB) SaveBoss(ctx context.Context, b Boss) (Boss, error) {
+	l := ctxzap.Extract(ctx)
+
+	b, err := normalizeBoss(b)
+	if err != nil {
+		return Boss{}, err
+	}
+	if err := d.upsertBoss(ctx, b); err != nil {
+		return Boss{}, err
+	}
+	if err := d.LoadBossCatalog(ctx); err != nil {
+		return Boss{}, err
+	}
+
+	l.Info("Saved boss to catalog",
+		zap.String("name", b.Name),
+		zap.String("key_type", b.KeyType),
+		zap.Bool("raid", b.Raid))
+	return b, nil
+}
+
+// DeleteBoss removes a boss from the catalog, then reloads the catalog.
+// Quests already recorded for the boss are kept.
+func (d *DB) DeleteBoss(ctx context.Context, name string) (bool, error) {
+	query := d.db.Rebind(`DELETE FROM boss_catalog WHERE name = ?`)
+	result, err := d.db.ExecContext(ctx, query, name)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	bosses, err := d.GetBossCatalog(ctx)
+	if err != nil {
+		return true, err
+	}
+	setBossCatalog(bosses)
+	return true, nil
+}
+
+func (d *DB) upsertBoss(ctx context.Context, b Boss) error {
+	if b.Aliases == nil {
+		b.Aliases = pq.StringArray{}
+	}
+	query := d.db.Rebind(`
+		INSERT INTO boss_catalog (` + bossCatalogColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			display_name = EXCLUDED.display_name,
+			aliases = EXCLUDED.aliases,
+			key_type = EXCLUDED.key_type,
+			key_color = EXCLUDED.key_color,
+			emoji = EXCLUDED.emoji,
+			pvm_stat = EXCLUDED.pvm_stat,
+			min_party_size = EXCLUDED.min_party_size,
+			max_party_size = EXCLUDED.max_party_size,
+			raid = EXCLUDED.raid,
+			sort_order = EXCLUDED.sort_order
+	`)
+	_, err := d.db.ExecContext(ctx, query,
+		b.Name, b.DisplayName, b.Aliases, b.KeyType, b.KeyColor, b.Emoji, b.PvmStat,
+		b.MinPartySize, b.MaxPartySize, b.Raid, b.SortOrder)
+	return err
+}
+
+// normalizeBoss validates a catalog entry and fills in defaults
+func normalizeBoss(b Boss) (Boss, error) {
+	b.Name = strings.ToLower(strings.TrimSpace(b.Name))
+	if !bossNamePattern.MatchString(b.Name) {
+		return b, fmt.Errorf("%w: name must be lowercase letters and digits, e.g. griffin", ErrInvalidBoss)
+	}
+	b.DisplayName = strings.TrimSpace(b.DisplayName)
+	if b.DisplayName == "" {
+		b.DisplayName = strings.ToUpper(b.Name[:1]) + b.Name[1:]
+	}
+	b.KeyType = strings.ToLower(strings.TrimSpace(b.KeyType))
+	b.KeyColor = strings.ToLower(strings.TrimSpace(b.KeyColor))
+	if b.KeyColor != "" && b.KeyType == "" {
+		return b, fmt.Errorf("%w: a key color needs a key type", ErrInvalidBoss)
+	}
+
+	aliases := pq.StringArray{}
+	for _, alias := range b.Aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if alias == "" {
+			continue
+		}
+		if other, ok := ResolveBossName(alias); ok && other != b.Name {
+			return b, fmt.Errorf("%w: alias %q already refers to %s", ErrInvalidBoss, alias, other)
+		}
+		aliases = append(aliases, alias)
+	}
+	b.Aliases = aliases
+
+	if b.MinPartySize == 0 {
+		b.MinPartySize = 1
+	}
+	if b.MaxPartySize == 0 {
+		b.MaxPartySize = PartySize
+	}
+	if b.MinPartySize < 1 || b.MaxPartySize < b.MinPartySize {
+		return b, fmt.Errorf("%w: party size must be at least 1 and the maximum no less than the minimum", ErrInvalidBoss)
+	}
+	if b.MaxPartySize > MaxBossPartySize {
+		return b, fmt.Errorf("%w: party size can be at most %d", ErrInvalidBoss, MaxBossPartySize)
+	}
+	return b, nil
+}
@@ -64,6 +64,10 @@ func (d *DB) initSchema() error {
 	// This handles both new columns (which will be NULL) and ensures consistency
 	_, _ = d.db.Exec(`UPDATE weekly_quests SET max_required_kills = required_kills WHERE max_required_kills IS NULL`)
 
+	// Migration: Add columns for kills synced from the IdleClans API
+	_, _ = d.db.Exec(`ALTER TABLE weekly_quests ADD COLUMN IF NOT EXISTS api_kills INTEGER`)
+	_, _ = d.db.Exec(`ALTER TABLE weekly_quests ADD COLUMN IF NOT EXISTS api_synced_at TIMESTAMP`)
+
 	// Migration: Convert player_keys from discord_user_id to player_name
 	// Check if old schema exists (has discord_user_id column)
 	var count int
@@ -76,6 +80,14 @@ func (d *DB) initSchema() error {
 		}
 	}
 
+	// Seed the boss catalog on first run and use it for boss lookups
+	if err := d.seedBossCatalog(context.Background()); err != nil {
+		return fmt.Errorf("failed to seed boss catalog: %w", err)
+	}
+	if err := d.LoadBossCatalog(context.Background()); err != nil {
+		return fmt.Errorf("failed to load boss catalog: %w", err)
+	}
+
 	return nil
 }
 
@@ -168,6 +180,82 @@ func (d *DB) getPostgreSQLSchema() string {
 
 	CREATE INDEX IF NOT EXISTS idx_parties_created ON parties(created_at);
 	CREATE INDEX IF NOT EXISTS idx_party_step_progress_party ON party_step_progress(party_id);
+
+	CREATE TABLE IF NOT EXISTS pvm_week_baselines (
+		player_name TEXT NOT NULL,
+		week_number INTEGER NOT NULL,
+		year INTEGER NOT NULL,
+		boss_name TEXT NOT NULL,
+		kills INTEGER NOT NULL,
+		recorded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (player_name, week_number, year, boss_name)
+	);
+
+	CREATE TABLE IF NOT EXISTS key_ledger (
+		id SERIAL PRIMARY KEY,
+		entry_type TEXT NOT NULL,
+		key_type TEXT NOT NULL,
+		from_player TEXT NOT NULL,
+		to_player TEXT,
+		beneficiaries TEXT,
+		count INTEGER NOT NULL,
+		party_id TEXT,
+		step_index INTEGER,
+		recorded_by TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_key_ledger_created ON key_ledger(created_at);
+	CREATE INDEX IF NOT EXISTS idx_key_ledger_party ON key_ledger(party_id);
+
+	CREATE TABLE IF NOT EXISTS scheduled_parties (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL DEFAULT '',
+		scheduled_at TIMESTAMPTZ NOT NULL,
+		channel_id TEXT NOT NULL DEFAULT '',
+		created_by TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'scheduled',
+		reminder_sent_at TIMESTAMPTZ,
+		party_id TEXT,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_scheduled_parties_status ON scheduled_parties(status, scheduled_at);
+
+	CREATE TABLE IF NOT EXISTS scheduled_party_rsvps (
+		scheduled_party_id TEXT NOT NULL REFERENCES scheduled_parties(id) ON DELETE CASCADE,
+		player_name TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		responded_at TIMESTAMPTZ,
+		PRIMARY KEY (scheduled_party_id, player_name)
+	);
+
+	CREATE TABLE IF NOT EXISTS boss_catalog (
+		name TEXT PRIMARY KEY,
+		display_name TEXT NOT NULL,
+		aliases TEXT[] NOT NULL DEFAULT '{}',
+		key_type TEXT NOT NULL DEFAULT '',
+		key_color TEXT NOT NULL DEFAULT '',
+		emoji TEXT NOT NULL DEFAULT '',
+		pvm_stat TEXT NOT NULL DEFAULT '',
+		min_party_size INTEGER NOT NULL DEFAULT 1,
+		max_party_size INTEGER NOT NULL DEFAULT 3,
+		raid BOOLEAN NOT NULL DEFAULT FALSE,
+		sort_order INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS key_history (
+		id SERIAL PRIMARY KEY,
+		player_name TEXT NOT NULL,
+		key_type TEXT NOT NULL,
+		count INTEGER NOT NULL,
+		change INTEGER NOT NULL,
+		source TEXT NOT NULL,
+		recorded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_key_history_player ON key_history(player_name, key_type, recorded_at);
+	CREATE INDEX IF NOT EXISTS idx_key_history_recorded ON key_history(recorded_at);
 	`
 }
 
@@ -308,6 +396,13 @@ func (d *DB) DeletePlayer(ctx context.Context, discordUserID string) error {
 		_, _ = d.db.ExecContext(ctx, `DELETE FROM player_keys WHERE player_name = ?`, alt)
 	}
 
+	// Delete key history for main and alts
+	for _, name := range append([]string{playerName}, alts...) {
+		if name != "" {
+			_, _ = d.db.ExecContext(ctx, d.db.Rebind(`DELETE FROM key_history WHERE player_name = ?`), name)
+		}
+	}
+
 	// Delete quests
 	_, _ = d.db.ExecContext(ctx, `DELETE FROM weekly_quests WHERE discord_user_id = ?`, discordUserID)
 
@@ -698,6 +793,23 @@ func (d *DB) GetPlayersWithBossQuest(ctx context.Context, bossName string, weekN
 func (d *DB) UpsertPlayerKeys(ctx context.Context, playerName string, keyType string, count int) error {
 	l := ctxzap.Extract(ctx)
 
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previous int
+	err = tx.GetContext(ctx, &previous, d.db.Rebind(`SELECT count FROM player_keys WHERE player_name = ? AND key_type = ?`), playerName, keyType)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	// The first count entered for a key is the player's existing inventory, not new drops
+	source := KeyHistoryUpdate
+	if err == sql.ErrNoRows {
+		source = KeyHistoryInitial
+	}
+
 	query := `
 		INSERT INTO player_keys (player_name, key_type, count, updated_at)
 		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
@@ -706,13 +818,17 @@ func (d *DB) UpsertPlayerKeys(ctx context.Context, playerName string, keyType st
 			updated_at = CURRENT_TIMESTAMP
 	`
 	query = d.db.Rebind(query)
-	_, err := d.db.ExecContext(ctx, query, playerName, keyType, count)
+	_, err = tx.ExecContext(ctx, query, playerName, keyType, count)
 	if err != nil {
 		l.Error("Failed to upsert player keys", zap.Error(err), zap.String("player_name", playerName), zap.String("key", keyType), zap.Int("count", count))
 		return err
 	}
 
-	return nil
+	if err := d.recordKeyHistory(ctx, tx, playerName, keyType, count, count-previous, source); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetPlayerKeys returns all key counts for a player (by player name)
@@ -1005,6 +1121,7 @@ func (d *DB) CreateParty(ctx context.Context, id string, players string, planDat
 		INSERT INTO parties (id, players, plan_data, current_step_index, created_at)
 		VALUES (?, ?, ?, 0, CURRENT_TIMESTAMP)
 	`
+	query = d.db.Rebind(query)
 	_, err := d.db.ExecContext(ctx, query, id, players, planData)
 	return err
 }
@@ -1077,6 +1194,7 @@ func (d *DB) GetPartiesForUser(ctx context.Context, discordUserID string, limit
 // StartParty marks the party as started
 func (d *DB) StartParty(ctx context.Context, id string) error {
 	query := `UPDATE parties SET started_at = CURRENT_TIMESTAMP WHERE id = ? AND started_at IS NULL`
+	query = d.db.Rebind(query)
 	_, err := d.db.ExecContext(ctx, query, id)
 	return err
 }
@@ -1084,6 +1202,7 @@ func (d *DB) StartParty(ctx context.Context, id string) error {
 // EndParty marks the party as ended
 func (d *DB) EndParty(ctx context.Context, id string) error {
 	query := `UPDATE parties SET ended_at = CURRENT_TIMESTAMP WHERE id = ? AND ended_at IS NULL`
+	query = d.db.Rebind(query)
 	_, err := d.db.ExecContext(ctx, query, id)
 	return err
 }
@@ -1091,6 +1210,7 @@ func (d *DB) EndParty(ctx context.Context, id string) error {
 // UpdatePartyStepIndex updates the current step index for a party
 func (d *DB) UpdatePartyStepIndex(ctx context.Context, id string, stepIndex int) error {
 	query := `UPDATE parties SET current_step_index = ? WHERE id = ?`
+	query = d.db.Rebind(query)
 	_, err := d.db.ExecContext(ctx, query, stepIndex, id)
 	return err
 }
@@ -1151,6 +1271,7 @@ func (d *DB) StartPartyStep(ctx context.Context, partyID string, stepIndex int,
 // CompletePartyStep marks a step as completed
 func (d *DB) CompletePartyStep(ctx context.Context, partyID string, stepIndex int) error {
 	query := `UPDATE party_step_progress SET completed_at = CURRENT_TIMESTAMP WHERE party_id = ? AND step_index = ?`
+	query = d.db.Rebind(query)
 	_, err := d.db.ExecContext(ctx, query, partyID, stepIndex)
 	return err
 }
@@ -1158,6 +1279,7 @@ func (d *DB) CompletePartyStep(ctx context.Context, partyID string, stepIndex in
 // UpdatePartyStepKills updates the kill count for a step
 func (d *DB) UpdatePartyStepKills(ctx context.Context, partyID string, stepIndex int, kills int) error {
 	query := `UPDATE party_step_progress SET kills_tracked = ? WHERE party_id = ? AND step_index = ?`
+	query = d.db.Rebind(query)
 	_, err := d.db.ExecContext(ctx, query, kills, partyID, stepIndex)
 	return err
 }
@@ -1165,6 +1287,7 @@ func (d *DB) UpdatePartyStepKills(ctx context.Context, partyID string, stepIndex
 // UpdatePartyStepKeys updates the keys used for a step
 func (d *DB) UpdatePartyStepKeys(ctx context.Context, partyID string, stepIndex int, keysUsed int) error {
 	query := `UPDATE party_step_progress SET keys_used = ? WHERE party_id = ? AND step_index = ?`
+	query = d.db.Rebind(query)
 	_, err := d.db.ExecContext(ctx, query, keysUsed, partyID, stepIndex)
 	return err
 }
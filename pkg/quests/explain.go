@@ -0,0 +1,208 @@
+package quests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Explanation codes for tasks and leftovers
+const (
+	ReasonKeyHolder     = "key_holder"      // Keys come from a party member
+	ReasonNoKeyNeeded   = "no_key_needed"   // The boss or raid doesn't need a key
+	ReasonNoKeys        = "no_keys"         // Nobody in the party has keys for the boss
+	ReasonNotEnoughKeys = "not_enough_keys" // The party ran out of keys before everyone's kills were done
+	ReasonNotSelected   = "not_selected"    // The player wasn't among the players the plan was made for
+	ReasonNoParty       = "no_party"        // The player wasn't placed in a party
+)
+
+// maxAlternatives caps how many alternatives an explanation lists
+const maxAlternatives = 5
+
+// Explanation says why the planner made a choice and what else it considered
+type Explanation struct {
+	Code         string
+	Reason       string
+	Alternatives []string
+}
+
+// planExplainer annotates a plan's tasks and leftovers, using the needs and keys players had
+// before any party was planned
+type planExplainer struct {
+	initial  map[string]*PlayerProfile
+	current  map[string]*PlayerProfile
+	groupOf  map[string]int // Player -> index of their party
+	selected func(name string) bool
+}
+
+func newPlanExplainer(profiles map[string]*PlayerProfile, selected func(name string) bool) *planExplainer {
+	e := &planExplainer{
+		initial:  make(map[string]*PlayerProfile, len(profiles)),
+		current:  profiles,
+		groupOf:  make(map[string]int),
+		selected: selected,
+	}
+	for name, prof := range profiles {
+		snapshot := &PlayerProfile{
+			Name:      prof.Name,
+			Needs:     make(map[string]int, len(prof.Needs)),
+			Keys:      make(map[string]int, len(prof.Keys)),
+			TotalNeed: prof.TotalNeed,
+		}
+		for boss, need := range prof.Needs {
+			snapshot.Needs[boss] = need
+		}
+		for keyType, count := range prof.Keys {
+			snapshot.Keys[keyType] = count
+		}
+		e.initial[name] = snapshot
+	}
+	return e
+}
+
+// explain annotates every task of the parties and the unmet needs of the leftovers
+func (e *planExplainer) explain(parties []Party, leftovers []PlayerProfile) {
+	for i, party := range parties {
+		for _, name := range party.Players {
+			e.groupOf[name] = i
+		}
+	}
+
+	for i := range parties {
+		for j := range parties[i].Tasks {
+			parties[i].Tasks[j].Explanation = e.explainTask(&parties[i], &parties[i].Tasks[j])
+		}
+	}
+
+	for i := range leftovers {
+		leftovers[i].Unmet = make(map[string]Explanation)
+		for boss, need := range leftovers[i].Needs {
+			if need > 0 {
+				leftovers[i].Unmet[boss] = e.explainLeftover(leftovers[i].Name, boss, need)
+			}
+		}
+	}
+}
+
+func (e *planExplainer) explainTask(party *Party, task *PartyTask) Explanation {
+	var neededBy []string
+	for _, name := range party.Players {
+		if need := e.initial[name].Needs[task.BossName]; need > 0 {
+			neededBy = append(neededBy, fmt.Sprintf("%s (%d)", name, need))
+		}
+	}
+	needed := fmt.Sprintf("Needed by %s.", strings.Join(neededBy, ", "))
+
+	if task.KeyType == "" && !task.NoKeys {
+		return Explanation{
+			Code:   ReasonNoKeyNeeded,
+			Reason: fmt.Sprintf("%s No key needed.", needed),
+		}
+	}
+
+	if task.NoKeys {
+		return Explanation{
+			Code:         ReasonNoKeys,
+			Reason:       fmt.Sprintf("%s Nobody in the party has %s keys.", needed, task.KeyType),
+			Alternatives: e.holdersOutside(party.Players, task.KeyType, e.initial),
+		}
+	}
+
+	partyKeys := 0
+	var alternatives []string
+	for _, name := range party.Players {
+		count := e.initial[name].Keys[task.KeyType]
+		partyKeys += count
+		if name != task.KeyHolder && count > 0 {
+			alternatives = append(alternatives, fmt.Sprintf("%s: %d keys", name, count))
+		}
+	}
+	return Explanation{
+		Code: ReasonKeyHolder,
+		Reason: fmt.Sprintf("%s %s had %d of the party's %d %s keys.",
+			needed, task.KeyHolder, e.initial[task.KeyHolder].Keys[task.KeyType], partyKeys, task.KeyType),
+		Alternatives: alternatives,
+	}
+}
+
+func (e *planExplainer) explainLeftover(name, boss string, need int) Explanation {
+	if !e.selected(name) {
+		return Explanation{
+			Code:   ReasonNotSelected,
+			Reason: "Not among the players the plan was made for.",
+		}
+	}
+
+	group, ok := e.groupOf[name]
+	if !ok {
+		return Explanation{
+			Code:   ReasonNoParty,
+			Reason: "No party could be formed for this player.",
+		}
+	}
+
+	keyType, _ := GetKeyForBoss(boss)
+	members := e.membersOf(group)
+	partyKeys := 0
+	for _, member := range members {
+		partyKeys += e.initial[member].Keys[keyType]
+	}
+	return Explanation{
+		Code: ReasonNotEnoughKeys,
+		Reason: fmt.Sprintf("Group %d only had %d %s keys, %d kills short.",
+			group+1, partyKeys, keyType, need),
+		Alternatives: e.holdersOutside(members, keyType, e.current),
+	}
+}
+
+func (e *planExplainer) membersOf(group int) []string {
+	var members []string
+	for name, g := range e.groupOf {
+		if g == group {
+			members = append(members, name)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// holdersOutside lists the players outside a party that hold keys of a type, most keys first
+func (e *planExplainer) holdersOutside(players []string, keyType string, profiles map[string]*PlayerProfile) []string {
+	inParty := make(map[string]bool, len(players))
+	for _, name := range players {
+		inParty[name] = true
+	}
+
+	type holder struct {
+		name  string
+		count int
+	}
+	var holders []holder
+	for name, prof := range profiles {
+		if !inParty[name] && prof.Keys[keyType] > 0 {
+			holders = append(holders, holder{name: name, count: prof.Keys[keyType]})
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if holders[i].count != holders[j].count {
+			return holders[i].count > holders[j].count
+		}
+		return holders[i].name < holders[j].name
+	})
+
+	var alternatives []string
+	for i, h := range holders {
+		if i == maxAlternatives {
+			alternatives = append(alternatives, fmt.Sprintf("and %d more", len(holders)-maxAlternatives))
+			break
+		}
+		where := "not in a party"
+		if g, ok := e.groupOf[h.name]; ok {
+			where = fmt.Sprintf("group %d", g+1)
+		} else if !e.selected(h.name) {
+			where = "not selected"
+		}
+		alternatives = append(alternatives, fmt.Sprintf("%s: %d keys (%s)", h.name, h.count, where))
+	}
+	return alternatives
+}
@@ -0,0 +1,229 @@
+package quests
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHistoryWeeks is how many weeks quest history covers when not specified
+	DefaultHistoryWeeks = 8
+	// MaxHistoryWeeks caps the quest history window
+	MaxHistoryWeeks = 52
+)
+
+// QuestHistory summarizes completed and incomplete quests over recent weeks,
+// for the whole clan or a single player
+type QuestHistory struct {
+	PlayerName string           `json:"player_name,omitempty"` // Empty for the whole clan
+	Weeks      []WeekCompletion `json:"weeks"`                 // Oldest first
+	Bosses     []BossQuestStats `json:"bosses"`                // Most often incomplete first
+	Streaks    []PlayerStreak   `json:"streaks"`               // Longest current streak first
+}
+
+// WeekCompletion is the share of quests completed in one week
+type WeekCompletion struct {
+	Week      int     `json:"week"`
+	Year      int     `json:"year"`
+	Quests    int     `json:"quests"`
+	Completed int     `json:"completed"`
+	Rate      float64 `json:"rate"` // 0-1; 0 if there were no quests
+}
+
+// BossQuestStats aggregates one boss's quests over the history window
+type BossQuestStats struct {
+	Boss        string  `json:"boss"`
+	Quests      int     `json:"quests"`
+	Incomplete  int     `json:"incomplete"`
+	AvgRequired float64 `json:"avg_required"` // Average kills asked for per quest
+	AvgKills    float64 `json:"avg_kills"`    // Average kills made per quest
+}
+
+// PlayerStreak counts consecutive fully completed weeks for a player.
+// The current week only counts once it is complete, so it never breaks a streak.
+type PlayerStreak struct {
+	PlayerName string `json:"player_name"`
+	Current    int    `json:"current"`
+	Best       int    `json:"best"`
+}
+
+type questHistoryRow struct {
+	PlayerName       string `db:"player_name"`
+	WeekNumber       int    `db:"week_number"`
+	Year             int    `db:"year"`
+	BossName         string `db:"boss_name"`
+	RequiredKills    int    `db:"required_kills"`
+	MaxRequiredKills int    `db:"max_required_kills"`
+	CurrentKills     int    `db:"current_kills"`
+}
+
+// complete reports whether a quest has no kills remaining
+func (r *questHistoryRow) complete() bool {
+	return r.CurrentKills >= r.RequiredKills
+}
+
+// GetQuestHistory returns quest completion statistics for the last weeks ISO weeks, including
+// the current one. If playerName is empty, every player's quests are included.
+// Streaks consider the player's whole history, not just the window.
+func (d *DB) GetQuestHistory(ctx context.Context, playerName string, weeks int) (*QuestHistory, error) {
+	if weeks <= 0 {
+		weeks = DefaultHistoryWeeks
+	}
+	if weeks > MaxHistoryWeeks {
+		weeks = MaxHistoryWeeks
+	}
+
+	query := `
+		SELECT player_name, week_number, year, boss_name, required_kills, max_required_kills, current_kills
+		FROM weekly_quests
+	`
+	args := []interface{}{}
+	if playerName != "" {
+		query += ` WHERE LOWER(player_name) = LOWER(?)`
+		args = append(args, playerName)
+	}
+	query += ` ORDER BY year, week_number, player_name, boss_name`
+	query = d.db.Rebind(query)
+
+	var rows []questHistoryRow
+	if err := d.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	history := &QuestHistory{
+		PlayerName: playerName,
+		Weeks:      make([]WeekCompletion, weeks),
+		Bosses:     []BossQuestStats{},
+		Streaks:    []PlayerStreak{},
+	}
+	weekIndex := make(map[[2]int]int, weeks)
+	for i := 0; i < weeks; i++ {
+		year, week := now.AddDate(0, 0, -7*(weeks-1-i)).ISOWeek()
+		history.Weeks[i] = WeekCompletion{Week: week, Year: year}
+		weekIndex[[2]int{year, week}] = i
+	}
+
+	bossStats := make(map[string]*BossQuestStats)
+	bossKills := make(map[string][2]int) // boss -> total required, total kills
+	for i := range rows {
+		row := &rows[i]
+		idx, ok := weekIndex[[2]int{row.Year, row.WeekNumber}]
+		if !ok {
+			continue
+		}
+
+		wc := &history.Weeks[idx]
+		wc.Quests++
+		if row.complete() {
+			wc.Completed++
+		}
+
+		stats, ok := bossStats[row.BossName]
+		if !ok {
+			stats = &BossQuestStats{Boss: row.BossName}
+			bossStats[row.BossName] = stats
+		}
+		stats.Quests++
+		if !row.complete() {
+			stats.Incomplete++
+		}
+		totals := bossKills[row.BossName]
+		totals[0] += row.MaxRequiredKills
+		totals[1] += min(row.CurrentKills, row.MaxRequiredKills)
+		bossKills[row.BossName] = totals
+	}
+
+	for i := range history.Weeks {
+		if wc := &history.Weeks[i]; wc.Quests > 0 {
+			wc.Rate = float64(wc.Completed) / float64(wc.Quests)
+		}
+	}
+
+	for boss, stats := range bossStats {
+		totals := bossKills[boss]
+		stats.AvgRequired = float64(totals[0]) / float64(stats.Quests)
+		stats.AvgKills = float64(totals[1]) / float64(stats.Quests)
+		history.Bosses = append(history.Bosses, *stats)
+	}
+	sort.Slice(history.Bosses, func(i, j int) bool {
+		a, b := history.Bosses[i], history.Bosses[j]
+		if a.Incomplete != b.Incomplete {
+			return a.Incomplete > b.Incomplete
+		}
+		return a.Boss < b.Boss
+	})
+
+	history.Streaks = computeStreaks(rows, now)
+	return history, nil
+}
+
+// computeStreaks finds each player's current and best run of consecutive fully completed weeks.
+// rows must be ordered oldest week first.
+func computeStreaks(rows []questHistoryRow, now time.Time) []PlayerStreak {
+	type weekResult struct {
+		start    time.Time
+		complete bool
+	}
+
+	names := make(map[string]string) // lowercase -> display name
+	var order []string
+	byPlayer := make(map[string][]weekResult)
+	for i := range rows {
+		row := &rows[i]
+		key := strings.ToLower(row.PlayerName)
+		if _, ok := names[key]; !ok {
+			names[key] = row.PlayerName
+			order = append(order, key)
+		}
+
+		start := WeekStart(row.WeekNumber, row.Year)
+		results := byPlayer[key]
+		if n := len(results); n > 0 && results[n-1].start.Equal(start) {
+			results[n-1].complete = results[n-1].complete && row.complete()
+			continue
+		}
+		byPlayer[key] = append(results, weekResult{start: start, complete: row.complete()})
+	}
+
+	currentYear, currentWeek := now.ISOWeek()
+	currentStart := WeekStart(currentWeek, currentYear)
+
+	streaks := make([]PlayerStreak, 0, len(order))
+	for _, key := range order {
+		streak := PlayerStreak{PlayerName: names[key]}
+		run := 0
+		var last time.Time
+		for _, result := range byPlayer[key] {
+			if !result.complete {
+				// The week in progress doesn't break the streak until it's over
+				if !result.start.Equal(currentStart) {
+					run = 0
+				}
+				continue
+			}
+			if run > 0 && result.start.Sub(last) != 7*24*time.Hour {
+				run = 0 // A week without quests breaks the streak
+			}
+			run++
+			last = result.start
+			streak.Best = max(streak.Best, run)
+		}
+
+		// The streak is only current if it reaches last week or this week
+		if run > 0 && currentStart.Sub(last) <= 7*24*time.Hour {
+			streak.Current = run
+		}
+		streaks = append(streaks, streak)
+	}
+
+	sort.SliceStable(streaks, func(i, j int) bool {
+		if streaks[i].Current != streaks[j].Current {
+			return streaks[i].Current > streaks[j].Current
+		}
+		return streaks[i].Best > streaks[j].Best
+	})
+	return streaks
+}
@@ -0,0 +1,237 @@
+package quests
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// KeyHistoryInitial records the first count entered for a player's key type. It's their
+	// existing inventory, so it doesn't count towards drop rates.
+	KeyHistoryInitial = "initial"
+	// KeyHistoryUpdate records a key count set by a player, an admin or a screenshot scan
+	KeyHistoryUpdate = "update"
+	// KeyHistoryTransfer records keys given from one player to another
+	KeyHistoryTransfer = "transfer"
+)
+
+const (
+	// DefaultForecastWindowDays is how many days of key history drop rates are estimated from
+	DefaultForecastWindowDays = 28
+	// MaxForecastWindowDays caps the drop rate window
+	MaxForecastWindowDays = 365
+	// DefaultKeyHistoryLimit is how many key count changes are listed by default
+	DefaultKeyHistoryLimit = 50
+	// MaxKeyHistoryLimit caps how many key count changes can be listed at once
+	MaxKeyHistoryLimit = 500
+)
+
+// KeyHistoryEntry is one recorded change of a player's key count
+type KeyHistoryEntry struct {
+	ID         int       `db:"id" json:"id"`
+	PlayerName string    `db:"player_name" json:"player_name"`
+	KeyType    string    `db:"key_type" json:"key_type"`
+	Count      int       `db:"count" json:"count"`   // Count after the change
+	Change     int       `db:"change" json:"change"` // Negative when keys were used or given away
+	Source     string    `db:"source" json:"source"`
+	RecordedAt time.Time `db:"recorded_at" json:"recorded_at"`
+}
+
+// KeyForecast compares the keys the clan's remaining quests need this week with the keys
+// players hold and are expected to find before the weekly reset
+type KeyForecast struct {
+	Week       int               `json:"week"`
+	Year       int               `json:"year"`
+	ResetAt    time.Time         `json:"reset_at"`
+	DaysLeft   float64           `json:"days_left"`
+	WindowDays int               `json:"window_days"` // Days of history the drop rates are estimated from
+	Keys       []KeyForecastItem `json:"keys"`        // Most keys short first
+}
+
+// KeyForecastItem is the forecast for one key type
+type KeyForecastItem struct {
+	KeyType       string   `json:"key_type"`
+	Bosses        []string `json:"bosses"`         // Bosses with remaining quest kills that use the key
+	RequiredKills int      `json:"required_kills"` // Remaining quest kills across all players
+	KeysNeeded    int      `json:"keys_needed"`    // Estimated with the yoink bonus
+	KeysHeld      int      `json:"keys_held"`
+	DropsPerDay   float64  `json:"drops_per_day"`
+	ExpectedDrops int      `json:"expected_drops"` // Keys expected to be found before the reset
+	Short         int      `json:"short"`          // Keys missing after expected drops; 0 if covered
+}
+
+// recordKeyHistory records a change of a player's key count. Changes of zero are not recorded.
+func (d *DB) recordKeyHistory(ctx context.Context, tx *sqlx.Tx, playerName, keyType string, count, change int, source string) error {
+	if change == 0 {
+		return nil
+	}
+	query := d.db.Rebind(`
+		INSERT INTO key_history (player_name, key_type, count, change, source, recorded_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`)
+	_, err := tx.ExecContext(ctx, query, playerName, keyType, count, change, source)
+	return err
+}
+
+// GetKeyHistory returns the most recent key count changes, optionally only a player's
+// and only for one key type
+func (d *DB) GetKeyHistory(ctx context.Context, playerName, keyType string, limit int) ([]KeyHistoryEntry, error) {
+	query := `
+		SELECT id, player_name, key_type, count, change, source, recorded_at
+		FROM key_history
+		WHERE 1 = 1
+	`
+	args := []interface{}{}
+	if playerName != "" {
+		query += ` AND LOWER(player_name) = LOWER(?)`
+		args = append(args, playerName)
+	}
+	if keyType != "" {
+		query += ` AND key_type = ?`
+		args = append(args, keyType)
+	}
+	query += ` ORDER BY recorded_at DESC, id DESC`
+	if limit > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, limit)
+	}
+	query = d.db.Rebind(query)
+
+	var entries []KeyHistoryEntry
+	err := d.db.SelectContext(ctx, &entries, query, args...)
+	return entries, err
+}
+
+// GetKeyForecast forecasts, for each key type the current week's remaining quests need, how many
+// keys the clan will be short by at the weekly reset. Key needs come from CalculateKeyRequirements
+// over every player's quests; drop rates are the keys players gained through updates (not
+// transfers or first imports) over the last windowDays days, or since history began if that's
+// more recent.
+func (d *DB) GetKeyForecast(ctx context.Context, windowDays int) (*KeyForecast, error) {
+	if windowDays <= 0 {
+		windowDays = DefaultForecastWindowDays
+	}
+	if windowDays > MaxForecastWindowDays {
+		windowDays = MaxForecastWindowDays
+	}
+
+	now := time.Now().UTC()
+	year, week := now.ISOWeek()
+	resetAt := WeekStart(week, year).AddDate(0, 0, 7)
+	forecast := &KeyForecast{
+		Week:       week,
+		Year:       year,
+		ResetAt:    resetAt,
+		DaysLeft:   resetAt.Sub(now).Hours() / 24,
+		WindowDays: windowDays,
+		Keys:       []KeyForecastItem{},
+	}
+
+	weekQuests, err := d.GetAllQuestsForWeek(ctx, week, year)
+	if err != nil {
+		return nil, err
+	}
+	requirements := CalculateKeyRequirements(weekQuests)
+	if len(requirements) == 0 {
+		return forecast, nil
+	}
+
+	held := make(map[string]int)
+	entries, err := d.GetAllPlayerKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		held[e.KeyType] += e.Count
+	}
+
+	dropsPerDay, err := d.keyDropRates(ctx, windowDays)
+	if err != nil {
+		return nil, err
+	}
+
+	bossesByKey := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, q := range weekQuests {
+		keyType, ok := GetKeyForBoss(q.BossName)
+		if !ok || q.CurrentKills >= q.RequiredKills || seen[q.BossName] {
+			continue
+		}
+		seen[q.BossName] = true
+		bossesByKey[keyType] = append(bossesByKey[keyType], q.BossName)
+	}
+
+	order := make(map[string]int)
+	for i, keyType := range KeyTypes() {
+		order[keyType] = i
+	}
+
+	for keyType, req := range requirements {
+		item := KeyForecastItem{
+			KeyType:       keyType,
+			Bosses:        bossesByKey[keyType],
+			RequiredKills: req.RequiredKills,
+			KeysNeeded:    req.EstimatedKeys,
+			KeysHeld:      held[keyType],
+			DropsPerDay:   dropsPerDay[keyType],
+			ExpectedDrops: int(math.Floor(dropsPerDay[keyType] * forecast.DaysLeft)),
+		}
+		sort.Strings(item.Bosses)
+		if short := item.KeysNeeded - item.KeysHeld - item.ExpectedDrops; short > 0 {
+			item.Short = short
+		}
+		forecast.Keys = append(forecast.Keys, item)
+	}
+	sort.Slice(forecast.Keys, func(i, j int) bool {
+		a, b := forecast.Keys[i], forecast.Keys[j]
+		if a.Short != b.Short {
+			return a.Short > b.Short
+		}
+		return order[a.KeyType] < order[b.KeyType]
+	})
+	return forecast, nil
+}
+
+// keyDropRates estimates how many keys of each type the clan finds per day from the key
+// increases players recorded in the last windowDays days. A player's first count for a key is
+// recorded as KeyHistoryInitial and left out, since it's their existing inventory.
+func (d *DB) keyDropRates(ctx context.Context, windowDays int) (map[string]float64, error) {
+	// History younger than the window would understate the rates, so only count the days it covers
+	var historySeconds float64
+	err := d.db.GetContext(ctx, &historySeconds, `
+		SELECT COALESCE(EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - MIN(recorded_at)), 0)
+		FROM key_history
+	`)
+	if err != nil {
+		return nil, err
+	}
+	days := math.Min(float64(windowDays), historySeconds/86400)
+	if days < 1 {
+		days = 1
+	}
+
+	type dropRow struct {
+		KeyType string `db:"key_type"`
+		Gained  int    `db:"gained"`
+	}
+	query := d.db.Rebind(`
+		SELECT key_type, SUM(change) AS gained
+		FROM key_history
+		WHERE source = ? AND change > 0 AND recorded_at >= CURRENT_TIMESTAMP - make_interval(days => ?)
+		GROUP BY key_type
+	`)
+	var rows []dropRow
+	if err := d.db.SelectContext(ctx, &rows, query, KeyHistoryUpdate, windowDays); err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		rates[row.KeyType] = float64(row.Gained) / days
+	}
+	return rates, nil
+}
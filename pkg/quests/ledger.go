@@ -0,0 +1,232 @@
+package quests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// LedgerConsumed records keys a holder spent on a party step
+	LedgerConsumed = "consumed"
+	// LedgerTransfer records keys given from one player to another
+	LedgerTransfer = "transfer"
+)
+
+// KeyLedgerEntry is one recorded key transfer or party consumption
+type KeyLedgerEntry struct {
+	ID            int       `db:"id" json:"id"`
+	EntryType     string    `db:"entry_type" json:"entry_type"`
+	KeyType       string    `db:"key_type" json:"key_type"`
+	FromPlayer    string    `db:"from_player" json:"from_player"`
+	ToPlayer      *string   `db:"to_player" json:"to_player,omitempty"`
+	Beneficiaries *string   `db:"beneficiaries" json:"-"` // JSON array of party players
+	Count         int       `db:"count" json:"count"`     // Negative for corrections
+	PartyID       *string   `db:"party_id" json:"party_id,omitempty"`
+	StepIndex     *int      `db:"step_index" json:"step_index,omitempty"`
+	RecordedBy    string    `db:"recorded_by" json:"recorded_by"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+// BeneficiaryList returns the party players a consumed key benefited
+func (e *KeyLedgerEntry) BeneficiaryList() []string {
+	if e.Beneficiaries == nil {
+		return nil
+	}
+	var players []string
+	if err := json.Unmarshal([]byte(*e.Beneficiaries), &players); err != nil {
+		return nil
+	}
+	return players
+}
+
+// KeyLedgerBalance is a player's net key contribution. Keys a holder spends on a party are
+// split evenly between its players, so the holder is credited with everyone else's share.
+type KeyLedgerBalance struct {
+	PlayerName string  `json:"player_name"`
+	Given      float64 `json:"given"`
+	Received   float64 `json:"received"`
+	Net        float64 `json:"net"` // Given - Received; positive means the clan owes this player
+}
+
+// RecordKeyConsumption records keys spent by a holder on a party step. count may be negative
+// to correct an earlier over-count.
+func (d *DB) RecordKeyConsumption(ctx context.Context, partyID string, stepIndex int, keyType, holder string, players []string, count int, recordedBy string) error {
+	if count == 0 {
+		return nil
+	}
+	beneficiaries, err := json.Marshal(players)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO key_ledger (entry_type, key_type, from_player, beneficiaries, count, party_id, step_index, recorded_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`
+	query = d.db.Rebind(query)
+	_, err = d.db.ExecContext(ctx, query, LedgerConsumed, keyType, holder, string(beneficiaries), count, partyID, stepIndex, recordedBy)
+	return err
+}
+
+// TransferKeys moves keys from one player to another, updating both key counts and
+// recording the transfer in the ledger
+func (d *DB) TransferKeys(ctx context.Context, fromPlayer, toPlayer, keyType string, count int, recordedBy string) error {
+	l := ctxzap.Extract(ctx)
+
+	if count <= 0 {
+		return fmt.Errorf("count must be positive")
+	}
+
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var available int
+	err = tx.GetContext(ctx, &available, d.db.Rebind(`SELECT count FROM player_keys WHERE player_name = ? AND key_type = ?`), fromPlayer, keyType)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if available < count {
+		return fmt.Errorf("%s only has %d %s keys", fromPlayer, available, keyType)
+	}
+
+	updateQuery := d.db.Rebind(`UPDATE player_keys SET count = count - ?, updated_at = CURRENT_TIMESTAMP WHERE player_name = ? AND key_type = ?`)
+	if _, err := tx.ExecContext(ctx, updateQuery, count, fromPlayer, keyType); err != nil {
+		return err
+	}
+
+	upsertQuery := d.db.Rebind(`
+		INSERT INTO player_keys (player_name, key_type, count, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(player_name, key_type) DO UPDATE SET
+			count = player_keys.count + excluded.count,
+			updated_at = CURRENT_TIMESTAMP
+	`)
+	if _, err := tx.ExecContext(ctx, upsertQuery, toPlayer, keyType, count); err != nil {
+		return err
+	}
+
+	var fromCount, toCount int
+	if err := tx.GetContext(ctx, &fromCount, d.db.Rebind(`SELECT count FROM player_keys WHERE player_name = ? AND key_type = ?`), fromPlayer, keyType); err != nil {
+		return err
+	}
+	if err := tx.GetContext(ctx, &toCount, d.db.Rebind(`SELECT count FROM player_keys WHERE player_name = ? AND key_type = ?`), toPlayer, keyType); err != nil {
+		return err
+	}
+	if err := d.recordKeyHistory(ctx, tx, fromPlayer, keyType, fromCount, -count, KeyHistoryTransfer); err != nil {
+		return err
+	}
+	if err := d.recordKeyHistory(ctx, tx, toPlayer, keyType, toCount, count, KeyHistoryTransfer); err != nil {
+		return err
+	}
+
+	ledgerQuery := d.db.Rebind(`
+		INSERT INTO key_ledger (entry_type, key_type, from_player, to_player, count, recorded_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`)
+	if _, err := tx.ExecContext(ctx, ledgerQuery, LedgerTransfer, keyType, fromPlayer, toPlayer, count, recordedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	l.Info("Transferred keys",
+		zap.String("from", fromPlayer),
+		zap.String("to", toPlayer),
+		zap.String("key", keyType),
+		zap.Int("count", count))
+	return nil
+}
+
+// GetKeyLedgerEntries returns the most recent ledger entries, optionally only those involving a player
+func (d *DB) GetKeyLedgerEntries(ctx context.Context, playerName string, limit int) ([]KeyLedgerEntry, error) {
+	query := `
+		SELECT id, entry_type, key_type, from_player, to_player, beneficiaries, count, party_id, step_index, recorded_by, created_at
+		FROM key_ledger
+	`
+	args := []interface{}{}
+	if playerName != "" {
+		// Beneficiaries is a JSON array, so match the quoted name
+		query += ` WHERE LOWER(from_player) = LOWER(?) OR LOWER(to_player) = LOWER(?) OR LOWER(beneficiaries) LIKE LOWER(?)`
+		args = append(args, playerName, playerName, "%\""+playerName+"\"%")
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+	if limit > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, limit)
+	}
+	query = d.db.Rebind(query)
+
+	var entries []KeyLedgerEntry
+	err := d.db.SelectContext(ctx, &entries, query, args...)
+	return entries, err
+}
+
+// GetKeyLedgerBalances computes each player's net key contribution from the whole ledger,
+// most generous first
+func (d *DB) GetKeyLedgerBalances(ctx context.Context) ([]KeyLedgerBalance, error) {
+	entries, err := d.GetKeyLedgerEntries(ctx, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]*KeyLedgerBalance)
+	balanceFor := func(name string) *KeyLedgerBalance {
+		key := strings.ToLower(name)
+		if b, ok := balances[key]; ok {
+			return b
+		}
+		b := &KeyLedgerBalance{PlayerName: name}
+		balances[key] = b
+		return b
+	}
+
+	for i := range entries {
+		e := &entries[i]
+		switch e.EntryType {
+		case LedgerTransfer:
+			if e.ToPlayer == nil {
+				continue
+			}
+			balanceFor(e.FromPlayer).Given += float64(e.Count)
+			balanceFor(*e.ToPlayer).Received += float64(e.Count)
+		case LedgerConsumed:
+			players := e.BeneficiaryList()
+			if len(players) == 0 {
+				continue
+			}
+			share := float64(e.Count) / float64(len(players))
+			for _, p := range players {
+				if strings.EqualFold(p, e.FromPlayer) {
+					continue
+				}
+				balanceFor(e.FromPlayer).Given += share
+				balanceFor(p).Received += share
+			}
+		}
+	}
+
+	result := make([]KeyLedgerBalance, 0, len(balances))
+	for _, b := range balances {
+		b.Net = b.Given - b.Received
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Net != result[j].Net {
+			return result[i].Net > result[j].Net
+		}
+		return strings.ToLower(result[i].PlayerName) < strings.ToLower(result[j].PlayerName)
+	})
+	return result, nil
+}
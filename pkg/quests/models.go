@@ -1,108 +1,245 @@
 package quests
 
-import "strings"
-
-// BossToKey maps boss names to their required key types
-var BossToKey = map[string]string{
-	"griffin": "mountain",
-	"medusa":  "stone",
-	"hades":   "underworld",
-	"zeus":    "godly",
-	"devil":   "burning",
-	"chimera": "mutated",
-	"dragon":  "otherworldly",
-	"sobek":   "ancient",
-	"kronos":  "kronos",
-}
-
-// KeyToColor maps key types to their colors (for user-friendly input)
-var KeyToColor = map[string]string{
-	"mountain":     "brown",
-	"stone":        "gray",
-	"underworld":   "blue",
-	"godly":        "gold",
-	"burning":      "red",
-	"mutated":      "green",
-	"otherworldly": "otherworldly",
-	"ancient":      "ancient",
-	"kronos":       "book",
-}
-
-// ColorToKey maps colors to key types
-var ColorToKey = make(map[string]string)
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+// Boss is an entry of the boss catalog: a boss or raid quests can be tracked for
+type Boss struct {
+	Name         string         `db:"name" json:"name"` // Lowercase ID used in quests, e.g. "griffin"
+	DisplayName  string         `db:"display_name" json:"display_name"`
+	Aliases      pq.StringArray `db:"aliases" json:"aliases"`     // Other names players use for the boss
+	KeyType      string         `db:"key_type" json:"key_type"`   // Empty for bosses that don't need a key
+	KeyColor     string         `db:"key_color" json:"key_color"` // What players call the key, e.g. "brown"
+	Emoji        string         `db:"emoji" json:"emoji"`         // Shown next to the boss and its key in Discord
+	PvmStat      string         `db:"pvm_stat" json:"pvm_stat"`   // Kill counter in the player profile's pvmStats, empty if none
+	MinPartySize int            `db:"min_party_size" json:"min_party_size"`
+	MaxPartySize int            `db:"max_party_size" json:"max_party_size"`
+	Raid         bool           `db:"raid" json:"raid"`
+	SortOrder    int            `db:"sort_order" json:"sort_order"`
+}
+
+// PartySizeLimit returns how many players can fight the boss together
+func (b Boss) PartySizeLimit() PartySizeLimit {
+	return PartySizeLimit{Min: b.MinPartySize, Max: b.MaxPartySize}
+}
+
+// defaultBosses seeds the boss catalog of a new database
+var defaultBosses = []Boss{
+	{Name: "griffin", DisplayName: "Griffin", KeyType: "mountain", KeyColor: "brown", Emoji: "🟤", PvmStat: "Griffin", MinPartySize: 1, MaxPartySize: 3, SortOrder: 1},
+	{Name: "devil", DisplayName: "Devil", KeyType: "burning", KeyColor: "red", Emoji: "🔴", PvmStat: "Devil", MinPartySize: 1, MaxPartySize: 3, SortOrder: 2},
+	{Name: "hades", DisplayName: "Hades", KeyType: "underworld", KeyColor: "blue", Emoji: "🔵", PvmStat: "Hades", MinPartySize: 1, MaxPartySize: 3, SortOrder: 3},
+	{Name: "zeus", DisplayName: "Zeus", KeyType: "godly", KeyColor: "gold", Emoji: "⭐", PvmStat: "Zeus", MinPartySize: 1, MaxPartySize: 3, SortOrder: 4},
+	{Name: "medusa", DisplayName: "Medusa", KeyType: "stone", KeyColor: "gray", Emoji: "⚫", PvmStat: "Medusa", MinPartySize: 1, MaxPartySize: 3, SortOrder: 5},
+	{Name: "chimera", DisplayName: "Chimera", KeyType: "mutated", KeyColor: "green", Emoji: "🟢", PvmStat: "Chimera", MinPartySize: 1, MaxPartySize: 3, SortOrder: 6},
+	{Name: "dragon", DisplayName: "Dragon", KeyType: "otherworldly", KeyColor: "otherworldly", Emoji: "💫", MinPartySize: 1, MaxPartySize: 3, SortOrder: 7},
+	{Name: "sobek", DisplayName: "Sobek", KeyType: "ancient", KeyColor: "ancient", Emoji: "🏛️", MinPartySize: 1, MaxPartySize: 3, SortOrder: 8},
+	{Name: "kronos", DisplayName: "Kronos", KeyType: "kronos", KeyColor: "book", Emoji: "📖", PvmStat: "Kronos", MinPartySize: 1, MaxPartySize: 3, SortOrder: 9},
+	{Name: "reckoningofthegods", DisplayName: "Reckoning of the Gods", Aliases: pq.StringArray{"rotg", "reckoning"}, PvmStat: "ReckoningOfTheGods", MinPartySize: 1, MaxPartySize: 3, Raid: true, SortOrder: 10},
+	{Name: "guardiansofthecitadel", DisplayName: "Guardians of the Citadel", Aliases: pq.StringArray{"gotc", "citadel"}, PvmStat: "GuardiansOfTheCitadel", MinPartySize: 1, MaxPartySize: 3, Raid: true, SortOrder: 11},
+}
+
+// bossCatalog is the boss catalog the package's lookups read from. It starts with the defaults
+// and is replaced with the database's catalog when it's loaded or edited.
+var bossCatalog = struct {
+	sync.RWMutex
+	bosses []Boss // Sorted by SortOrder, then name
+	byName map[string]Boss
+}{}
 
 func init() {
-	// Build reverse mapping
-	for key, color := range KeyToColor {
-		ColorToKey[strings.ToLower(color)] = key
+	setBossCatalog(defaultBosses)
+}
+
+// setBossCatalog replaces the catalog the package's lookups read from
+func setBossCatalog(bosses []Boss) {
+	sorted := append([]Boss{}, bosses...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].SortOrder != sorted[j].SortOrder {
+			return sorted[i].SortOrder < sorted[j].SortOrder
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	byName := make(map[string]Boss, len(sorted))
+	for _, b := range sorted {
+		byName[b.Name] = b
 	}
+
+	bossCatalog.Lock()
+	defer bossCatalog.Unlock()
+	bossCatalog.bosses = sorted
+	bossCatalog.byName = byName
+}
+
+// Bosses returns the boss catalog in display order
+func Bosses() []Boss {
+	bossCatalog.RLock()
+	defer bossCatalog.RUnlock()
+	return append([]Boss{}, bossCatalog.bosses...)
+}
+
+// GetBoss returns a boss from the catalog
+func GetBoss(name string) (Boss, bool) {
+	bossCatalog.RLock()
+	defer bossCatalog.RUnlock()
+	b, ok := bossCatalog.byName[name]
+	return b, ok
+}
+
+// PartySizeLimit is how many players can fight a boss together
+type PartySizeLimit struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// GetPartySizeLimit returns how many players can fight a boss together
+func GetPartySizeLimit(boss string) PartySizeLimit {
+	if b, ok := GetBoss(boss); ok {
+		return b.PartySizeLimit()
+	}
+	return PartySizeLimit{Min: 1, Max: PartySize}
+}
+
+// Allows reports whether a party of the given size is within the limit
+func (l PartySizeLimit) Allows(size int) bool {
+	return size >= l.Min && size <= l.Max
+}
+
+// String describes the limit, e.g. "1-3 players"
+func (l PartySizeLimit) String() string {
+	if l.Min == l.Max {
+		return fmt.Sprintf("%d players", l.Max)
+	}
+	return fmt.Sprintf("%d-%d players", l.Min, l.Max)
 }
 
 // ValidBosses returns a list of all valid boss names
 func ValidBosses() []string {
-	bosses := make([]string, 0, len(BossToKey))
-	for boss := range BossToKey {
-		bosses = append(bosses, boss)
+	bosses := Bosses()
+	names := make([]string, 0, len(bosses))
+	for _, b := range bosses {
+		names = append(names, b.Name)
 	}
-	return bosses
+	return names
 }
 
 // IsValidBoss checks if a boss name is valid
 func IsValidBoss(boss string) bool {
-	_, ok := BossToKey[boss]
+	_, ok := GetBoss(boss)
 	return ok
 }
 
-// GetKeyForBoss returns the key type required for a boss
+// GetKeyForBoss returns the key type required for a boss.
+// Returns false for unknown bosses and bosses that don't need a key.
 func GetKeyForBoss(boss string) (string, bool) {
-	key, ok := BossToKey[boss]
-	return key, ok
+	b, ok := GetBoss(boss)
+	if !ok || b.KeyType == "" {
+		return "", false
+	}
+	return b.KeyType, true
+}
+
+// GetPvmStat returns the boss's kill counter in the player profile's pvmStats.
+// Bosses without a counter can't be synced from the API.
+func GetPvmStat(boss string) (string, bool) {
+	b, ok := GetBoss(boss)
+	if !ok || b.PvmStat == "" {
+		return "", false
+	}
+	return b.PvmStat, true
+}
+
+// KeyTypes returns every key type in the catalog, in display order
+func KeyTypes() []string {
+	var keyTypes []string
+	seen := make(map[string]bool)
+	for _, b := range Bosses() {
+		if b.KeyType != "" && !seen[b.KeyType] {
+			seen[b.KeyType] = true
+			keyTypes = append(keyTypes, b.KeyType)
+		}
+	}
+	return keyTypes
+}
+
+// GetKeyColor returns the color players call a key type by
+func GetKeyColor(keyType string) (string, bool) {
+	for _, b := range Bosses() {
+		if b.KeyType == keyType && b.KeyColor != "" {
+			return b.KeyColor, true
+		}
+	}
+	return "", false
+}
+
+// GetKeyEmoji returns the emoji shown next to a key type
+func GetKeyEmoji(keyType string) string {
+	for _, b := range Bosses() {
+		if b.KeyType == keyType && b.Emoji != "" {
+			return b.Emoji
+		}
+	}
+	return ""
 }
 
 // ResolveBossName attempts to resolve a boss name from various input formats:
 // 1. Full boss name (e.g., "griffin")
-// 2. Single letter (first letter of boss name, e.g., "g" for "griffin")
-// 3. Key color (e.g., "red" for mountain key bosses)
+// 2. Display name or alias (e.g., "Reckoning of the Gods" or "rotg")
+// 3. Single letter (first letter of boss name, e.g., "g" for "griffin")
+// 4. Key color (e.g., "red" for burning key bosses)
 // Returns the resolved boss name and true if successful, empty string and false otherwise
 func ResolveBossName(input string) (string, bool) {
 	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return "", false
+	}
 
 	// Try full boss name first
 	if IsValidBoss(input) {
 		return input, true
 	}
 
-	// Try single letter (first letter of boss name)
-	if len(input) == 1 {
-		for boss := range BossToKey {
-			if strings.HasPrefix(boss, input) {
-				return boss, true
+	bosses := Bosses()
+
+	// Try display names and aliases
+	compact := strings.ReplaceAll(input, " ", "")
+	for _, b := range bosses {
+		if strings.ReplaceAll(strings.ToLower(b.DisplayName), " ", "") == compact {
+			return b.Name, true
+		}
+		for _, alias := range b.Aliases {
+			if strings.ToLower(alias) == input {
+				return b.Name, true
 			}
 		}
 	}
 
-	// Try key color
-	if keyType, ok := ColorToKey[input]; ok {
-		// Find the first boss that uses this key type
-		// If multiple bosses use the same key, return the first one alphabetically
-		var matchingBosses []string
-		for boss, key := range BossToKey {
-			if key == keyType {
-				matchingBosses = append(matchingBosses, boss)
+	// Try single letter (first letter of boss name), preferring bosses over raids
+	if len(input) == 1 {
+		for _, b := range bosses {
+			if !b.Raid && strings.HasPrefix(b.Name, input) {
+				return b.Name, true
 			}
 		}
-		if len(matchingBosses) > 0 {
-			// Return the first one alphabetically for consistency
-			// In practice, if multiple bosses share a key, user should be more specific
-			bestBoss := matchingBosses[0]
-			for _, boss := range matchingBosses[1:] {
-				if boss < bestBoss {
-					bestBoss = boss
-				}
-			}
-			return bestBoss, true
+	}
+
+	// Try key color. If multiple bosses use the same key, return the first one alphabetically
+	// for consistency; in practice the user should be more specific
+	var matchingBosses []string
+	for _, b := range bosses {
+		if b.KeyColor != "" && strings.ToLower(b.KeyColor) == input {
+			matchingBosses = append(matchingBosses, b.Name)
 		}
 	}
+	if len(matchingBosses) > 0 {
+		sort.Strings(matchingBosses)
+		return matchingBosses[0], true
+	}
 
 	return "", false
 }
@@ -115,17 +252,24 @@ func ResolveBossName(input string) (string, bool) {
 // 5. Prefix match on color name (e.g., "br" -> "brown" -> "mountain")
 func ResolveKeyType(input string) (string, bool) {
 	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return "", false
+	}
+
+	bosses := Bosses()
 
 	// 1. Check if it's a valid key type directly
-	for _, key := range BossToKey {
-		if key == input {
-			return key, true
+	for _, b := range bosses {
+		if b.KeyType != "" && b.KeyType == input {
+			return b.KeyType, true
 		}
 	}
 
 	// 2. Check if it's a valid color name
-	if key, ok := ColorToKey[input]; ok {
-		return key, true
+	for _, b := range bosses {
+		if b.KeyType != "" && strings.ToLower(b.KeyColor) == input {
+			return b.KeyType, true
+		}
 	}
 
 	// 3. Check if it resolves to a boss name
@@ -136,19 +280,16 @@ func ResolveKeyType(input string) (string, bool) {
 	}
 
 	// 4. Prefix match on key type
-	for _, key := range BossToKey {
-		if strings.HasPrefix(key, input) {
-			return key, true
+	for _, b := range bosses {
+		if b.KeyType != "" && strings.HasPrefix(b.KeyType, input) {
+			return b.KeyType, true
 		}
 	}
 
 	// 5. Prefix match on color name
-	for color, key := range ColorToKey {
-		// ColorToKey keys are already lowercased in init()
-		// But wait, ColorToKey keys are colors, values are key types
-		// The map is color -> key
-		if strings.HasPrefix(color, input) {
-			return key, true
+	for _, b := range bosses {
+		if b.KeyType != "" && b.KeyColor != "" && strings.HasPrefix(strings.ToLower(b.KeyColor), input) {
+			return b.KeyType, true
 		}
 	}
 
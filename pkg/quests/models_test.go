@@ -0,0 +1,123 @@
+package quests
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestResolveBossName(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{input: "griffin", want: "griffin", wantOK: true},
+		{input: "  Griffin ", want: "griffin", wantOK: true},
+		{input: "Reckoning of the Gods", want: "reckoningofthegods", wantOK: true},
+		{input: "guardians of the citadel", want: "guardiansofthecitadel", wantOK: true},
+		{input: "rotg", want: "reckoningofthegods", wantOK: true},
+		{input: "GOTC", want: "guardiansofthecitadel", wantOK: true},
+		{input: "g", want: "griffin", wantOK: true},   // Bosses before raids
+		{input: "d", want: "devil", wantOK: true},     // First in catalog order
+		{input: "red", want: "devil", wantOK: true},   // Key color
+		{input: "book", want: "kronos", wantOK: true}, // Key color that isn't a color
+		{input: "r"},
+		{input: "nobody"},
+		{input: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ResolveBossName(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ResolveBossName(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestResolveKeyType(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{input: "mountain", want: "mountain", wantOK: true},
+		{input: "Brown", want: "mountain", wantOK: true},
+		{input: "griffin", want: "mountain", wantOK: true},
+		{input: "book", want: "kronos", wantOK: true},
+		{input: "und", want: "underworld", wantOK: true}, // Key type prefix
+		{input: "gra", want: "stone", wantOK: true},      // Key color prefix
+		{input: "rotg"}, // Raids need no key
+		{input: "nobody"},
+		{input: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ResolveKeyType(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ResolveKeyType(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestResolveEditedCatalog(t *testing.T) {
+	bosses := append([]Boss{}, defaultBosses...)
+	bosses = append(bosses, Boss{
+		Name: "cerberus", DisplayName: "Cerberus", Aliases: pq.StringArray{"dog"},
+		KeyType: "burning", KeyColor: "red", MinPartySize: 1, MaxPartySize: 3, SortOrder: 12,
+	})
+	setBossCatalog(bosses)
+	t.Cleanup(func() { setBossCatalog(defaultBosses) })
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "cerberus", want: "cerberus"},
+		{input: "dog", want: "cerberus"},
+		{input: "c", want: "chimera"},    // Earlier in catalog order
+		{input: "red", want: "cerberus"}, // Shared key color resolves alphabetically
+	}
+	for _, tt := range tests {
+		if got, ok := ResolveBossName(tt.input); got != tt.want || !ok {
+			t.Errorf("ResolveBossName(%q) = %q, %v, want %q", tt.input, got, ok, tt.want)
+		}
+	}
+	if got, ok := ResolveKeyType("dog"); got != "burning" || !ok {
+		t.Errorf("ResolveKeyType(\"dog\") = %q, %v, want \"burning\"", got, ok)
+	}
+}
+
+func TestNormalizeBoss(t *testing.T) {
+	got, err := normalizeBoss(Boss{Name: " Cerberus ", KeyType: " Burning ", KeyColor: "Red", Aliases: pq.StringArray{" Dog ", ""}})
+	if err != nil {
+		t.Fatalf("normalizeBoss() returned error: %v", err)
+	}
+	want := Boss{Name: "cerberus", DisplayName: "Cerberus", Aliases: pq.StringArray{"dog"}, KeyType: "burning", KeyColor: "red", MinPartySize: 1, MaxPartySize: PartySize}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeBoss() = %+v, want %+v", got, want)
+	}
+
+	invalid := []struct {
+		name string
+		boss Boss
+	}{
+		{name: "name with spaces", boss: Boss{Name: "big dog"}},
+		{name: "empty name", boss: Boss{}},
+		{name: "key color without key type", boss: Boss{Name: "cerberus", KeyColor: "red"}},
+		{name: "alias of another boss", boss: Boss{Name: "cerberus", Aliases: pq.StringArray{"rotg"}}},
+		{name: "max below min", boss: Boss{Name: "cerberus", MinPartySize: 3, MaxPartySize: 2}},
+		{name: "max above the cap", boss: Boss{Name: "cerberus", MaxPartySize: MaxBossPartySize + 1}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := normalizeBoss(tt.boss); !errors.Is(err, ErrInvalidBoss) {
+				t.Errorf("normalizeBoss(%+v) error = %v, want ErrInvalidBoss", tt.boss, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package quests
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// DefaultSearchBudget is how long the planner searches for a better plan than the greedy one
+const DefaultSearchBudget = 2 * time.Second
+
+// limitViolationCost is added to a party's cost for each boss whose party size limit it breaks,
+// so the search only breaks a limit when no grouping can avoid it
+const limitViolationCost = 1e6
+
+// PlanObjective weighs what the plan optimizer minimizes. Each party is scored on its own since
+// a player is only ever in one party, and the plan's score is the sum over its parties.
+type PlanObjective struct {
+	Parties   float64 `json:"parties"`   // Cost of each party formed
+	KeysSpent float64 `json:"keys"`      // Cost of each key used
+	Uncovered float64 `json:"uncovered"` // Cost of each quest kill the party has no key for
+}
+
+// DefaultPlanObjective covers as many kills as possible first, then uses as few parties and keys as it can
+func DefaultPlanObjective() PlanObjective {
+	return PlanObjective{
+		Parties:   10,
+		KeysSpent: 1,
+		Uncovered: 100,
+	}
+}
+
+// ParsePlanObjective parses weights such as "parties=10,keys=1,uncovered=100".
+// Weights that aren't given keep their default.
+func ParsePlanObjective(input string) (PlanObjective, error) {
+	objective := DefaultPlanObjective()
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return objective, fmt.Errorf("invalid objective weight %q, expected name=value", part)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 {
+			return objective, fmt.Errorf("invalid weight for %s: %s", name, value)
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "parties":
+			objective.Parties = weight
+		case "keys":
+			objective.KeysSpent = weight
+		case "uncovered":
+			objective.Uncovered = weight
+		default:
+			return objective, fmt.Errorf("unknown objective weight: %s (use parties, keys or uncovered)", name)
+		}
+	}
+	return objective, nil
+}
+
+// planSearch is a branch-and-bound search over the ways to split players into parties.
+// Players are indexed with the players who have needs first, then the helpers.
+type planSearch struct {
+	ctx       context.Context
+	objective PlanObjective
+	deadline  time.Time
+
+	names    []string
+	numNeedy int
+	maxSize  int
+	useful   []bool           // Players worth adding to a party
+	needs    [][]int          // Player -> boss -> kills needed
+	keys     [][]int          // Player -> boss -> keys for the boss
+	limits   []PartySizeLimit // Boss -> party size limit
+	keyless  []bool           // Boss -> true if it doesn't need a key
+	minShare []float64
+
+	used     []bool
+	current  [][]int
+	best     [][]int
+	bestCost float64
+	nodes    int
+	expired  bool
+}
+
+// optimizeGroups searches for the grouping of players into parties that minimizes the planner's
+// objective. The greedy groups are the starting point and are returned when the search can't
+// beat them, including when the time budget runs out before a better grouping is found.
+func (p *Planner) optimizeGroups(ctx context.Context, playersWithNeeds, helpers []*PlayerProfile, profiles map[string]*PlayerProfile, greedy [][]string) [][]string {
+	l := ctxzap.Extract(ctx)
+
+	if len(playersWithNeeds) == 0 {
+		return greedy
+	}
+
+	s := newPlanSearch(ctx, p.objective, playersWithNeeds, helpers, time.Now().Add(p.searchBudget))
+
+	index := make(map[string]int, len(s.names))
+	for i, name := range s.names {
+		index[name] = i
+	}
+	s.bestCost = 0
+	for _, group := range greedy {
+		members := make([]int, 0, len(group))
+		for _, name := range group {
+			members = append(members, index[name])
+		}
+		s.bestCost += s.groupCost(members)
+	}
+	greedyCost := s.bestCost
+
+	if s.computeMinShares() {
+		remShare := 0.0
+		for i := 0; i < s.numNeedy; i++ {
+			remShare += s.minShare[i]
+		}
+		s.search(0, remShare, s.numNeedy)
+	}
+
+	l.Debug("Searched for party plan",
+		zap.Int("nodes", s.nodes),
+		zap.Bool("complete", !s.expired),
+		zap.Float64("greedy_cost", greedyCost),
+		zap.Float64("best_cost", s.bestCost))
+
+	if s.best == nil {
+		return greedy
+	}
+	groups := make([][]string, 0, len(s.best))
+	for _, members := range s.best {
+		group := make([]string, 0, len(members))
+		for _, m := range members {
+			group = append(group, s.names[m])
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func newPlanSearch(ctx context.Context, objective PlanObjective, playersWithNeeds, helpers []*PlayerProfile, deadline time.Time) *planSearch {
+	var bosses []string
+	seenBosses := make(map[string]bool)
+	for _, prof := range playersWithNeeds {
+		for boss, need := range prof.Needs {
+			if need > 0 && !seenBosses[boss] {
+				seenBosses[boss] = true
+				bosses = append(bosses, boss)
+			}
+		}
+	}
+	sort.Strings(bosses)
+
+	s := &planSearch{
+		ctx:       ctx,
+		objective: objective,
+		deadline:  deadline,
+		numNeedy:  len(playersWithNeeds),
+		maxSize:   1,
+	}
+	// Helpers without keys only help fill parties for bosses with a minimum party size
+	needsFillers := false
+	for _, boss := range bosses {
+		limit := GetPartySizeLimit(boss)
+		s.limits = append(s.limits, limit)
+		needsFillers = needsFillers || limit.Min > 1
+		if limit.Max > s.maxSize {
+			s.maxSize = limit.Max
+		}
+		_, needsKey := GetKeyForBoss(boss)
+		s.keyless = append(s.keyless, !needsKey && IsValidBoss(boss))
+	}
+
+	players := append(append([]*PlayerProfile{}, playersWithNeeds...), helpers...)
+	for i, prof := range players {
+		needs := make([]int, len(bosses))
+		keys := make([]int, len(bosses))
+		hasKeys := false
+		for b, boss := range bosses {
+			needs[b] = prof.Needs[boss]
+			keyType, _ := GetKeyForBoss(boss)
+			keys[b] = prof.Keys[keyType]
+			if keys[b] > 0 {
+				hasKeys = true
+			}
+		}
+		s.names = append(s.names, prof.Name)
+		s.needs = append(s.needs, needs)
+		s.keys = append(s.keys, keys)
+		s.useful = append(s.useful, i < s.numNeedy || hasKeys || needsFillers)
+	}
+	s.used = make([]bool, len(players))
+	s.minShare = make([]float64, s.numNeedy)
+	return s
+}
+
+// groupCost scores a single party on the objective, the same way buildParty assigns its tasks
+func (s *planSearch) groupCost(members []int) float64 {
+	cost := s.objective.Parties
+	for b := range s.needs[0] {
+		maxNeed, keys := 0, 0
+		for _, m := range members {
+			if s.needs[m][b] > maxNeed {
+				maxNeed = s.needs[m][b]
+			}
+			keys += s.keys[m][b]
+		}
+		if maxNeed == 0 {
+			continue
+		}
+		if !s.limits[b].Allows(len(members)) {
+			cost += limitViolationCost
+		}
+
+		if s.keyless[b] {
+			continue
+		}
+
+		kills := maxNeed
+		if keys < kills {
+			kills = keys
+		}
+		cost += s.objective.KeysSpent * float64(kills)
+		for _, m := range members {
+			if uncovered := s.needs[m][b] - kills; uncovered > 0 {
+				cost += s.objective.Uncovered * float64(uncovered)
+			}
+		}
+	}
+	return cost
+}
+
+// computeMinShares finds, for each player with needs, the smallest share of a party's cost they
+// can carry, splitting each party's cost evenly between its players with needs. The sum over the
+// unassigned players is a lower bound on the cost of the parties still to form.
+// Returns false if the time budget ran out.
+func (s *planSearch) computeMinShares() bool {
+	for a := 0; a < s.numNeedy; a++ {
+		s.minShare[a] = -1
+	}
+
+	for a := 0; a < s.numNeedy; a++ {
+		var partners []int
+		for i := a + 1; i < len(s.names); i++ {
+			if s.useful[i] {
+				partners = append(partners, i)
+			}
+		}
+
+		completed := forEachCombination(partners, s.maxSize-1, func(combination []int) bool {
+			if s.checkExpired() {
+				return false
+			}
+			members := append([]int{a}, combination...)
+			cost := s.groupCost(members)
+			needy := 0
+			for _, m := range members {
+				if m < s.numNeedy {
+					needy++
+				}
+			}
+			share := cost / float64(needy)
+			for _, m := range members {
+				if m < s.numNeedy && (s.minShare[m] < 0 || share < s.minShare[m]) {
+					s.minShare[m] = share
+				}
+			}
+			return true
+		})
+		if !completed {
+			return false
+		}
+	}
+	return true
+}
+
+// search forms a party around the first unassigned player with needs and recurses,
+// pruning branches whose lower bound can't beat the best plan found so far
+func (s *planSearch) search(cost, remShare float64, remaining int) {
+	if s.checkExpired() {
+		return
+	}
+
+	anchor := -1
+	for i := 0; i < s.numNeedy; i++ {
+		if !s.used[i] {
+			anchor = i
+			break
+		}
+	}
+	if anchor < 0 {
+		if cost < s.bestCost {
+			s.bestCost = cost
+			s.best = make([][]int, len(s.current))
+			for i, members := range s.current {
+				s.best[i] = append([]int{}, members...)
+			}
+		}
+		return
+	}
+
+	var partners []int
+	for i := anchor + 1; i < len(s.names); i++ {
+		if !s.used[i] && s.useful[i] {
+			partners = append(partners, i)
+		}
+	}
+
+	type candidate struct {
+		members []int
+		cost    float64
+		share   float64 // Lower-bound shares of its players with needs
+		needy   int
+	}
+	// The candidates grow as C(partners, maxSize-1), so building them counts against the budget too
+	var candidates []candidate
+	completed := forEachCombination(partners, s.maxSize-1, func(combination []int) bool {
+		if s.checkExpired() {
+			return false
+		}
+		c := candidate{members: append([]int{anchor}, combination...)}
+		c.cost = s.groupCost(c.members)
+		for _, m := range c.members {
+			if m < s.numNeedy {
+				c.share += s.minShare[m]
+				c.needy++
+			}
+		}
+		candidates = append(candidates, c)
+		return true
+	})
+	if !completed {
+		return
+	}
+
+	// Try the cheapest parties per player first so good plans are found early
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].cost/float64(candidates[i].needy) < candidates[j].cost/float64(candidates[j].needy)
+	})
+
+	for _, c := range candidates {
+		left := remaining - c.needy
+		bound := remShare - c.share
+		if parties := float64((left+s.maxSize-1)/s.maxSize) * s.objective.Parties; parties > bound {
+			bound = parties
+		}
+		if cost+c.cost+bound >= s.bestCost {
+			continue
+		}
+
+		for _, m := range c.members {
+			s.used[m] = true
+		}
+		s.current = append(s.current, c.members)
+		s.search(cost+c.cost, remShare-c.share, left)
+		s.current = s.current[:len(s.current)-1]
+		for _, m := range c.members {
+			s.used[m] = false
+		}
+
+		if s.expired {
+			return
+		}
+	}
+}
+
+// checkExpired reports whether the time budget ran out or the request was cancelled
+func (s *planSearch) checkExpired() bool {
+	if s.expired {
+		return true
+	}
+	s.nodes++
+	if s.nodes%256 == 0 && (time.Now().After(s.deadline) || s.ctx.Err() != nil) {
+		s.expired = true
+	}
+	return s.expired
+}
+
+// forEachCombination calls fn with every combination of up to max items, including the empty one.
+// Stops and returns false as soon as fn returns false.
+func forEachCombination(items []int, max int, fn func([]int) bool) bool {
+	combination := make([]int, 0, max)
+	var walk func(start int) bool
+	walk = func(start int) bool {
+		if !fn(combination) {
+			return false
+		}
+		if len(combination) == max {
+			return true
+		}
+		for i := start; i < len(items); i++ {
+			combination = append(combination, items[i])
+			if !walk(i + 1) {
+				return false
+			}
+			combination = combination[:len(combination)-1]
+		}
+		return true
+	}
+	return walk(0)
+}
@@ -0,0 +1,166 @@
+package quests
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func profile(name string, needs, keys map[string]int) *PlayerProfile {
+	return &PlayerProfile{Name: name, Needs: needs, Keys: keys}
+}
+
+// sortedGroups orders the players in each group and the groups themselves so plans compare equal
+func sortedGroups(groups [][]string) [][]string {
+	out := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		g := append([]string{}, group...)
+		sort.Strings(g)
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i][0] < out[j][0] })
+	return out
+}
+
+func testPlanner(budget time.Duration) *Planner {
+	return &Planner{objective: DefaultPlanObjective(), searchBudget: budget}
+}
+
+func TestOptimizeGroupsBeatsGreedy(t *testing.T) {
+	needy := []*PlayerProfile{
+		profile("alice", map[string]int{"griffin": 2}, nil),
+		profile("bob", map[string]int{"devil": 2}, nil),
+	}
+	helpers := []*PlayerProfile{
+		profile("carol", nil, map[string]int{"mountain": 2}),
+		profile("dave", nil, map[string]int{"burning": 2}),
+	}
+	// Pairing each player with the wrong key holder leaves every kill uncovered: 2 * (10 + 2*100)
+	greedy := [][]string{{"alice", "dave"}, {"bob", "carol"}}
+
+	got := testPlanner(time.Second).optimizeGroups(context.Background(), needy, helpers, nil, greedy)
+
+	// Pairing them with the right key holder covers every kill: 2 * (10 + 2*1)
+	want := [][]string{{"alice", "carol"}, {"bob", "dave"}}
+	if !reflect.DeepEqual(sortedGroups(got), want) {
+		t.Errorf("optimizeGroups() = %v, want %v", got, want)
+	}
+}
+
+func TestOptimizeGroupsBudgetReturnsGreedy(t *testing.T) {
+	// Enough players that the search checks its deadline before finishing
+	var needy []*PlayerProfile
+	var greedy [][]string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		needy = append(needy, profile(name, map[string]int{"griffin": 1}, nil))
+		greedy = append(greedy, []string{name})
+	}
+
+	got := testPlanner(0).optimizeGroups(context.Background(), needy, nil, nil, greedy)
+
+	// Parties of three would be cheaper, but the search has no time to find them
+	if !reflect.DeepEqual(got, greedy) {
+		t.Errorf("optimizeGroups() = %v, want the greedy groups %v", got, greedy)
+	}
+}
+
+func TestSearchStopsBuildingCandidatesWhenExpired(t *testing.T) {
+	withPartySizeLimits(t, map[string]PartySizeLimit{"griffin": {Min: 1, Max: MaxBossPartySize}})
+
+	var needy []*PlayerProfile
+	for i := 0; i < 60; i++ {
+		needy = append(needy, profile(fmt.Sprintf("player%d", i), map[string]int{"griffin": 1}, nil))
+	}
+	s := newPlanSearch(context.Background(), DefaultPlanObjective(), needy, nil, time.Now().Add(-time.Second))
+	s.bestCost = math.Inf(1)
+
+	// The first party alone has C(59, 4) candidates; the search must give up long before that
+	start := time.Now()
+	s.search(0, 0, s.numNeedy)
+	elapsed := time.Since(start)
+
+	if !s.expired {
+		t.Fatal("search() finished, want it to run out of time")
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("search() took %v after its deadline, want it to stop within its next deadline check", elapsed)
+	}
+}
+
+func TestGroupCostKeylessBoss(t *testing.T) {
+	objective := DefaultPlanObjective()
+	tests := []struct {
+		name string
+		boss string
+		want float64
+	}{
+		{name: "raid needs no key", boss: "reckoningofthegods", want: objective.Parties},
+		{name: "boss without keys", boss: "griffin", want: objective.Parties + 3*objective.Uncovered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			needy := []*PlayerProfile{profile("alice", map[string]int{tt.boss: 3}, nil)}
+			s := newPlanSearch(context.Background(), objective, needy, nil, time.Now().Add(time.Second))
+			if got := s.groupCost([]int{0}); got != tt.want {
+				t.Errorf("groupCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptimizeGroupsAvoidsLimitViolations(t *testing.T) {
+	withPartySizeLimits(t, map[string]PartySizeLimit{"griffin": {Min: 1, Max: 1}})
+
+	needy := []*PlayerProfile{
+		profile("alice", map[string]int{"griffin": 1}, map[string]int{"mountain": 1}),
+		profile("bob", map[string]int{"devil": 1}, map[string]int{"burning": 1}),
+		profile("carol", map[string]int{"devil": 1}, map[string]int{"burning": 1}),
+	}
+	// One party would be cheapest (10 + 1 + 1) if griffin allowed three players
+	greedy := [][]string{{"alice", "bob", "carol"}}
+
+	got := testPlanner(time.Second).optimizeGroups(context.Background(), needy, nil, nil, greedy)
+
+	want := [][]string{{"alice"}, {"bob", "carol"}}
+	if !reflect.DeepEqual(sortedGroups(got), want) {
+		t.Errorf("optimizeGroups() = %v, want %v", got, want)
+	}
+}
+
+func TestParsePlanObjective(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    PlanObjective
+		wantErr bool
+	}{
+		{name: "empty keeps defaults", input: "", want: DefaultPlanObjective()},
+		{name: "all weights", input: "parties=5,keys=2,uncovered=50", want: PlanObjective{Parties: 5, KeysSpent: 2, Uncovered: 50}},
+		{name: "partial with spaces and case", input: " KEYS = 3 , ", want: PlanObjective{Parties: 10, KeysSpent: 3, Uncovered: 100}},
+		{name: "missing value", input: "parties", wantErr: true},
+		{name: "not a number", input: "keys=lots", wantErr: true},
+		{name: "negative weight", input: "uncovered=-1", wantErr: true},
+		{name: "unknown weight", input: "speed=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlanObjective(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParsePlanObjective(%q) = %+v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePlanObjective(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePlanObjective(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,15 +4,53 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 )
 
+// PartySize is the number of players the planner groups into each party, for bosses without
+// their own party size limit
+const PartySize = 3
+
 // Planner generates a party plan for boss quests
 type Planner struct {
-	db *DB
+	db             *DB
+	fairnessWeight float64
+	objective      PlanObjective
+	searchBudget   time.Duration
 }
 
 func NewPlanner(db *DB) *Planner {
-	return &Planner{db: db}
+	return &Planner{
+		db:           db,
+		objective:    DefaultPlanObjective(),
+		searchBudget: DefaultSearchBudget,
+	}
+}
+
+// SetFairnessWeight makes the planner spread key usage across holders. Each key a player has
+// contributed on net (from the key ledger, plus keys spent earlier in the plan) counts as
+// weight fewer keys when choosing helpers and key holders. 0 (the default) always picks the
+// richest holder, which needs the fewest parties; higher values favor fairness over fewer parties.
+func (p *Planner) SetFairnessWeight(weight float64) {
+	if weight < 0 {
+		weight = 0
+	}
+	p.fairnessWeight = weight
+}
+
+// SetObjective sets what the optimizer minimizes when grouping players into parties
+func (p *Planner) SetObjective(objective PlanObjective) {
+	p.objective = objective
+}
+
+// SetSearchBudget sets how long the optimizer may search for a better plan than the greedy one.
+// 0 disables the search and always uses the greedy plan.
+func (p *Planner) SetSearchBudget(budget time.Duration) {
+	if budget < 0 {
+		budget = 0
+	}
+	p.searchBudget = budget
 }
 
 // PlayerProfile tracks a player's needs and available keys
@@ -21,12 +59,21 @@ type PlayerProfile struct {
 	Needs     map[string]int // Boss -> Kills needed
 	Keys      map[string]int // KeyType -> Count
 	TotalNeed int            // Total kills needed
+
+	Unmet map[string]Explanation // Boss -> why the remaining kills weren't planned (leftovers only)
 }
 
 // Party represents a group of players assigned to tasks
 type Party struct {
-	Players []string
-	Tasks   []PartyTask
+	Players    []string
+	Tasks      []PartyTask
+	Violations []PartyLimitViolation // Bosses whose party size limit the party breaks
+}
+
+// PartyLimitViolation is a boss a party is too small or too large for
+type PartyLimitViolation struct {
+	BossName string
+	Limit    PartySizeLimit
 }
 
 // PartyTask represents a boss assignment for a party
@@ -36,6 +83,8 @@ type PartyTask struct {
 	KeyHolder string // Player providing the key (empty if no keys available)
 	KeyType   string
 	NoKeys    bool // True if no keys available for this task
+
+	Explanation Explanation // Why the task is planned this way
 }
 
 // PlanResult contains the generated plan
@@ -106,6 +155,22 @@ func (p *Planner) GeneratePlanFiltered(ctx context.Context, weekNumber, year int
 		profiles[k.PlayerName].Keys[k.KeyType] = k.Count
 	}
 
+	// Net keys each player has contributed to others, lowercase name -> keys
+	burden := make(map[string]float64)
+	if p.fairnessWeight > 0 {
+		balances, err := p.db.GetKeyLedgerBalances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get key ledger balances: %w", err)
+		}
+		for _, b := range balances {
+			burden[strings.ToLower(b.PlayerName)] = b.Net
+		}
+	}
+	// keyScore discounts a player's keys by the keys they've already contributed
+	keyScore := func(name string, keys int) float64 {
+		return float64(keys) - p.fairnessWeight*burden[strings.ToLower(name)]
+	}
+
 	// Separate players into those with needs and available helpers
 	// If filtering by online, only include online players
 	var playersWithNeeds []*PlayerProfile
@@ -129,7 +194,8 @@ func (p *Planner) GeneratePlanFiltered(ctx context.Context, weekNumber, year int
 		return playersWithNeeds[i].TotalNeed > playersWithNeeds[j].TotalNeed
 	})
 
-	// Sort helpers by total keys descending (most useful helpers first)
+	// Sort helpers by total keys descending (most useful helpers first),
+	// discounted by past contributions when fairness is enabled
 	sort.Slice(availableHelpers, func(i, j int) bool {
 		totalKeysI := 0
 		for _, c := range availableHelpers[i].Keys {
@@ -139,206 +205,239 @@ func (p *Planner) GeneratePlanFiltered(ctx context.Context, weekNumber, year int
 		for _, c := range availableHelpers[j].Keys {
 			totalKeysJ += c
 		}
-		return totalKeysI > totalKeysJ
+		return keyScore(availableHelpers[i].Name, totalKeysI) > keyScore(availableHelpers[j].Name, totalKeysJ)
 	})
 
-	// Track which players have been assigned to parties
-	assigned := make(map[string]bool)
+	// 4. Party Formation - group players greedily, then search for a grouping that
+	// scores better on the objective within the time budget
+	groups := greedyGroups(playersWithNeeds, availableHelpers, profiles)
+	if p.searchBudget > 0 {
+		groups = p.optimizeGroups(ctx, playersWithNeeds, availableHelpers, profiles, groups)
+	}
+
+	explainer := newPlanExplainer(profiles, func(name string) bool {
+		return !filterByOnline || onlineSet[name]
+	})
 
-	// 4. Party Formation (Greedy) - always try to form parties of 3
 	var parties []Party
+	for _, group := range groups {
+		party := buildParty(group, profiles, keyScore, burden)
 
-	for len(playersWithNeeds) > 0 {
-		// Find the best group starting with the player with highest need
-		bestGroup := findBestGroupWithHelpers(playersWithNeeds, availableHelpers, profiles, assigned)
-		if len(bestGroup.Players) == 0 {
-			break
+		// Add party to plan (always, even if no keys)
+		if len(party.Tasks) > 0 {
+			parties = append(parties, party)
 		}
+	}
 
-		party := Party{
-			Players: bestGroup.Players,
-			Tasks:   make([]PartyTask, 0),
+	// Gather leftovers (players with remaining needs)
+	var leftovers []PlayerProfile
+	for _, prof := range profiles {
+		total := 0
+		for _, n := range prof.Needs {
+			total += n
+		}
+		if total > 0 {
+			prof.TotalNeed = total
+			leftovers = append(leftovers, *prof)
 		}
+	}
+
+	explainer.explain(parties, leftovers)
+
+	return &PlanResult{
+		Parties:   parties,
+		Leftovers: leftovers,
+	}, nil
+}
 
-		// Mark players as assigned for this round
+// greedyGroups forms full parties one at a time around the unassigned player with the highest need
+func greedyGroups(playersWithNeeds, helpers []*PlayerProfile, profiles map[string]*PlayerProfile) [][]string {
+	// Track which players have been assigned to parties
+	assigned := make(map[string]bool)
+
+	var groups [][]string
+	for {
+		bestGroup := findBestGroupWithHelpers(playersWithNeeds, helpers, profiles, assigned)
+		if len(bestGroup.Players) == 0 {
+			break
+		}
 		for _, pName := range bestGroup.Players {
 			assigned[pName] = true
 		}
+		groups = append(groups, bestGroup.Players)
+	}
+	return groups
+}
 
-		// Assign tasks for this group - for ALL bosses that anyone in the group needs
-		bossesNeeded := make(map[string]int) // boss -> max kills needed
-		for _, pName := range bestGroup.Players {
-			for boss, need := range profiles[pName].Needs {
-				if need > 0 {
-					if need > bossesNeeded[boss] {
-						bossesNeeded[boss] = need
-					}
+// buildParty assigns the tasks of a party: every boss anyone in it needs, paid for with the
+// party's keys. The keys used and kills done are deducted from the profiles.
+func buildParty(players []string, profiles map[string]*PlayerProfile, keyScore func(string, int) float64, burden map[string]float64) Party {
+	party := Party{
+		Players: players,
+		Tasks:   make([]PartyTask, 0),
+	}
+
+	// Assign tasks for this group - for ALL bosses that anyone in the group needs
+	bossesNeeded := make(map[string]int) // boss -> max kills needed
+	for _, pName := range players {
+		for boss, need := range profiles[pName].Needs {
+			if need > 0 {
+				if need > bossesNeeded[boss] {
+					bossesNeeded[boss] = need
 				}
 			}
 		}
+	}
 
-		// Sort bosses for consistent output
-		var bossList []string
-		for boss := range bossesNeeded {
-			bossList = append(bossList, boss)
-		}
-		sort.Strings(bossList)
+	// Sort bosses for consistent output
+	var bossList []string
+	for boss := range bossesNeeded {
+		bossList = append(bossList, boss)
+	}
+	sort.Strings(bossList)
 
-		for _, boss := range bossList {
-			killsNeeded := bossesNeeded[boss]
+	for _, boss := range bossList {
+		killsNeeded := bossesNeeded[boss]
 
-			// Check for keys within the party
-			keyType, _ := GetKeyForBoss(boss)
-			keysAvailable := 0
-			for _, pName := range bestGroup.Players {
-				keysAvailable += profiles[pName].Keys[keyType]
-			}
+		// Check for keys within the party
+		keyType, needsKey := GetKeyForBoss(boss)
+		keysAvailable := 0
+		for _, pName := range players {
+			keysAvailable += profiles[pName].Keys[keyType]
+		}
 
-			if keysAvailable > 0 {
-				// Use available keys
-				killsToDo := killsNeeded
-				if keysAvailable < killsToDo {
-					killsToDo = keysAvailable
+		if !needsKey && IsValidBoss(boss) {
+			// Bosses and raids without keys only need the kills
+			party.Tasks = append(party.Tasks, PartyTask{
+				BossName: boss,
+				Kills:    killsNeeded,
+			})
+			for _, pName := range players {
+				if profiles[pName].Needs[boss] > 0 {
+					profiles[pName].Needs[boss] -= killsNeeded
+					if profiles[pName].Needs[boss] < 0 {
+						profiles[pName].Needs[boss] = 0
+					}
 				}
+			}
+		} else if keysAvailable > 0 {
+			// Use available keys
+			killsToDo := killsNeeded
+			if keysAvailable < killsToDo {
+				killsToDo = keysAvailable
+			}
+
+			// Assign key holder(s)
+			remainingKillsToPay := killsToDo
 
-				// Assign key holder(s)
-				remainingKillsToPay := killsToDo
+			// Sort players by key count descending, discounted by past contributions
+			payers := make([]string, len(players))
+			copy(payers, players)
+			sort.Slice(payers, func(i, j int) bool {
+				return keyScore(payers[i], profiles[payers[i]].Keys[keyType]) > keyScore(payers[j], profiles[payers[j]].Keys[keyType])
+			})
 
-				// Sort players by key count descending
-				payers := make([]string, len(bestGroup.Players))
-				copy(payers, bestGroup.Players)
-				sort.Slice(payers, func(i, j int) bool {
-					return profiles[payers[i]].Keys[keyType] > profiles[payers[j]].Keys[keyType]
-				})
+			for _, payer := range payers {
+				if remainingKillsToPay <= 0 {
+					break
+				}
 
-				for _, payer := range payers {
-					if remainingKillsToPay <= 0 {
-						break
+				has := profiles[payer].Keys[keyType]
+				if has > 0 {
+					pay := has
+					if pay > remainingKillsToPay {
+						pay = remainingKillsToPay
 					}
 
-					has := profiles[payer].Keys[keyType]
-					if has > 0 {
-						pay := has
-						if pay > remainingKillsToPay {
-							pay = remainingKillsToPay
+					// Record task
+					party.Tasks = append(party.Tasks, PartyTask{
+						BossName:  boss,
+						Kills:     pay,
+						KeyHolder: payer,
+						KeyType:   keyType,
+					})
+
+					// Deduct keys
+					profiles[payer].Keys[keyType] -= pay
+					remainingKillsToPay -= pay
+
+					// Split the keys between the party like the ledger does, so later
+					// tasks in this plan favor the other holders
+					share := float64(pay) / float64(len(players))
+					for _, pName := range players {
+						if pName != payer {
+							burden[strings.ToLower(payer)] += share
+							burden[strings.ToLower(pName)] -= share
 						}
-
-						// Record task
-						party.Tasks = append(party.Tasks, PartyTask{
-							BossName:  boss,
-							Kills:     pay,
-							KeyHolder: payer,
-							KeyType:   keyType,
-						})
-
-						// Deduct keys
-						profiles[payer].Keys[keyType] -= pay
-						remainingKillsToPay -= pay
 					}
 				}
+			}
 
-				// Deduct needs for everyone in party
-				for _, pName := range bestGroup.Players {
-					if profiles[pName].Needs[boss] > 0 {
-						profiles[pName].Needs[boss] -= killsToDo
-						if profiles[pName].Needs[boss] < 0 {
-							profiles[pName].Needs[boss] = 0
-						}
+			// Deduct needs for everyone in party
+			for _, pName := range players {
+				if profiles[pName].Needs[boss] > 0 {
+					profiles[pName].Needs[boss] -= killsToDo
+					if profiles[pName].Needs[boss] < 0 {
+						profiles[pName].Needs[boss] = 0
 					}
 				}
-			} else {
-				// No keys available - still record the task but mark as needing keys
-				party.Tasks = append(party.Tasks, PartyTask{
-					BossName: boss,
-					Kills:    killsNeeded,
-					KeyType:  keyType,
-					NoKeys:   true,
-				})
-
-				// Still deduct needs (they'll figure out keys)
-				for _, pName := range bestGroup.Players {
-					if profiles[pName].Needs[boss] > 0 {
-						profiles[pName].Needs[boss] -= killsNeeded
-						if profiles[pName].Needs[boss] < 0 {
-							profiles[pName].Needs[boss] = 0
-						}
+			}
+		} else {
+			// No keys available - still record the task but mark as needing keys
+			party.Tasks = append(party.Tasks, PartyTask{
+				BossName: boss,
+				Kills:    killsNeeded,
+				KeyType:  keyType,
+				NoKeys:   true,
+			})
+
+			// Still deduct needs (they'll figure out keys)
+			for _, pName := range players {
+				if profiles[pName].Needs[boss] > 0 {
+					profiles[pName].Needs[boss] -= killsNeeded
+					if profiles[pName].Needs[boss] < 0 {
+						profiles[pName].Needs[boss] = 0
 					}
 				}
 			}
 		}
+	}
 
-		// Sort tasks by key holder so each player uses all their keys contiguously
-		// This minimizes the number of times the party leader needs to swap key providers
-		sort.Slice(party.Tasks, func(i, j int) bool {
-			// Kronos always goes last
-			if party.Tasks[i].BossName == "kronos" {
-				return false
-			}
-			if party.Tasks[j].BossName == "kronos" {
-				return true
-			}
-			// NoKeys tasks go last (but before kronos)
-			if party.Tasks[i].NoKeys != party.Tasks[j].NoKeys {
-				return !party.Tasks[i].NoKeys
-			}
-			// Sort by key holder name
-			if party.Tasks[i].KeyHolder != party.Tasks[j].KeyHolder {
-				return party.Tasks[i].KeyHolder < party.Tasks[j].KeyHolder
-			}
-			// Within same key holder, sort by boss name for consistency
-			return party.Tasks[i].BossName < party.Tasks[j].BossName
-		})
-
-		// Add party to plan (always, even if no keys)
-		if len(party.Tasks) > 0 {
-			parties = append(parties, party)
+	// Sort tasks by key holder so each player uses all their keys contiguously
+	// This minimizes the number of times the party leader needs to swap key providers
+	sort.Slice(party.Tasks, func(i, j int) bool {
+		// Kronos always goes last
+		if party.Tasks[i].BossName == "kronos" {
+			return false
 		}
-
-		// Re-evaluate players with needs
-		var newPlayersWithNeeds []*PlayerProfile
-		for _, prof := range playersWithNeeds {
-			total := 0
-			for _, n := range prof.Needs {
-				total += n
-			}
-			prof.TotalNeed = total
-			if total > 0 && !assigned[prof.Name] {
-				newPlayersWithNeeds = append(newPlayersWithNeeds, prof)
-			}
+		if party.Tasks[j].BossName == "kronos" {
+			return true
 		}
-		playersWithNeeds = newPlayersWithNeeds
-
-		// Re-sort
-		sort.Slice(playersWithNeeds, func(i, j int) bool {
-			return playersWithNeeds[i].TotalNeed > playersWithNeeds[j].TotalNeed
-		})
-
-		// Update available helpers (remove assigned ones)
-		var newHelpers []*PlayerProfile
-		for _, h := range availableHelpers {
-			if !assigned[h.Name] {
-				newHelpers = append(newHelpers, h)
-			}
+		// NoKeys tasks go last (but before kronos)
+		if party.Tasks[i].NoKeys != party.Tasks[j].NoKeys {
+			return !party.Tasks[i].NoKeys
 		}
-		availableHelpers = newHelpers
-	}
+		// Sort by key holder name
+		if party.Tasks[i].KeyHolder != party.Tasks[j].KeyHolder {
+			return party.Tasks[i].KeyHolder < party.Tasks[j].KeyHolder
+		}
+		// Within same key holder, sort by boss name for consistency
+		return party.Tasks[i].BossName < party.Tasks[j].BossName
+	})
 
-	// Gather leftovers (players with remaining needs)
-	var leftovers []PlayerProfile
-	for _, prof := range profiles {
-		total := 0
-		for _, n := range prof.Needs {
-			total += n
+	// Mark the bosses the party is too small or too large for
+	seenBosses := make(map[string]bool)
+	for _, task := range party.Tasks {
+		if seenBosses[task.BossName] {
+			continue
 		}
-		if total > 0 {
-			leftovers = append(leftovers, *prof)
+		seenBosses[task.BossName] = true
+		if limit := GetPartySizeLimit(task.BossName); !limit.Allows(len(players)) {
+			party.Violations = append(party.Violations, PartyLimitViolation{BossName: task.BossName, Limit: limit})
 		}
 	}
 
-	return &PlanResult{
-		Parties:   parties,
-		Leftovers: leftovers,
-	}, nil
+	return party
 }
 
 type GroupCandidate struct {
@@ -347,7 +446,35 @@ type GroupCandidate struct {
 	Score    int
 }
 
-// findBestGroupWithHelpers finds a group of 3 players, prioritizing overlap but filling with helpers
+// MaxPartySize returns the largest party any boss allows
+func MaxPartySize() int {
+	max := PartySize
+	for _, b := range Bosses() {
+		if b.MaxPartySize > max {
+			max = b.MaxPartySize
+		}
+	}
+	return max
+}
+
+// needsSizeLimit narrows a party size limit to the limits of every boss the player needs
+func needsSizeLimit(prof *PlayerProfile, limit PartySizeLimit) PartySizeLimit {
+	for boss, need := range prof.Needs {
+		if need <= 0 {
+			continue
+		}
+		bossLimit := GetPartySizeLimit(boss)
+		if bossLimit.Min > limit.Min {
+			limit.Min = bossLimit.Min
+		}
+		if bossLimit.Max < limit.Max {
+			limit.Max = bossLimit.Max
+		}
+	}
+	return limit
+}
+
+// findBestGroupWithHelpers finds a full party of players, prioritizing overlap but filling with helpers
 func findBestGroupWithHelpers(playersWithNeeds, helpers []*PlayerProfile, allProfiles map[string]*PlayerProfile, assigned map[string]bool) GroupCandidate {
 	// Filter out already assigned players
 	var available []*PlayerProfile
@@ -401,14 +528,24 @@ func findBestGroupWithHelpers(playersWithNeeds, helpers []*PlayerProfile, allPro
 		return partners[i].Score > partners[j].Score
 	})
 
-	// Take up to 2 partners from players with needs
-	for i := 0; i < 2 && i < len(partners); i++ {
-		candidate.Players = append(candidate.Players, partners[i].Name)
+	// Fill the party with partners from players with needs, as long as the party stays
+	// within the size limits of every boss its players need
+	limit := needsSizeLimit(anchor, PartySizeLimit{Min: 1, Max: MaxPartySize()})
+	for _, partner := range partners {
+		if len(candidate.Players) >= limit.Max {
+			break
+		}
+		combined := needsSizeLimit(allProfiles[partner.Name], limit)
+		if len(candidate.Players) >= combined.Max {
+			continue
+		}
+		candidate.Players = append(candidate.Players, partner.Name)
+		limit = combined
 	}
 
-	// If we don't have 3 players yet, add helpers
+	// If the party isn't full yet, add helpers
 	for _, helper := range helpers {
-		if len(candidate.Players) >= 3 {
+		if len(candidate.Players) >= limit.Max {
 			break
 		}
 		if !assigned[helper.Name] {
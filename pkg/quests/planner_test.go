@@ -0,0 +1,67 @@
+package quests
+
+import "testing"
+
+// withPartySizeLimits overrides bosses' party size limits in the catalog for the rest of the test
+func withPartySizeLimits(t *testing.T, limits map[string]PartySizeLimit) {
+	bosses := append([]Boss{}, defaultBosses...)
+	for i := range bosses {
+		if limit, ok := limits[bosses[i].Name]; ok {
+			bosses[i].MinPartySize = limit.Min
+			bosses[i].MaxPartySize = limit.Max
+		}
+	}
+	setBossCatalog(bosses)
+	t.Cleanup(func() { setBossCatalog(defaultBosses) })
+}
+
+func TestNeedsSizeLimit(t *testing.T) {
+	limits := map[string]PartySizeLimit{
+		"devil":  {Min: 2, Max: 5},
+		"zeus":   {Min: 1, Max: 2},
+		"medusa": {Min: 3, Max: 4},
+	}
+	withPartySizeLimits(t, limits)
+
+	start := PartySizeLimit{Min: 1, Max: 5}
+	tests := []struct {
+		name  string
+		needs map[string]int
+		want  PartySizeLimit
+	}{
+		{name: "no needs", needs: nil, want: start},
+		{name: "default boss", needs: map[string]int{"griffin": 2}, want: PartySizeLimit{Min: 1, Max: 3}},
+		{name: "raises the minimum", needs: map[string]int{"devil": 1}, want: PartySizeLimit{Min: 2, Max: 5}},
+		{name: "narrows to every boss", needs: map[string]int{"devil": 1, "zeus": 1}, want: PartySizeLimit{Min: 2, Max: 2}},
+		{name: "ignores bosses already done", needs: map[string]int{"devil": 1, "zeus": 0}, want: PartySizeLimit{Min: 2, Max: 5}},
+		{name: "unknown boss uses the default size", needs: map[string]int{"nobody": 1}, want: PartySizeLimit{Min: 1, Max: PartySize}},
+		{name: "conflicting limits", needs: map[string]int{"medusa": 1, "zeus": 1}, want: PartySizeLimit{Min: 3, Max: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := needsSizeLimit(&PlayerProfile{Name: "alice", Needs: tt.needs}, start)
+			if got != tt.want {
+				t.Errorf("needsSizeLimit(%v) = %+v, want %+v", tt.needs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartySizeLimitAllows(t *testing.T) {
+	tests := []struct {
+		limit PartySizeLimit
+		size  int
+		want  bool
+	}{
+		{limit: PartySizeLimit{Min: 1, Max: 3}, size: 1, want: true},
+		{limit: PartySizeLimit{Min: 1, Max: 3}, size: 3, want: true},
+		{limit: PartySizeLimit{Min: 1, Max: 3}, size: 4, want: false},
+		{limit: PartySizeLimit{Min: 2, Max: 3}, size: 1, want: false},
+		{limit: PartySizeLimit{Min: 3, Max: 2}, size: 2, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.limit.Allows(tt.size); got != tt.want {
+			t.Errorf("%+v.Allows(%d) = %v, want %v", tt.limit, tt.size, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package quests
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// Scheduled party statuses
+const (
+	ScheduledPartyScheduled = "scheduled"
+	ScheduledPartyStarted   = "started"
+	ScheduledPartyCancelled = "cancelled"
+)
+
+// RSVP statuses
+const (
+	RSVPPending  = "pending"
+	RSVPAccepted = "accepted"
+	RSVPDeclined = "declined"
+)
+
+// scheduledPartyRSVPPrefix starts the custom ID of the Discord RSVP buttons
+const scheduledPartyRSVPPrefix = "scheduled_party_rsvp:"
+
+// ScheduledParty is a party planned for a future time. When it starts, a party session is
+// created with only the invited players who accepted.
+type ScheduledParty struct {
+	ID             string               `db:"id" json:"id"`
+	Title          string               `db:"title" json:"title"`
+	ScheduledAt    time.Time            `db:"scheduled_at" json:"scheduled_at"`
+	ChannelID      string               `db:"channel_id" json:"-"`          // Where reminders are posted; empty for the default channel
+	CreatedBy      string               `db:"created_by" json:"created_by"` // Discord user ID
+	Status         string               `db:"status" json:"status"`
+	ReminderSentAt *time.Time           `db:"reminder_sent_at" json:"reminder_sent_at"`
+	PartyID        *string              `db:"party_id" json:"party_id"` // Set once the party has started
+	CreatedAt      time.Time            `db:"created_at" json:"created_at"`
+	RSVPs          []ScheduledPartyRSVP `db:"-" json:"rsvps"`
+}
+
+// ScheduledPartyRSVP is an invited player's response to a scheduled party
+type ScheduledPartyRSVP struct {
+	ScheduledPartyID string     `db:"scheduled_party_id" json:"-"`
+	PlayerName       string     `db:"player_name" json:"player_name"`
+	Status           string     `db:"status" json:"status"`
+	RespondedAt      *time.Time `db:"responded_at" json:"responded_at"`
+}
+
+// PlayersWithStatus returns the invited players whose RSVP has the given status
+func (sp *ScheduledParty) PlayersWithStatus(status string) []string {
+	var players []string
+	for _, rsvp := range sp.RSVPs {
+		if rsvp.Status == status {
+			players = append(players, rsvp.PlayerName)
+		}
+	}
+	return players
+}
+
+// RSVPButtonID returns the Discord button custom ID for responding to a scheduled party
+func RSVPButtonID(scheduledPartyID, status string) string {
+	return scheduledPartyRSVPPrefix + status + ":" + scheduledPartyID
+}
+
+// ParseRSVPButtonID parses a custom ID created by RSVPButtonID
+func ParseRSVPButtonID(customID string) (scheduledPartyID, status string, ok bool) {
+	if !strings.HasPrefix(customID, scheduledPartyRSVPPrefix) {
+		return "", "", false
+	}
+	status, scheduledPartyID, ok = strings.Cut(strings.TrimPrefix(customID, scheduledPartyRSVPPrefix), ":")
+	if !ok || (status != RSVPAccepted && status != RSVPDeclined) {
+		return "", "", false
+	}
+	return scheduledPartyID, status, true
+}
+
+// CreateScheduledParty saves a scheduled party and invites the given players.
+// The party's ID is generated if empty.
+func (d *DB) CreateScheduledParty(ctx context.Context, sp *ScheduledParty, players []string) error {
+	l := ctxzap.Extract(ctx)
+
+	if len(players) == 0 {
+		return fmt.Errorf("no players invited")
+	}
+	if !sp.ScheduledAt.After(time.Now()) {
+		return fmt.Errorf("scheduled time must be in the future")
+	}
+	if sp.ID == "" {
+		b := make([]byte, 4)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		sp.ID = hex.EncodeToString(b)
+	}
+
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := d.db.Rebind(`
+		INSERT INTO scheduled_parties (id, title, scheduled_at, channel_id, created_by, status)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING status, created_at
+	`)
+	if err := tx.QueryRowxContext(ctx, query, sp.ID, sp.Title, sp.ScheduledAt, sp.ChannelID, sp.CreatedBy, ScheduledPartyScheduled).
+		Scan(&sp.Status, &sp.CreatedAt); err != nil {
+		return err
+	}
+
+	rsvpQuery := d.db.Rebind(`
+		INSERT INTO scheduled_party_rsvps (scheduled_party_id, player_name, status)
+		VALUES (?, ?, ?)
+		ON CONFLICT (scheduled_party_id, player_name) DO NOTHING
+	`)
+	sp.RSVPs = nil
+	for _, player := range players {
+		if _, err := tx.ExecContext(ctx, rsvpQuery, sp.ID, player, RSVPPending); err != nil {
+			return err
+		}
+		sp.RSVPs = append(sp.RSVPs, ScheduledPartyRSVP{ScheduledPartyID: sp.ID, PlayerName: player, Status: RSVPPending})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	l.Info("Scheduled party",
+		zap.String("id", sp.ID),
+		zap.Time("scheduled_at", sp.ScheduledAt),
+		zap.Strings("players", players))
+	return nil
+}
+
+const scheduledPartyColumns = `id, title, scheduled_at, channel_id, created_by, status, reminder_sent_at, party_id, created_at`
+
+// GetScheduledParty returns a scheduled party with its RSVPs, or nil if it doesn't exist
+func (d *DB) GetScheduledParty(ctx context.Context, id string) (*ScheduledParty, error) {
+	query := `SELECT ` + scheduledPartyColumns + ` FROM scheduled_parties WHERE id = ?`
+	query = d.db.Rebind(query)
+	var sp ScheduledParty
+	err := d.db.GetContext(ctx, &sp, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	parties := []ScheduledParty{sp}
+	if err := d.loadScheduledPartyRSVPs(ctx, parties); err != nil {
+		return nil, err
+	}
+	return &parties[0], nil
+}
+
+// GetUpcomingScheduledParties returns the parties that haven't started or been cancelled,
+// soonest first. If before is non-zero, only parties scheduled before it are returned.
+func (d *DB) GetUpcomingScheduledParties(ctx context.Context, before time.Time) ([]ScheduledParty, error) {
+	query := `SELECT ` + scheduledPartyColumns + ` FROM scheduled_parties WHERE status = ?`
+	args := []interface{}{ScheduledPartyScheduled}
+	if !before.IsZero() {
+		query += ` AND scheduled_at <= ?`
+		args = append(args, before)
+	}
+	query += ` ORDER BY scheduled_at`
+	query = d.db.Rebind(query)
+
+	var parties []ScheduledParty
+	if err := d.db.SelectContext(ctx, &parties, query, args...); err != nil {
+		return nil, err
+	}
+	if err := d.loadScheduledPartyRSVPs(ctx, parties); err != nil {
+		return nil, err
+	}
+	return parties, nil
+}
+
+// loadScheduledPartyRSVPs fills in the RSVPs of each party
+func (d *DB) loadScheduledPartyRSVPs(ctx context.Context, parties []ScheduledParty) error {
+	if len(parties) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(parties))
+	ids := make([]interface{}, 0, len(parties))
+	for i := range parties {
+		index[parties[i].ID] = i
+		parties[i].RSVPs = []ScheduledPartyRSVP{}
+		ids = append(ids, parties[i].ID)
+	}
+
+	query := `
+		SELECT scheduled_party_id, player_name, status, responded_at
+		FROM scheduled_party_rsvps
+		WHERE scheduled_party_id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)
+		ORDER BY player_name
+	`
+	query = d.db.Rebind(query)
+	var rsvps []ScheduledPartyRSVP
+	if err := d.db.SelectContext(ctx, &rsvps, query, ids...); err != nil {
+		return err
+	}
+	for _, rsvp := range rsvps {
+		sp := &parties[index[rsvp.ScheduledPartyID]]
+		sp.RSVPs = append(sp.RSVPs, rsvp)
+	}
+	return nil
+}
+
+// SetScheduledPartyRSVP records the response of every invited player owned by a Discord user
+// (main or alt). Returns the players that were updated, or none if the user wasn't invited.
+func (d *DB) SetScheduledPartyRSVP(ctx context.Context, id, discordUserID, status string) ([]string, error) {
+	if status != RSVPAccepted && status != RSVPDeclined {
+		return nil, fmt.Errorf("invalid RSVP status: %s", status)
+	}
+
+	names, err := d.GetAllPlayerNames(ctx, discordUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE scheduled_party_rsvps SET status = ?, responded_at = NOW()
+		WHERE scheduled_party_id = ? AND LOWER(player_name) = LOWER(?)
+		AND scheduled_party_id IN (SELECT id FROM scheduled_parties WHERE status = ?)
+	`
+	query = d.db.Rebind(query)
+
+	var updated []string
+	for _, name := range names {
+		result, err := d.db.ExecContext(ctx, query, status, id, name, ScheduledPartyScheduled)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if rows > 0 {
+			updated = append(updated, name)
+		}
+	}
+	return updated, nil
+}
+
+// CancelScheduledParty cancels a party that hasn't started yet
+func (d *DB) CancelScheduledParty(ctx context.Context, id string) error {
+	query := `UPDATE scheduled_parties SET status = ? WHERE id = ? AND status = ?`
+	query = d.db.Rebind(query)
+	result, err := d.db.ExecContext(ctx, query, ScheduledPartyCancelled, id, ScheduledPartyScheduled)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ClaimScheduledPartyReminder records that a party's reminder is being sent.
+// Returns false if it was already claimed, so each party is only reminded once.
+func (d *DB) ClaimScheduledPartyReminder(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE scheduled_parties SET reminder_sent_at = NOW() WHERE id = ? AND reminder_sent_at IS NULL AND status = ?`
+	query = d.db.Rebind(query)
+	return d.claimScheduledParty(ctx, query, id, ScheduledPartyScheduled)
+}
+
+// ClaimScheduledPartyStart marks a party as started.
+// Returns false if it already started or was cancelled, so each party only starts once.
+func (d *DB) ClaimScheduledPartyStart(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE scheduled_parties SET status = ? WHERE id = ? AND status = ?`
+	query = d.db.Rebind(query)
+	return d.claimScheduledParty(ctx, query, ScheduledPartyStarted, id, ScheduledPartyScheduled)
+}
+
+func (d *DB) claimScheduledParty(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	result, err := d.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// SetScheduledPartySession links a started scheduled party to the party session created for it
+func (d *DB) SetScheduledPartySession(ctx context.Context, id, partyID string) error {
+	query := `UPDATE scheduled_parties SET party_id = ? WHERE id = ?`
+	query = d.db.Rebind(query)
+	_, err := d.db.ExecContext(ctx, query, partyID, id)
+	return err
+}
@@ -0,0 +1,128 @@
+package quests
+
+import (
+	"context"
+	"time"
+)
+
+// WeekStart returns the start of an ISO week (Monday 00:00 UTC)
+func WeekStart(weekNumber, year int) time.Time {
+	// January 4th is always in ISO week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7 // days since Monday
+	week1 := jan4.AddDate(0, 0, -offset)
+	return week1.AddDate(0, 0, (weekNumber-1)*7)
+}
+
+// GetWeekBaseline returns the PvM kill counts recorded at the start of a week for a player.
+// Returns nil if no baseline has been recorded yet.
+func (d *DB) GetWeekBaseline(ctx context.Context, playerName string, weekNumber, year int) (map[string]int, error) {
+	query := `
+		SELECT boss_name, kills
+		FROM pvm_week_baselines
+		WHERE player_name = ? AND week_number = ? AND year = ?
+	`
+	query = d.db.Rebind(query)
+	var rows []struct {
+		BossName string `db:"boss_name"`
+		Kills    int    `db:"kills"`
+	}
+	if err := d.db.SelectContext(ctx, &rows, query, playerName, weekNumber, year); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	baseline := make(map[string]int, len(rows))
+	for _, row := range rows {
+		baseline[row.BossName] = row.Kills
+	}
+	return baseline, nil
+}
+
+// RecordWeekBaseline stores a player's PvM kill counts for the start of a week.
+// Existing baselines are never overwritten.
+func (d *DB) RecordWeekBaseline(ctx context.Context, playerName string, weekNumber, year int, kills map[string]int) error {
+	query := `
+		INSERT INTO pvm_week_baselines (player_name, week_number, year, boss_name, kills, recorded_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (player_name, week_number, year, boss_name) DO NOTHING
+	`
+	query = d.db.Rebind(query)
+	for bossName, count := range kills {
+		if _, err := d.db.ExecContext(ctx, query, playerName, weekNumber, year, bossName, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncQuestKills records the kills reported by the API for a quest since the start of the week.
+// current_kills is raised to the API count (capped at required_kills) but never lowered, so
+// manually entered or party-tracked progress is preserved. Returns true if current_kills changed.
+func (d *DB) SyncQuestKills(ctx context.Context, playerName, bossName string, weekNumber, year int, apiKills int) (bool, error) {
+	query := `
+		SELECT id, required_kills, current_kills
+		FROM weekly_quests
+		WHERE player_name = ? AND boss_name = ? AND week_number = ? AND year = ?
+	`
+	query = d.db.Rebind(query)
+	var rows []WeeklyQuestRow
+	if err := d.db.SelectContext(ctx, &rows, query, playerName, bossName, weekNumber, year); err != nil {
+		return false, err
+	}
+
+	updateQuery := `
+		UPDATE weekly_quests
+		SET api_kills = ?, api_synced_at = CURRENT_TIMESTAMP, current_kills = ?
+		WHERE id = ?
+	`
+	updateQuery = d.db.Rebind(updateQuery)
+
+	changed := false
+	for _, row := range rows {
+		newCurrentKills := row.CurrentKills
+		synced := apiKills
+		if synced > row.RequiredKills {
+			synced = row.RequiredKills
+		}
+		if synced > newCurrentKills {
+			newCurrentKills = synced
+			changed = true
+		}
+		if _, err := d.db.ExecContext(ctx, updateQuery, apiKills, newCurrentKills, row.ID); err != nil {
+			return false, err
+		}
+	}
+
+	return changed, nil
+}
+
+// QuestSyncConflict is a quest whose recorded progress disagrees with the API kill counts
+type QuestSyncConflict struct {
+	DiscordUserID string    `db:"discord_user_id" json:"discord_user_id"`
+	PlayerName    string    `db:"player_name" json:"player_name"`
+	BossName      string    `db:"boss_name" json:"boss_name"`
+	RequiredKills int       `db:"required_kills" json:"required_kills"`
+	CurrentKills  int       `db:"current_kills" json:"current_kills"`
+	APIKills      int       `db:"api_kills" json:"api_kills"`
+	SyncedAt      time.Time `db:"api_synced_at" json:"synced_at"`
+}
+
+// GetQuestSyncConflicts returns quests where the recorded kills exceed what the API has seen
+// since the start of the week, i.e. progress was entered manually that the API can't confirm
+func (d *DB) GetQuestSyncConflicts(ctx context.Context, weekNumber, year int) ([]QuestSyncConflict, error) {
+	query := `
+		SELECT discord_user_id, player_name, boss_name, required_kills, current_kills, api_kills, api_synced_at
+		FROM weekly_quests
+		WHERE week_number = ? AND year = ?
+		  AND api_kills IS NOT NULL
+		  AND current_kills > api_kills
+		ORDER BY player_name, boss_name
+	`
+	query = d.db.Rebind(query)
+	var conflicts []QuestSyncConflict
+	err := d.db.SelectContext(ctx, &conflicts, query, weekNumber, year)
+	return conflicts, err
+}
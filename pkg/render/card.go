@@ -0,0 +1,158 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+)
+
+// Player card dimensions
+const (
+	CardWidth  = 960
+	CardHeight = 460
+)
+
+const (
+	cardMargin     = 32
+	cardTopPvM     = 3
+	skillRowHeight = 24
+)
+
+// SkillGroup is a titled set of skills shown as one column on the player card
+type SkillGroup struct {
+	Title  string
+	Skills []string
+}
+
+// SkillGroups are the columns of the player card. Profiles report either attack or rigour
+// depending on the endpoint, so both are listed and whichever is present is shown.
+var SkillGroups = []SkillGroup{
+	{Title: "Combat", Skills: []string{"attack", "rigour", "strength", "defence", "archery", "magic", "health"}},
+	{Title: "Gathering", Skills: []string{"woodcutting", "mining", "fishing", "foraging", "farming"}},
+	{Title: "Crafting", Skills: []string{"crafting", "carpentry", "cooking", "smithing", "brewing", "enchanting"}},
+	{Title: "Other", Skills: []string{"agility", "plundering", "exterminating"}},
+}
+
+// PlayerCard is the data drawn on a player card
+type PlayerCard struct {
+	Name     string
+	Skills   map[string]float64 // skill -> experience, any casing
+	PvmStats map[string]int     // boss -> kill count
+}
+
+// TotalLevel sums the level of every skill on the card
+func (c *PlayerCard) TotalLevel() int {
+	total := 0
+	for _, exp := range c.Skills {
+		level, _ := idleclans.GetSkillLevel(int(exp))
+		total += level
+	}
+	return total
+}
+
+// TopPvM returns the bosses with the most kills, most first. Bosses with no kills are skipped.
+func (c *PlayerCard) TopPvM(n int) []string {
+	bosses := make([]string, 0, len(c.PvmStats))
+	for boss, kills := range c.PvmStats {
+		if kills > 0 {
+			bosses = append(bosses, boss)
+		}
+	}
+	sort.Slice(bosses, func(i, j int) bool {
+		if c.PvmStats[bosses[i]] != c.PvmStats[bosses[j]] {
+			return c.PvmStats[bosses[i]] > c.PvmStats[bosses[j]]
+		}
+		return bosses[i] < bosses[j]
+	})
+	if len(bosses) > n {
+		bosses = bosses[:n]
+	}
+	return bosses
+}
+
+// RenderPlayerCard draws a player's skill levels grouped by category, their total level
+// and their top PvM kill counts
+func RenderPlayerCard(card *PlayerCard) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, CardWidth, CardHeight))
+	FillRect(img, 0, 0, CardWidth-1, CardHeight-1, BgColor)
+	DrawRect(img, 0, 0, CardWidth-1, CardHeight-1, BorderColor)
+	DrawRect(img, 1, 1, CardWidth-2, CardHeight-2, BorderColor)
+
+	// Header: name on the left, total level on the right
+	DrawText(img, cardMargin, cardMargin, FitText(card.Name, CardWidth/2, 5), TextColor, 5)
+	DrawTextRight(img, CardWidth-cardMargin, cardMargin, "Total level", MutedColor, 2)
+	DrawTextRight(img, CardWidth-cardMargin, cardMargin+24, fmt.Sprintf("%d", card.TotalLevel()), AmberColor, 4)
+	DrawHLine(img, cardMargin, CardWidth-cardMargin, 100, BorderColor)
+
+	// Index skills case-insensitively; the simple and full profiles disagree on casing
+	skills := make(map[string]float64, len(card.Skills))
+	for name, exp := range card.Skills {
+		skills[strings.ToLower(name)] = exp
+	}
+
+	columnWidth := (CardWidth - 2*cardMargin) / len(SkillGroups)
+	for i, group := range SkillGroups {
+		x := cardMargin + i*columnWidth
+		FillRect(img, x, 116, x+columnWidth-16, 324, CardColor)
+		DrawText(img, x+8, 124, group.Title, VioletColor, 2)
+
+		y := 152
+		for _, skill := range group.Skills {
+			exp, ok := skills[skill]
+			if !ok {
+				continue
+			}
+			level, _ := idleclans.GetSkillLevel(int(exp))
+			DrawText(img, x+8, y, skill, MutedColor, 2)
+			DrawTextRight(img, x+columnWidth-24, y, fmt.Sprintf("%d", level), TextColor, 2)
+			y += skillRowHeight
+		}
+	}
+
+	// Footer: top PvM kill counts
+	DrawHLine(img, cardMargin, CardWidth-cardMargin, 340, BorderColor)
+	DrawText(img, cardMargin, 356, "Top PvM", VioletColor, 2)
+
+	bosses := card.TopPvM(cardTopPvM)
+	if len(bosses) == 0 {
+		DrawText(img, cardMargin, 388, "No kills yet", MutedColor, 2)
+	}
+	pvmWidth := (CardWidth - 2*cardMargin) / cardTopPvM
+	for i, boss := range bosses {
+		x := cardMargin + i*pvmWidth
+		DrawText(img, x, 388, FitText(splitCamelCase(boss), pvmWidth-16, 2), MutedColor, 2)
+		DrawText(img, x, 412, fmt.Sprintf("%d", card.PvmStats[boss]), GreenColor, 3)
+	}
+
+	return img
+}
+
+// RenderPlaceholderCard draws a card for a player the bot doesn't track, with just their name
+func RenderPlaceholderCard(name string) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, CardWidth, CardHeight))
+	FillRect(img, 0, 0, CardWidth-1, CardHeight-1, BgColor)
+	DrawRect(img, 0, 0, CardWidth-1, CardHeight-1, BorderColor)
+	DrawRect(img, 1, 1, CardWidth-2, CardHeight-2, BorderColor)
+
+	DrawText(img, cardMargin, cardMargin, FitText(name, CardWidth-2*cardMargin, 5), TextColor, 5)
+	DrawHLine(img, cardMargin, CardWidth-cardMargin, 100, BorderColor)
+	DrawText(img, cardMargin, 124, "Not a tracked clan player", MutedColor, 3)
+
+	return img
+}
+
+// splitCamelCase converts a key such as "ReckoningOfTheGods" to "Reckoning Of The Gods"
+func splitCamelCase(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		if i > 0 && unicode.IsUpper(r) {
+			sb.WriteRune(' ')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
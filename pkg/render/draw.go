@@ -0,0 +1,125 @@
+// Package render draws the PNG images served to Discord and as Open Graph previews
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+// Shared palette, matching the web dashboard
+var (
+	BgColor     = color.RGBA{10, 15, 26, 255}    // #0a0f1a
+	CardColor   = color.RGBA{13, 19, 33, 255}    // #0d1321
+	TextColor   = color.RGBA{243, 244, 246, 255} // #f3f4f6
+	MutedColor  = color.RGBA{156, 163, 175, 255} // #9ca3af
+	GreenColor  = color.RGBA{16, 185, 129, 255}  // #10b981
+	RedColor    = color.RGBA{239, 68, 68, 255}   // #ef4444
+	VioletColor = color.RGBA{139, 92, 246, 255}  // #8b5cf6
+	AmberColor  = color.RGBA{245, 158, 11, 255}  // #f59e0b
+	BorderColor = color.RGBA{31, 41, 55, 255}    // #1f2937
+)
+
+// Point is a pixel coordinate
+type Point struct {
+	X, Y int
+}
+
+// FillRect fills the rectangle between two corners, inclusive
+func FillRect(img *image.RGBA, x1, y1, x2, y2 int, c color.Color) {
+	for y := y1; y <= y2; y++ {
+		for x := x1; x <= x2; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
+
+// DrawRect outlines the rectangle between two corners, inclusive
+func DrawRect(img *image.RGBA, x1, y1, x2, y2 int, c color.Color) {
+	DrawHLine(img, x1, x2, y1, c)
+	DrawHLine(img, x1, x2, y2, c)
+	DrawVLine(img, x1, y1, y2, c)
+	DrawVLine(img, x2, y1, y2, c)
+}
+
+// DrawHLine draws a horizontal line
+func DrawHLine(img *image.RGBA, x1, x2, y int, c color.Color) {
+	for x := x1; x <= x2; x++ {
+		img.Set(x, y, c)
+	}
+}
+
+// DrawVLine draws a vertical line
+func DrawVLine(img *image.RGBA, x, y1, y2 int, c color.Color) {
+	for y := y1; y <= y2; y++ {
+		img.Set(x, y, c)
+	}
+}
+
+// DrawThickLine draws a line of the given thickness between two points
+func DrawThickLine(img *image.RGBA, x1, y1, x2, y2 int, c color.Color, thickness int) {
+	// Bresenham's line algorithm with thickness
+	dx := abs(x2 - x1)
+	dy := abs(y2 - y1)
+	sx := 1
+	if x1 >= x2 {
+		sx = -1
+	}
+	sy := 1
+	if y1 >= y2 {
+		sy = -1
+	}
+	err := dx - dy
+
+	for {
+		// Draw thick point
+		for ty := -thickness / 2; ty <= thickness/2; ty++ {
+			for tx := -thickness / 2; tx <= thickness/2; tx++ {
+				img.Set(x1+tx, y1+ty, c)
+			}
+		}
+
+		if x1 == x2 && y1 == y2 {
+			break
+		}
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x1 += sx
+		}
+		if e2 < dx {
+			err += dx
+			y1 += sy
+		}
+	}
+}
+
+// DrawFilledCircle draws a filled circle centered on (cx, cy)
+func DrawFilledCircle(img *image.RGBA, cx, cy, r int, c color.Color) {
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				img.Set(cx+x, cy+y, c)
+			}
+		}
+	}
+}
+
+// BlendPixel alpha-blends a color over the existing pixel
+func BlendPixel(img *image.RGBA, x, y int, c color.RGBA) {
+	if x < 0 || y < 0 || x >= img.Bounds().Dx() || y >= img.Bounds().Dy() {
+		return
+	}
+	existing := img.RGBAAt(x, y)
+	alpha := float64(c.A) / 255.0
+	newR := uint8(float64(existing.R)*(1-alpha) + float64(c.R)*alpha)
+	newG := uint8(float64(existing.G)*(1-alpha) + float64(c.G)*alpha)
+	newB := uint8(float64(existing.B)*(1-alpha) + float64(c.B)*alpha)
+	img.Set(x, y, color.RGBA{newR, newG, newB, 255})
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,139 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/quests"
+)
+
+// MaxComparePlayers is the most players that can be compared at once
+const MaxComparePlayers = 5
+
+// Comparison row categories
+const (
+	CompareSkills = "skills"
+	ComparePvM    = "pvm"
+	CompareKeys   = "keys"
+)
+
+// ComparisonRow is a single stat across every compared player
+type ComparisonRow struct {
+	Category string  `json:"category"`
+	Name     string  `json:"name"`
+	Values   []int64 `json:"values"`  // One per player, in the order of Comparison.Players
+	Leaders  []int   `json:"leaders"` // Indexes of the players with the highest value; empty if nobody leads
+}
+
+// Comparison is a side-by-side view of several players' skills, PvM kills and keys
+type Comparison struct {
+	Players []string        `json:"players"`
+	Rows    []ComparisonRow `json:"rows"`
+}
+
+// ComparePlayers fetches each player's profile concurrently and lines up their skill levels,
+// PvM kill counts and key inventory
+func ComparePlayers(ctx context.Context, client *idleclans.Client, questsDB *quests.DB, playerNames []string) (*Comparison, error) {
+	if len(playerNames) < 2 {
+		return nil, fmt.Errorf("at least two players are required")
+	}
+	if len(playerNames) > MaxComparePlayers {
+		return nil, fmt.Errorf("at most %d players can be compared", MaxComparePlayers)
+	}
+
+	snapshots := make([]*Snapshot, len(playerNames))
+	keys := make([]map[string]int, len(playerNames))
+	errs := make([]error, len(playerNames))
+
+	var wg sync.WaitGroup
+	for i, name := range playerNames {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			player, err := client.GetPlayer(ctx, name)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to get profile for %s: %w", name, err)
+				return
+			}
+			snapshots[i], errs[i] = SnapshotFromPlayer(name, player)
+			if errs[i] != nil {
+				return
+			}
+
+			// Key inventory is only known for registered players
+			if questsDB != nil {
+				keys[i], errs[i] = questsDB.GetPlayerKeys(ctx, name)
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	comparison := &Comparison{Players: playerNames}
+
+	for _, skill := range sortedKeys(snapshots[0].Skills) {
+		row := ComparisonRow{Category: CompareSkills, Name: skill}
+		for _, s := range snapshots {
+			level, _ := idleclans.GetSkillLevel(int(s.Skills[skill]))
+			row.Values = append(row.Values, int64(level))
+		}
+		comparison.Rows = append(comparison.Rows, withLeaders(row))
+	}
+
+	for _, boss := range sortedKeys(snapshots[0].PvmStats) {
+		row := ComparisonRow{Category: ComparePvM, Name: boss}
+		for _, s := range snapshots {
+			row.Values = append(row.Values, int64(s.PvmStats[boss]))
+		}
+		comparison.Rows = append(comparison.Rows, withLeaders(row))
+	}
+
+	for _, keyType := range quests.KeyTypes() {
+		row := ComparisonRow{Category: CompareKeys, Name: keyType}
+		for _, k := range keys {
+			row.Values = append(row.Values, int64(k[keyType]))
+		}
+		comparison.Rows = append(comparison.Rows, withLeaders(row))
+	}
+
+	return comparison, nil
+}
+
+// withLeaders marks the players with the highest value in a row.
+// Nobody leads a row where every value is equal.
+func withLeaders(row ComparisonRow) ComparisonRow {
+	best, worst := row.Values[0], row.Values[0]
+	for _, v := range row.Values {
+		best = max(best, v)
+		worst = min(worst, v)
+	}
+
+	row.Leaders = []int{}
+	if best == worst {
+		return row
+	}
+	for i, v := range row.Values {
+		if v == best {
+			row.Leaders = append(row.Leaders, i)
+		}
+	}
+	return row
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,28 @@
+package tracker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithLeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int64
+		want   []int
+	}{
+		{name: "single leader", values: []int64{10, 30, 20}, want: []int{1}},
+		{name: "tied leaders", values: []int64{30, 10, 30}, want: []int{0, 2}},
+		{name: "all equal", values: []int64{5, 5, 5}, want: []int{}},
+		{name: "all zero", values: []int64{0, 0}, want: []int{}},
+		{name: "one player", values: []int64{7}, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := withLeaders(ComparisonRow{Category: CompareSkills, Name: "attack", Values: tt.values})
+			if !reflect.DeepEqual(row.Leaders, tt.want) {
+				t.Errorf("withLeaders(%v).Leaders = %v, want %v", tt.values, row.Leaders, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package tracker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+)
+
+// Competition metric types
+const (
+	MetricSkill = "skill"
+	MetricBoss  = "boss"
+)
+
+// Competition statuses
+const (
+	CompetitionScheduled = "scheduled"
+	CompetitionActive    = "active"
+	CompetitionFinished  = "finished"
+	CompetitionCancelled = "cancelled"
+)
+
+// DefaultStandingsInterval is how often standings are refreshed and posted for an active competition
+const DefaultStandingsInterval = 24 * time.Hour
+
+// Competition is a clan event measuring XP or boss kills gained over a time window
+type Competition struct {
+	ID                int        `db:"id" json:"id"`
+	Name              string     `db:"name" json:"name"`
+	MetricType        string     `db:"metric_type" json:"metric_type"` // "skill" or "boss"
+	Metric            string     `db:"metric" json:"metric"`           // skill or pvmStats key, e.g. "mining" or "Griffin"
+	StartsAt          time.Time  `db:"starts_at" json:"starts_at"`
+	EndsAt            time.Time  `db:"ends_at" json:"ends_at"`
+	ChannelID         string     `db:"channel_id" json:"-"`
+	CreatedBy         string     `db:"created_by" json:"created_by"`
+	Status            string     `db:"status" json:"status"`
+	StandingsInterval int        `db:"standings_interval_hours" json:"standings_interval_hours"`
+	LastStandingsAt   *time.Time `db:"last_standings_at" json:"last_standings_at"`
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+}
+
+// Standing is a participant's position in a competition
+type Standing struct {
+	Rank         int       `json:"rank"`
+	PlayerName   string    `db:"player_name" json:"player_name"`
+	StartValue   float64   `db:"start_value" json:"start_value"`
+	CurrentValue float64   `db:"current_value" json:"current_value"`
+	Gain         float64   `json:"gain"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ResolveMetric validates a competition metric, returning its canonical type and name.
+// Skills match the keys of the player profile's skillExperiences and bosses the keys of
+// pvmStats (including raids), case-insensitively.
+func ResolveMetric(input string) (string, string, bool) {
+	empty, err := SnapshotFromPlayer("", &idleclans.Player{})
+	if err != nil {
+		return "", "", false
+	}
+
+	input = strings.TrimSpace(input)
+	for skill := range empty.Skills {
+		if strings.EqualFold(skill, input) {
+			return MetricSkill, skill, true
+		}
+	}
+	for boss := range empty.PvmStats {
+		if strings.EqualFold(boss, input) {
+			return MetricBoss, boss, true
+		}
+	}
+	return "", "", false
+}
+
+// Value returns the competition metric from a snapshot
+func (c *Competition) Value(snapshot *Snapshot) float64 {
+	if c.MetricType == MetricBoss {
+		return float64(snapshot.PvmStats[c.Metric])
+	}
+	return snapshot.Skills[c.Metric]
+}
+
+// CreateCompetition schedules a new competition
+func (d *DB) CreateCompetition(ctx context.Context, c *Competition) error {
+	if c.StandingsInterval <= 0 {
+		c.StandingsInterval = int(DefaultStandingsInterval.Hours())
+	}
+	if !c.EndsAt.After(c.StartsAt) {
+		return fmt.Errorf("competition must end after it starts")
+	}
+
+	query := `
+		INSERT INTO competitions (name, metric_type, metric, starts_at, ends_at, channel_id, created_by, status, standings_interval_hours)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, status, created_at
+	`
+	query = d.db.Rebind(query)
+	return d.db.QueryRowxContext(ctx, query,
+		c.Name, c.MetricType, c.Metric, c.StartsAt, c.EndsAt, c.ChannelID, c.CreatedBy, CompetitionScheduled, c.StandingsInterval,
+	).Scan(&c.ID, &c.Status, &c.CreatedAt)
+}
+
+const competitionColumns = `id, name, metric_type, metric, starts_at, ends_at, channel_id, created_by, status, standings_interval_hours, last_standings_at, created_at`
+
+// GetCompetition returns a competition by ID, or nil if it doesn't exist
+func (d *DB) GetCompetition(ctx context.Context, id int) (*Competition, error) {
+	query := `SELECT ` + competitionColumns + ` FROM competitions WHERE id = ?`
+	query = d.db.Rebind(query)
+	var c Competition
+	err := d.db.GetContext(ctx, &c, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetCompetitions returns the most recent competitions, newest first
+func (d *DB) GetCompetitions(ctx context.Context, limit int) ([]Competition, error) {
+	query := `SELECT ` + competitionColumns + ` FROM competitions ORDER BY starts_at DESC LIMIT ?`
+	query = d.db.Rebind(query)
+	var competitions []Competition
+	err := d.db.SelectContext(ctx, &competitions, query, limit)
+	return competitions, err
+}
+
+// GetCompetitionsByStatus returns all competitions with a status
+func (d *DB) GetCompetitionsByStatus(ctx context.Context, status string) ([]Competition, error) {
+	query := `SELECT ` + competitionColumns + ` FROM competitions WHERE status = ? ORDER BY starts_at`
+	query = d.db.Rebind(query)
+	var competitions []Competition
+	err := d.db.SelectContext(ctx, &competitions, query, status)
+	return competitions, err
+}
+
+// SetCompetitionStatus updates a competition's status
+func (d *DB) SetCompetitionStatus(ctx context.Context, id int, status string) error {
+	query := `UPDATE competitions SET status = ? WHERE id = ?`
+	query = d.db.Rebind(query)
+	_, err := d.db.ExecContext(ctx, query, status, id)
+	return err
+}
+
+// CancelCompetition cancels a competition that hasn't finished yet
+func (d *DB) CancelCompetition(ctx context.Context, id int) error {
+	query := `UPDATE competitions SET status = ? WHERE id = ? AND status IN (?, ?)`
+	query = d.db.Rebind(query)
+	result, err := d.db.ExecContext(ctx, query, CompetitionCancelled, id, CompetitionScheduled, CompetitionActive)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MarkStandingsPosted records when standings were last posted for a competition
+func (d *DB) MarkStandingsPosted(ctx context.Context, id int) error {
+	query := `UPDATE competitions SET last_standings_at = NOW() WHERE id = ?`
+	query = d.db.Rebind(query)
+	_, err := d.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// UpsertCompetitionEntry records a participant's start value (on insert) and current value
+func (d *DB) UpsertCompetitionEntry(ctx context.Context, competitionID int, playerName string, value float64) error {
+	query := `
+		INSERT INTO competition_entries (competition_id, player_name, start_value, current_value, updated_at)
+		VALUES (?, ?, ?, ?, NOW())
+		ON CONFLICT (competition_id, player_name) DO UPDATE SET
+			current_value = EXCLUDED.current_value,
+			updated_at = NOW()
+	`
+	query = d.db.Rebind(query)
+	_, err := d.db.ExecContext(ctx, query, competitionID, playerName, value, value)
+	return err
+}
+
+// GetStandings returns a competition's participants ranked by gain
+func (d *DB) GetStandings(ctx context.Context, competitionID int) ([]Standing, error) {
+	query := `
+		SELECT player_name, start_value, current_value, updated_at
+		FROM competition_entries
+		WHERE competition_id = ?
+	`
+	query = d.db.Rebind(query)
+	var standings []Standing
+	if err := d.db.SelectContext(ctx, &standings, query, competitionID); err != nil {
+		return nil, err
+	}
+
+	for i := range standings {
+		standings[i].Gain = standings[i].CurrentValue - standings[i].StartValue
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Gain != standings[j].Gain {
+			return standings[i].Gain > standings[j].Gain
+		}
+		return standings[i].PlayerName < standings[j].PlayerName
+	})
+
+	// Ties share a rank
+	for i := range standings {
+		if i > 0 && standings[i].Gain == standings[i-1].Gain {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings, nil
+}
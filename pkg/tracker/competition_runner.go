@@ -0,0 +1,156 @@
+package tracker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// competitionCheckInterval is how often competitions are checked for start, standings and end
+const competitionCheckInterval = 1 * time.Minute
+
+// CompetitionNotifier is called when competition standings are posted or a competition ends
+type CompetitionNotifier interface {
+	// NotifyCompetitionStandings posts the current standings. final is true when the competition has ended.
+	NotifyCompetitionStandings(ctx context.Context, competition *Competition, standings []Standing, final bool) error
+}
+
+// processCompetitions starts scheduled competitions, refreshes and posts standings for
+// active ones and finishes those that have ended
+func (t *Tracker) processCompetitions(ctx context.Context) {
+	now := time.Now()
+
+	scheduled, err := t.db.GetCompetitionsByStatus(ctx, CompetitionScheduled)
+	if err != nil {
+		t.logger.Error("Failed to get scheduled competitions", zap.Error(err))
+		return
+	}
+	for i := range scheduled {
+		c := &scheduled[i]
+		if c.StartsAt.After(now) {
+			continue
+		}
+		t.startCompetition(ctx, c)
+	}
+
+	active, err := t.db.GetCompetitionsByStatus(ctx, CompetitionActive)
+	if err != nil {
+		t.logger.Error("Failed to get active competitions", zap.Error(err))
+		return
+	}
+	for i := range active {
+		c := &active[i]
+		switch {
+		case !c.EndsAt.After(now):
+			t.finishCompetition(ctx, c)
+		case t.standingsDue(c, now):
+			t.postStandings(ctx, c)
+		}
+	}
+}
+
+// standingsDue reports whether an active competition's standings should be refreshed and posted
+func (t *Tracker) standingsDue(c *Competition, now time.Time) bool {
+	last := c.StartsAt
+	if c.LastStandingsAt != nil {
+		last = *c.LastStandingsAt
+	}
+	return now.Sub(last) >= time.Duration(c.StandingsInterval)*time.Hour
+}
+
+// startCompetition captures start values for every registered player and alt
+func (t *Tracker) startCompetition(ctx context.Context, c *Competition) {
+	names, err := t.questsDB.GetAllRegisteredPlayerNames(ctx)
+	if err != nil {
+		t.logger.Error("Failed to get registered player names", zap.Error(err))
+		return
+	}
+
+	t.logger.Info("Starting competition", zap.Int("id", c.ID), zap.String("name", c.Name), zap.Int("participants", len(names)))
+
+	t.captureCompetitionValues(ctx, c, names)
+
+	if err := t.db.SetCompetitionStatus(ctx, c.ID, CompetitionActive); err != nil {
+		t.logger.Error("Failed to activate competition", zap.Int("id", c.ID), zap.Error(err))
+		return
+	}
+	t.notifyCompetitionChange()
+}
+
+// postStandings refreshes current values and posts the standings
+func (t *Tracker) postStandings(ctx context.Context, c *Competition) {
+	t.refreshCompetition(ctx, c)
+	if err := t.db.MarkStandingsPosted(ctx, c.ID); err != nil {
+		t.logger.Error("Failed to mark standings posted", zap.Int("id", c.ID), zap.Error(err))
+	}
+	t.notifyCompetitionStandings(ctx, c, false)
+}
+
+// finishCompetition captures end values, marks the competition finished and announces the winners
+func (t *Tracker) finishCompetition(ctx context.Context, c *Competition) {
+	t.refreshCompetition(ctx, c)
+	if err := t.db.SetCompetitionStatus(ctx, c.ID, CompetitionFinished); err != nil {
+		t.logger.Error("Failed to finish competition", zap.Int("id", c.ID), zap.Error(err))
+		return
+	}
+	t.logger.Info("Competition finished", zap.Int("id", c.ID), zap.String("name", c.Name))
+	t.notifyCompetitionStandings(ctx, c, true)
+}
+
+// refreshCompetition captures current values for a competition's participants
+func (t *Tracker) refreshCompetition(ctx context.Context, c *Competition) {
+	standings, err := t.db.GetStandings(ctx, c.ID)
+	if err != nil {
+		t.logger.Error("Failed to get competition standings", zap.Int("id", c.ID), zap.Error(err))
+		return
+	}
+
+	names := make([]string, 0, len(standings))
+	for _, s := range standings {
+		names = append(names, s.PlayerName)
+	}
+	t.captureCompetitionValues(ctx, c, names)
+}
+
+// captureCompetitionValues records each player's value from their latest stored snapshot.
+// It makes no API requests, so the competition checks don't compete with the snapshot pass
+// for the rate limit; values are as fresh as the snapshot interval. Players without a snapshot
+// get an entry when the snapshot pass first captures them.
+func (t *Tracker) captureCompetitionValues(ctx context.Context, c *Competition, names []string) {
+	for _, name := range names {
+		snapshot, err := t.db.GetLatestSnapshot(ctx, name)
+		if err != nil {
+			t.logger.Warn("Failed to get latest snapshot", zap.String("player", name), zap.Error(err))
+			continue
+		}
+		if snapshot == nil {
+			continue
+		}
+		if err := t.db.UpsertCompetitionEntry(ctx, c.ID, name, c.Value(snapshot)); err != nil {
+			t.logger.Warn("Failed to record competition entry",
+				zap.Int("id", c.ID), zap.String("player", name), zap.Error(err))
+		}
+	}
+	t.notifyCompetitionChange()
+}
+
+func (t *Tracker) notifyCompetitionStandings(ctx context.Context, c *Competition, final bool) {
+	if t.competitionNotifier == nil {
+		return
+	}
+	standings, err := t.db.GetStandings(ctx, c.ID)
+	if err != nil {
+		t.logger.Error("Failed to get competition standings", zap.Int("id", c.ID), zap.Error(err))
+		return
+	}
+	if err := t.competitionNotifier.NotifyCompetitionStandings(ctx, c, standings, final); err != nil {
+		t.logger.Error("Failed to post competition standings", zap.Int("id", c.ID), zap.Error(err))
+	}
+}
+
+func (t *Tracker) notifyCompetitionChange() {
+	if t.dataChangeNotifier != nil {
+		t.dataChangeNotifier("competition")
+	}
+}
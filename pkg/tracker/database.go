@@ -0,0 +1,257 @@
+package tracker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// DB handles player snapshot storage
+type DB struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+// NewDB creates a new tracker database using an existing sqlx.DB
+func NewDB(db *sqlx.DB, logger *zap.Logger) (*DB, error) {
+	d := &DB{db: db, logger: logger}
+	if err := d.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize tracker schema: %w", err)
+	}
+	return d, nil
+}
+
+func (d *DB) initSchema() error {
+	if _, err := d.db.Exec(d.getBaseSchema()); err != nil {
+		return err
+	}
+
+	// Migrations for existing databases
+	migrations := []string{
+		`ALTER TABLE player_snapshots ADD COLUMN IF NOT EXISTS equipment JSONB`,
+	}
+	for _, m := range migrations {
+		if _, err := d.db.Exec(m); err != nil {
+			d.logger.Debug("Migration may have already been applied", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (d *DB) getBaseSchema() string {
+	return `
+	-- Periodic snapshots of player profiles from the IdleClans API
+	CREATE TABLE IF NOT EXISTS player_snapshots (
+		id SERIAL PRIMARY KEY,
+		player_name TEXT NOT NULL,
+		captured_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		skills JSONB NOT NULL,
+		pvm_stats JSONB NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_player_snapshots_player_time ON player_snapshots(LOWER(player_name), captured_at DESC);
+
+	-- Clan competitions over a skill or boss
+	CREATE TABLE IF NOT EXISTS competitions (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		metric_type TEXT NOT NULL,
+		metric TEXT NOT NULL,
+		starts_at TIMESTAMPTZ NOT NULL,
+		ends_at TIMESTAMPTZ NOT NULL,
+		channel_id TEXT NOT NULL DEFAULT '',
+		created_by TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'scheduled',
+		standings_interval_hours INTEGER NOT NULL DEFAULT 24,
+		last_standings_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_competitions_status ON competitions(status);
+
+	CREATE TABLE IF NOT EXISTS competition_entries (
+		competition_id INTEGER NOT NULL REFERENCES competitions(id) ON DELETE CASCADE,
+		player_name TEXT NOT NULL,
+		start_value DOUBLE PRECISION NOT NULL,
+		current_value DOUBLE PRECISION NOT NULL,
+		updated_at TIMESTAMPTZ DEFAULT NOW(),
+		PRIMARY KEY (competition_id, player_name)
+	);
+
+	-- Weeks for which the end-of-week quest reminder has been sent
+	CREATE TABLE IF NOT EXISTS quest_reminders (
+		week_number INTEGER NOT NULL,
+		year INTEGER NOT NULL,
+		sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (week_number, year)
+	);
+	`
+}
+
+// Snapshot is a point-in-time copy of a player's skill experience, PvM kill counts and equipment
+type Snapshot struct {
+	ID         int                `json:"id"`
+	PlayerName string             `json:"player_name"`
+	CapturedAt time.Time          `json:"captured_at"`
+	Skills     map[string]float64 `json:"skills"`
+	PvmStats   map[string]int     `json:"pvm_stats"`
+	Equipment  map[string]int     `json:"equipment,omitempty"` // slot -> item ID; nil for snapshots taken before equipment was recorded
+}
+
+// snapshotRow is the raw row from the database
+type snapshotRow struct {
+	ID         int       `db:"id"`
+	PlayerName string    `db:"player_name"`
+	CapturedAt time.Time `db:"captured_at"`
+	Skills     []byte    `db:"skills"`
+	PvmStats   []byte    `db:"pvm_stats"`
+	Equipment  []byte    `db:"equipment"`
+}
+
+func (r *snapshotRow) toSnapshot() (*Snapshot, error) {
+	snapshot := &Snapshot{
+		ID:         r.ID,
+		PlayerName: r.PlayerName,
+		CapturedAt: r.CapturedAt,
+	}
+	if err := json.Unmarshal(r.Skills, &snapshot.Skills); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal skills: %w", err)
+	}
+	if err := json.Unmarshal(r.PvmStats, &snapshot.PvmStats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pvm stats: %w", err)
+	}
+	if len(r.Equipment) > 0 {
+		if err := json.Unmarshal(r.Equipment, &snapshot.Equipment); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal equipment: %w", err)
+		}
+	}
+	return snapshot, nil
+}
+
+// InsertSnapshot stores a new player snapshot
+func (d *DB) InsertSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	skillsJSON, err := json.Marshal(snapshot.Skills)
+	if err != nil {
+		return fmt.Errorf("failed to marshal skills: %w", err)
+	}
+	pvmJSON, err := json.Marshal(snapshot.PvmStats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pvm stats: %w", err)
+	}
+	var equipmentJSON []byte
+	if snapshot.Equipment != nil {
+		equipmentJSON, err = json.Marshal(snapshot.Equipment)
+		if err != nil {
+			return fmt.Errorf("failed to marshal equipment: %w", err)
+		}
+	}
+
+	capturedAt := snapshot.CapturedAt
+	if capturedAt.IsZero() {
+		capturedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO player_snapshots (player_name, captured_at, skills, pvm_stats, equipment)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+	query = d.db.Rebind(query)
+	return d.db.GetContext(ctx, &snapshot.ID, query, snapshot.PlayerName, capturedAt, skillsJSON, pvmJSON, equipmentJSON)
+}
+
+// GetLatestSnapshot returns the most recent snapshot for a player, or nil if none exist
+func (d *DB) GetLatestSnapshot(ctx context.Context, playerName string) (*Snapshot, error) {
+	query := `
+		SELECT id, player_name, captured_at, skills, pvm_stats, equipment
+		FROM player_snapshots
+		WHERE LOWER(player_name) = LOWER(?)
+		ORDER BY captured_at DESC
+		LIMIT 1
+	`
+	return d.getSnapshot(ctx, query, playerName)
+}
+
+// GetSnapshotBefore returns the most recent snapshot taken at or before t, or nil if none exist
+func (d *DB) GetSnapshotBefore(ctx context.Context, playerName string, t time.Time) (*Snapshot, error) {
+	query := `
+		SELECT id, player_name, captured_at, skills, pvm_stats, equipment
+		FROM player_snapshots
+		WHERE LOWER(player_name) = LOWER(?) AND captured_at <= ?
+		ORDER BY captured_at DESC
+		LIMIT 1
+	`
+	return d.getSnapshot(ctx, query, playerName, t)
+}
+
+// GetSnapshotAfter returns the oldest snapshot taken at or after t, or nil if none exist
+func (d *DB) GetSnapshotAfter(ctx context.Context, playerName string, t time.Time) (*Snapshot, error) {
+	query := `
+		SELECT id, player_name, captured_at, skills, pvm_stats, equipment
+		FROM player_snapshots
+		WHERE LOWER(player_name) = LOWER(?) AND captured_at >= ?
+		ORDER BY captured_at ASC
+		LIMIT 1
+	`
+	return d.getSnapshot(ctx, query, playerName, t)
+}
+
+func (d *DB) getSnapshot(ctx context.Context, query string, args ...interface{}) (*Snapshot, error) {
+	var row snapshotRow
+	err := d.db.GetContext(ctx, &row, d.db.Rebind(query), args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toSnapshot()
+}
+
+// GetLatestSnapshotTimes returns the time of the most recent snapshot for every tracked player,
+// keyed by lowercased player name
+func (d *DB) GetLatestSnapshotTimes(ctx context.Context) (map[string]time.Time, error) {
+	var rows []struct {
+		PlayerName string    `db:"player_name"`
+		CapturedAt time.Time `db:"captured_at"`
+	}
+	query := `
+		SELECT LOWER(player_name) as player_name, MAX(captured_at) as captured_at
+		FROM player_snapshots
+		GROUP BY LOWER(player_name)
+	`
+	if err := d.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	times := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		times[row.PlayerName] = row.CapturedAt
+	}
+	return times, nil
+}
+
+// PruneSnapshots deletes every snapshot taken before cutoff except each player's last one of
+// each day (UTC), and returns how many were deleted
+func (d *DB) PruneSnapshots(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM player_snapshots
+		WHERE captured_at < ?
+		AND id NOT IN (
+			SELECT DISTINCT ON (LOWER(player_name), DATE(captured_at AT TIME ZONE 'UTC')) id
+			FROM player_snapshots
+			WHERE captured_at < ?
+			ORDER BY LOWER(player_name), DATE(captured_at AT TIME ZONE 'UTC'), captured_at DESC
+		)
+	`
+	result, err := d.db.ExecContext(ctx, d.db.Rebind(query), cutoff, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,210 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+)
+
+// Periods supported by the gains tracker
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// PeriodDuration returns the lookback window for a period name.
+// Accepts "day"/"d", "week"/"w" and "month"/"m".
+func PeriodDuration(period string) (string, time.Duration, error) {
+	switch strings.ToLower(strings.TrimSpace(period)) {
+	case "day", "d", "24h":
+		return PeriodDay, 24 * time.Hour, nil
+	case "", "week", "w", "7d":
+		return PeriodWeek, 7 * 24 * time.Hour, nil
+	case "month", "m", "30d":
+		return PeriodMonth, 30 * 24 * time.Hour, nil
+	default:
+		return "", 0, fmt.Errorf("unknown period %q (use day, week or month)", period)
+	}
+}
+
+// SnapshotFromPlayer converts an API player profile into a snapshot
+func SnapshotFromPlayer(playerName string, player *idleclans.Player) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		PlayerName: playerName,
+		CapturedAt: time.Now(),
+		Skills:     make(map[string]float64),
+		PvmStats:   make(map[string]int),
+		Equipment:  player.EquipmentBySlot(),
+	}
+
+	// Round-trip through JSON so the snapshot uses the same keys as the API
+	skillsJSON, err := json.Marshal(player.SkillExperiences)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(skillsJSON, &snapshot.Skills); err != nil {
+		return nil, err
+	}
+
+	pvmJSON, err := json.Marshal(player.PvmStats)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(pvmJSON, &snapshot.PvmStats); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// SkillGain is the change in a single skill between two snapshots
+type SkillGain struct {
+	Skill        string  `json:"skill"`
+	StartXP      float64 `json:"start_xp"`
+	EndXP        float64 `json:"end_xp"`
+	XPGained     float64 `json:"xp_gained"`
+	StartLevel   int     `json:"start_level"`
+	EndLevel     int     `json:"end_level"`
+	LevelsGained int     `json:"levels_gained"`
+	XPPerHour    float64 `json:"xp_per_hour"`
+}
+
+// BossGain is the change in a single boss kill count between two snapshots
+type BossGain struct {
+	Boss       string `json:"boss"`
+	StartKills int    `json:"start_kills"`
+	EndKills   int    `json:"end_kills"`
+	Kills      int    `json:"kills"`
+}
+
+// Gains summarizes a player's progress over a period
+type Gains struct {
+	PlayerName   string      `json:"player_name"`
+	Period       string      `json:"period"`
+	From         time.Time   `json:"from"`
+	To           time.Time   `json:"to"`
+	Skills       []SkillGain `json:"skills"`
+	PvM          []BossGain  `json:"pvm"`
+	TotalXP      float64     `json:"total_xp"`
+	TotalLevels  int         `json:"total_levels"`
+	TotalKills   int         `json:"total_kills"`
+	PartialRange bool        `json:"partial_range"` // true if snapshots don't cover the full period
+}
+
+// GetGains computes a player's gains over a period from stored snapshots.
+// The baseline is the latest snapshot taken before the period started, or the oldest
+// snapshot within the period if tracking began more recently. Returns nil if fewer
+// than two snapshots are available.
+func (d *DB) GetGains(ctx context.Context, playerName, period string) (*Gains, error) {
+	periodName, window, err := PeriodDuration(period)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := d.GetLatestSnapshot(ctx, playerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest snapshot: %w", err)
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	start := time.Now().Add(-window)
+	partial := false
+	baseline, err := d.GetSnapshotBefore(ctx, playerName, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baseline snapshot: %w", err)
+	}
+	if baseline == nil {
+		partial = true
+		baseline, err = d.GetSnapshotAfter(ctx, playerName, start)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get baseline snapshot: %w", err)
+		}
+	}
+	if baseline == nil || baseline.ID == latest.ID {
+		return nil, nil
+	}
+
+	gains := CompareSnapshots(baseline, latest)
+	gains.Period = periodName
+	gains.PartialRange = partial
+	return gains, nil
+}
+
+// CompareSnapshots computes the gains between two snapshots of the same player
+func CompareSnapshots(from, to *Snapshot) *Gains {
+	gains := &Gains{
+		PlayerName: to.PlayerName,
+		From:       from.CapturedAt,
+		To:         to.CapturedAt,
+		Skills:     []SkillGain{},
+		PvM:        []BossGain{},
+	}
+
+	hours := to.CapturedAt.Sub(from.CapturedAt).Hours()
+
+	for skill, endXP := range to.Skills {
+		startXP := from.Skills[skill]
+		if endXP <= startXP {
+			continue
+		}
+		startLevel, _ := idleclans.GetSkillLevel(int(startXP))
+		endLevel, _ := idleclans.GetSkillLevel(int(endXP))
+		gain := SkillGain{
+			Skill:        skill,
+			StartXP:      startXP,
+			EndXP:        endXP,
+			XPGained:     endXP - startXP,
+			StartLevel:   startLevel,
+			EndLevel:     endLevel,
+			LevelsGained: endLevel - startLevel,
+		}
+		if hours > 0 {
+			gain.XPPerHour = gain.XPGained / hours
+		}
+		gains.Skills = append(gains.Skills, gain)
+		gains.TotalXP += gain.XPGained
+		gains.TotalLevels += gain.LevelsGained
+	}
+
+	for boss, endKills := range to.PvmStats {
+		startKills := from.PvmStats[boss]
+		if endKills <= startKills {
+			continue
+		}
+		gains.PvM = append(gains.PvM, BossGain{
+			Boss:       boss,
+			StartKills: startKills,
+			EndKills:   endKills,
+			Kills:      endKills - startKills,
+		})
+		gains.TotalKills += endKills - startKills
+	}
+
+	sort.Slice(gains.Skills, func(i, j int) bool {
+		return gains.Skills[i].XPGained > gains.Skills[j].XPGained
+	})
+	sort.Slice(gains.PvM, func(i, j int) bool {
+		return gains.PvM[i].Kills > gains.PvM[j].Kills
+	})
+
+	return gains
+}
+
+// SkillXPPerHour returns the recorded XP/hour rate for a skill over a period.
+// Returns false if there is not enough history or no XP was gained.
+func (g *Gains) SkillXPPerHour(skill string) (float64, bool) {
+	for _, gain := range g.Skills {
+		if strings.EqualFold(gain.Skill, skill) && gain.XPPerHour > 0 {
+			return gain.XPPerHour, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,29 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+)
+
+func TestCompareSnapshotsCapsLevels(t *testing.T) {
+	maxXP, _ := idleclans.ExperienceForLevel(idleclans.MaxSkillLevel)
+	virtualXP, _ := idleclans.ExperienceForLevel(idleclans.MaxSkillLevel + 5)
+	from := &Snapshot{PlayerName: "alice", CapturedAt: time.Now().Add(-time.Hour), Skills: map[string]float64{"attack": float64(maxXP)}}
+	to := &Snapshot{PlayerName: "alice", CapturedAt: time.Now(), Skills: map[string]float64{"attack": float64(virtualXP)}}
+
+	gains := CompareSnapshots(from, to)
+	if len(gains.Skills) != 1 {
+		t.Fatalf("CompareSnapshots() returned %d skill gains, want 1", len(gains.Skills))
+	}
+	// XP past the max level is still gained, but no levels are
+	gain := gains.Skills[0]
+	if gain.StartLevel != idleclans.MaxSkillLevel || gain.EndLevel != idleclans.MaxSkillLevel || gain.LevelsGained != 0 {
+		t.Errorf("CompareSnapshots() levels = %d → %d (+%d), want %d → %d (+0)",
+			gain.StartLevel, gain.EndLevel, gain.LevelsGained, idleclans.MaxSkillLevel, idleclans.MaxSkillLevel)
+	}
+	if gain.XPGained != float64(virtualXP-maxXP) {
+		t.Errorf("CompareSnapshots() XPGained = %v, want %v", gain.XPGained, virtualXP-maxXP)
+	}
+}
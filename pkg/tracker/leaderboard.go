@@ -0,0 +1,121 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// leaderboardMaxFetches caps the profiles fetched for players without a recent snapshot while
+// building a leaderboard. At the tracker's request pace each one adds requestDelay to the command.
+const leaderboardMaxFetches = 5
+
+// LeaderboardEntry is one player's kill count on a PvM leaderboard
+type LeaderboardEntry struct {
+	PlayerName  string `json:"player_name"`
+	Kills       int    `json:"kills"`
+	WeeklyKills *int   `json:"weekly_kills,omitempty"` // nil if no snapshot from the last week exists
+}
+
+// PvMLeaderboard ranks registered players by kills of one boss, or by total kills
+type PvMLeaderboard struct {
+	Boss    string             `json:"boss,omitempty"` // Empty for total kills
+	Entries []LeaderboardEntry `json:"entries"`
+	Failed  []string           `json:"failed,omitempty"` // Players without a snapshot whose profiles couldn't be fetched
+}
+
+// ResolveBoss validates a boss name case-insensitively, returning its pvmStats key
+func ResolveBoss(input string) (string, bool) {
+	metricType, metric, ok := ResolveMetric(input)
+	if !ok || metricType != MetricBoss {
+		return "", false
+	}
+	return metric, true
+}
+
+// BuildPvMLeaderboard ranks every registered player and alt by kills of boss, or by total kills if
+// boss is empty. Kills come from each player's latest snapshot; players without one from the last
+// snapshot interval are fetched first, up to leaderboardMaxFetches, at the tracker's request pace.
+// Weekly deltas are computed against older snapshots.
+func (t *Tracker) BuildPvMLeaderboard(ctx context.Context, boss string) (*PvMLeaderboard, error) {
+	names, err := t.questsDB.GetAllRegisteredPlayerNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registered players: %w", err)
+	}
+
+	latest, err := t.db.GetLatestSnapshotTimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest snapshot times: %w", err)
+	}
+
+	current := make(map[string]*Snapshot, len(names))
+	cutoff := time.Now().Add(-t.interval)
+	var stale []string
+	for _, name := range names {
+		if last, ok := latest[strings.ToLower(name)]; !ok || last.Before(cutoff) {
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) > leaderboardMaxFetches {
+		stale = stale[:leaderboardMaxFetches]
+	}
+	t.snapshotPlayers(ctx, stale, func(snapshot *Snapshot) {
+		current[snapshot.PlayerName] = snapshot
+	})
+
+	killsOf := func(s *Snapshot) int {
+		if boss != "" {
+			return s.PvmStats[boss]
+		}
+		total := 0
+		for _, kills := range s.PvmStats {
+			total += kills
+		}
+		return total
+	}
+
+	weekAgo := time.Now().Add(-7 * 24 * time.Hour)
+	entries := make([]*LeaderboardEntry, len(names))
+	for i, name := range names {
+		snapshot := current[name]
+		if snapshot == nil {
+			if snapshot, err = t.db.GetLatestSnapshot(ctx, name); err != nil || snapshot == nil {
+				continue
+			}
+		}
+		entry := &LeaderboardEntry{PlayerName: name, Kills: killsOf(snapshot)}
+
+		baseline, err := t.db.GetSnapshotBefore(ctx, name, weekAgo)
+		if err == nil && baseline == nil {
+			// Tracking began within the last week
+			baseline, err = t.db.GetSnapshotAfter(ctx, name, weekAgo)
+		}
+		if err == nil && baseline != nil {
+			weekly := max(0, entry.Kills-killsOf(baseline))
+			entry.WeeklyKills = &weekly
+		}
+
+		entries[i] = entry
+	}
+
+	leaderboard := &PvMLeaderboard{Boss: boss, Entries: []LeaderboardEntry{}}
+	for i, entry := range entries {
+		if entry == nil {
+			leaderboard.Failed = append(leaderboard.Failed, names[i])
+			continue
+		}
+		leaderboard.Entries = append(leaderboard.Entries, *entry)
+	}
+
+	sort.SliceStable(leaderboard.Entries, func(i, j int) bool {
+		a, b := leaderboard.Entries[i], leaderboard.Entries[j]
+		if a.Kills != b.Kills {
+			return a.Kills > b.Kills
+		}
+		return strings.ToLower(a.PlayerName) < strings.ToLower(b.PlayerName)
+	})
+
+	return leaderboard, nil
+}
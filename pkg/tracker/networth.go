@@ -0,0 +1,195 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/market"
+)
+
+// GearValue is an equipped item priced at the latest market prices
+type GearValue struct {
+	idleclans.GearSlot
+	SellPrice int  `json:"sell_price"` // Lowest sell listing
+	BuyPrice  int  `json:"buy_price"`  // Highest buy order
+	Priced    bool `json:"priced"`     // The item has a market price
+}
+
+// NetWorth is the market value of a player's equipped gear
+type NetWorth struct {
+	PlayerName string      `json:"player_name"`
+	Items      []GearValue `json:"items"`
+	SellValue  int64       `json:"sell_value"`
+	BuyValue   int64       `json:"buy_value"`
+	Unpriced   int         `json:"unpriced"` // Equipped items with no market price
+	CapturedAt time.Time   `json:"captured_at"`
+}
+
+// ClanGearValue is the combined value of every tracked player's gear at the end of a day
+type ClanGearValue struct {
+	Date      time.Time `json:"date"`
+	SellValue int64     `json:"sell_value"`
+	BuyValue  int64     `json:"buy_value"`
+	Players   int       `json:"players"`
+}
+
+// OnDemandSnapshotName returns the name to record an on-demand snapshot of a looked-up player
+// under, and false if it shouldn't be recorded. Only registered players and members of the
+// clan's guild are recorded, under the name the API returns, so lookups of other players and
+// differently-cased names stay out of the clan history.
+func OnDemandSnapshotName(player *idleclans.Player, lookedUp string, registered []string, guild string) (string, bool) {
+	name := player.Username
+	if name == "" {
+		name = lookedUp
+	}
+	if guild != "" && strings.EqualFold(player.GuildName, guild) {
+		return name, true
+	}
+	for _, r := range registered {
+		if strings.EqualFold(r, name) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// latestPrices returns the latest market price for each item, keyed by item ID
+func latestPrices(ctx context.Context, prices *market.DB, itemIDs []int) (map[int]market.PriceSnapshot, error) {
+	snapshots, err := prices.GetLatestPrices(ctx, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest prices: %w", err)
+	}
+	byID := make(map[int]market.PriceSnapshot, len(snapshots))
+	for _, p := range snapshots {
+		byID[p.ItemID] = p
+	}
+	return byID, nil
+}
+
+// ValueGear prices each equipped item at its latest market buy and sell prices
+func ValueGear(ctx context.Context, prices *market.DB, playerName string, gear []idleclans.GearSlot) (*NetWorth, error) {
+	var itemIDs []int
+	for _, g := range gear {
+		if !g.Empty {
+			itemIDs = append(itemIDs, g.ItemID)
+		}
+	}
+
+	byID, err := latestPrices(ctx, prices, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	worth := &NetWorth{
+		PlayerName: playerName,
+		Items:      make([]GearValue, 0, len(gear)),
+		CapturedAt: time.Now(),
+	}
+	for _, g := range gear {
+		v := GearValue{GearSlot: g}
+		if p, ok := byID[g.ItemID]; ok && !g.Empty && (p.LowestSellPrice > 0 || p.HighestBuyPrice > 0) {
+			v.Priced = true
+			v.SellPrice = p.LowestSellPrice
+			v.BuyPrice = p.HighestBuyPrice
+			worth.SellValue += int64(p.LowestSellPrice)
+			worth.BuyValue += int64(p.HighestBuyPrice)
+		} else if !g.Empty {
+			worth.Unpriced++
+		}
+		worth.Items = append(worth.Items, v)
+	}
+
+	return worth, nil
+}
+
+// GetClanGearValueHistory returns the combined gear value of the given players for each of the
+// last days days, using each player's last snapshot of the day (carried forward to days without one).
+// Gear is valued at the latest market prices, so changes reflect gear swaps rather than price moves.
+func (d *DB) GetClanGearValueHistory(ctx context.Context, prices *market.DB, playerNames []string, days int) ([]ClanGearValue, error) {
+	if days <= 0 {
+		days = 30
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	start := today.AddDate(0, 0, -(days - 1))
+
+	var rows []struct {
+		PlayerName string    `db:"player_name"`
+		Day        time.Time `db:"day"`
+		Equipment  []byte    `db:"equipment"`
+	}
+	query := `
+		SELECT DISTINCT ON (LOWER(player_name), (captured_at AT TIME ZONE 'UTC')::date)
+			LOWER(player_name) as player_name,
+			(captured_at AT TIME ZONE 'UTC')::date as day,
+			equipment
+		FROM player_snapshots
+		WHERE captured_at >= ? AND equipment IS NOT NULL
+		ORDER BY LOWER(player_name), (captured_at AT TIME ZONE 'UTC')::date, captured_at DESC
+	`
+	if err := d.db.SelectContext(ctx, &rows, d.db.Rebind(query), start); err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[string]bool, len(playerNames))
+	for _, name := range playerNames {
+		tracked[strings.ToLower(name)] = true
+	}
+
+	// day -> player -> equipment
+	byDay := make(map[time.Time]map[string]map[string]int)
+	itemSet := make(map[int]bool)
+	for _, row := range rows {
+		if !tracked[row.PlayerName] {
+			continue
+		}
+		var equipment map[string]int
+		if err := json.Unmarshal(row.Equipment, &equipment); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal equipment: %w", err)
+		}
+		day := time.Date(row.Day.Year(), row.Day.Month(), row.Day.Day(), 0, 0, 0, 0, time.UTC)
+		if byDay[day] == nil {
+			byDay[day] = make(map[string]map[string]int)
+		}
+		byDay[day][row.PlayerName] = equipment
+		for _, itemID := range equipment {
+			if itemID > 0 {
+				itemSet[itemID] = true
+			}
+		}
+	}
+
+	itemIDs := make([]int, 0, len(itemSet))
+	for itemID := range itemSet {
+		itemIDs = append(itemIDs, itemID)
+	}
+	byID, err := latestPrices(ctx, prices, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]map[string]int)
+	history := make([]ClanGearValue, 0, days)
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		for player, equipment := range byDay[day] {
+			current[player] = equipment
+		}
+
+		point := ClanGearValue{Date: day, Players: len(current)}
+		for _, equipment := range current {
+			for _, itemID := range equipment {
+				if p, ok := byID[itemID]; ok && itemID > 0 {
+					point.SellValue += int64(p.LowestSellPrice)
+					point.BuyValue += int64(p.HighestBuyPrice)
+				}
+			}
+		}
+		history = append(history, point)
+	}
+
+	return history, nil
+}
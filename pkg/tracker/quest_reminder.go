@@ -0,0 +1,163 @@
+package tracker
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+// QuestReminderNotifier is called once per week, shortly before the quest reset,
+// with every Discord user who still has incomplete boss quests
+type QuestReminderNotifier interface {
+	NotifyQuestReminders(ctx context.Context, resetAt time.Time, reminders []QuestReminder) error
+}
+
+// QuestReminder lists one Discord user's incomplete boss quests for the week
+type QuestReminder struct {
+	DiscordUserID string
+	Quests        []IncompleteQuest
+}
+
+// IncompleteQuest is a boss quest with kills remaining, and the clan members who share it
+// and already hold the key for it
+type IncompleteQuest struct {
+	PlayerName string
+	BossName   string
+	Remaining  int
+	Helpers    []QuestHelper
+}
+
+// QuestHelper is another player with the same boss quest who holds keys for the boss
+type QuestHelper struct {
+	PlayerName string
+	Keys       int
+}
+
+// ClaimQuestReminder records that the reminder for a week is being sent.
+// Returns false if it was already claimed, so each week is only reminded once.
+func (d *DB) ClaimQuestReminder(ctx context.Context, weekNumber, year int) (bool, error) {
+	query := `
+		INSERT INTO quest_reminders (week_number, year, sent_at)
+		VALUES (?, ?, NOW())
+		ON CONFLICT (week_number, year) DO NOTHING
+	`
+	query = d.db.Rebind(query)
+	result, err := d.db.ExecContext(ctx, query, weekNumber, year)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// processQuestReminders sends the weekly reminder once the reset is within the configured window
+func (t *Tracker) processQuestReminders(ctx context.Context) {
+	if t.questReminderNotifier == nil || t.questReminderHours <= 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	year, week := now.ISOWeek()
+	resetAt := quests.WeekStart(week, year).AddDate(0, 0, 7)
+	if now.Before(resetAt.Add(-time.Duration(t.questReminderHours) * time.Hour)) {
+		return
+	}
+
+	claimed, err := t.db.ClaimQuestReminder(ctx, week, year)
+	if err != nil {
+		t.logger.Error("Failed to claim quest reminder", zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	reminders, err := BuildQuestReminders(ctx, t.questsDB, week, year)
+	if err != nil {
+		t.logger.Error("Failed to build quest reminders", zap.Error(err))
+		return
+	}
+	if len(reminders) == 0 {
+		t.logger.Info("No incomplete quests to remind about", zap.Int("week", week), zap.Int("year", year))
+		return
+	}
+
+	t.logger.Info("Sending quest reminders", zap.Int("week", week), zap.Int("year", year), zap.Int("users", len(reminders)))
+	if err := t.questReminderNotifier.NotifyQuestReminders(ctx, resetAt, reminders); err != nil {
+		t.logger.Error("Failed to send quest reminders", zap.Error(err))
+	}
+}
+
+// BuildQuestReminders groups the week's incomplete boss quests by the Discord user who owns
+// each player, listing for each quest the other players who share it and hold its key
+func BuildQuestReminders(ctx context.Context, questsDB *quests.DB, weekNumber, year int) ([]QuestReminder, error) {
+	allKeys, err := questsDB.GetAllPlayerKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyCounts := make(map[string]map[string]int) // lowercase player -> key type -> count
+	for _, k := range allKeys {
+		name := strings.ToLower(k.PlayerName)
+		if keyCounts[name] == nil {
+			keyCounts[name] = make(map[string]int)
+		}
+		keyCounts[name][k.KeyType] = k.Count
+	}
+
+	bosses := quests.ValidBosses()
+	sort.Strings(bosses)
+
+	byUser := make(map[string]*QuestReminder)
+	var order []string
+	for _, boss := range bosses {
+		players, err := questsDB.GetPlayersWithBossQuest(ctx, boss, weekNumber, year)
+		if err != nil {
+			return nil, err
+		}
+		keyType, _ := quests.GetKeyForBoss(boss)
+
+		for _, player := range players {
+			if player.DiscordUserID == "" {
+				continue
+			}
+
+			quest := IncompleteQuest{
+				PlayerName: player.PlayerName,
+				BossName:   boss,
+				Remaining:  player.RequiredKills - player.CurrentKills,
+			}
+			for _, other := range players {
+				if other.DiscordUserID == player.DiscordUserID {
+					continue
+				}
+				if keys := keyCounts[strings.ToLower(other.PlayerName)][keyType]; keys > 0 {
+					quest.Helpers = append(quest.Helpers, QuestHelper{PlayerName: other.PlayerName, Keys: keys})
+				}
+			}
+			sort.SliceStable(quest.Helpers, func(i, j int) bool {
+				return quest.Helpers[i].Keys > quest.Helpers[j].Keys
+			})
+
+			reminder, ok := byUser[player.DiscordUserID]
+			if !ok {
+				reminder = &QuestReminder{DiscordUserID: player.DiscordUserID}
+				byUser[player.DiscordUserID] = reminder
+				order = append(order, player.DiscordUserID)
+			}
+			reminder.Quests = append(reminder.Quests, quest)
+		}
+	}
+
+	reminders := make([]QuestReminder, 0, len(order))
+	for _, userID := range order {
+		reminders = append(reminders, *byUser[userID])
+	}
+	return reminders, nil
+}
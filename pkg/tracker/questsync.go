@@ -0,0 +1,65 @@
+package tracker
+
+import (
+	"context"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/quests"
+)
+
+// baselineTolerance is how long before the week start a snapshot can be taken and
+// still be used as that week's baseline
+const baselineTolerance = 6 * time.Hour
+
+// syncQuestProgress updates a player's weekly quest progress from the kill counts in a snapshot.
+// The first snapshot of each week records the baseline; later snapshots sync the delta since then.
+// Returns true if any quest's current_kills changed.
+func (t *Tracker) syncQuestProgress(ctx context.Context, snapshot *Snapshot) (bool, error) {
+	year, week := snapshot.CapturedAt.UTC().ISOWeek()
+
+	baseline, err := t.questsDB.GetWeekBaseline(ctx, snapshot.PlayerName, week, year)
+	if err != nil {
+		return false, err
+	}
+	if baseline == nil {
+		baseline = snapshot.PvmStats
+
+		// Prefer a snapshot taken shortly before the reset so kills made early in the week count
+		weekStart := quests.WeekStart(week, year)
+		previous, err := t.db.GetSnapshotBefore(ctx, snapshot.PlayerName, weekStart)
+		if err != nil {
+			return false, err
+		}
+		if previous != nil && weekStart.Sub(previous.CapturedAt) <= baselineTolerance {
+			baseline = previous.PvmStats
+		}
+
+		if err := t.questsDB.RecordWeekBaseline(ctx, snapshot.PlayerName, week, year, baseline); err != nil {
+			return false, err
+		}
+	}
+
+	playerQuests, err := t.questsDB.GetPlayerQuests(ctx, snapshot.PlayerName, week, year)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for _, q := range playerQuests {
+		stat, ok := quests.GetPvmStat(q.BossName)
+		if !ok {
+			continue
+		}
+		kills := snapshot.PvmStats[stat] - baseline[stat]
+		if kills < 0 {
+			kills = 0
+		}
+		updated, err := t.questsDB.SyncQuestKills(ctx, snapshot.PlayerName, q.BossName, week, year, kills)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || updated
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,66 @@
+package tracker
+
+import (
+	"context"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+// ScheduledPartyNotifier reminds invited players before a scheduled party and starts it on time.
+// It is implemented by the web server, which owns party sessions.
+type ScheduledPartyNotifier interface {
+	NotifyScheduledPartyReminder(ctx context.Context, party *quests.ScheduledParty) error
+	StartScheduledParty(ctx context.Context, party *quests.ScheduledParty) error
+}
+
+// processScheduledParties sends reminders for parties starting soon and starts the ones that are due
+func (t *Tracker) processScheduledParties(ctx context.Context) {
+	if t.scheduledPartyNotifier == nil {
+		return
+	}
+
+	now := time.Now()
+	parties, err := t.questsDB.GetUpcomingScheduledParties(ctx, now.Add(t.partyReminderLead))
+	if err != nil {
+		t.logger.Error("Failed to get upcoming scheduled parties", zap.Error(err))
+		return
+	}
+
+	for i := range parties {
+		sp := &parties[i]
+
+		if !sp.ScheduledAt.After(now) {
+			claimed, err := t.questsDB.ClaimScheduledPartyStart(ctx, sp.ID)
+			if err != nil {
+				t.logger.Error("Failed to claim scheduled party start", zap.String("id", sp.ID), zap.Error(err))
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			sp.Status = quests.ScheduledPartyStarted
+			if err := t.scheduledPartyNotifier.StartScheduledParty(ctx, sp); err != nil {
+				t.logger.Error("Failed to start scheduled party", zap.String("id", sp.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		// Parties scheduled within the reminder window were just announced, so skip their reminder
+		if sp.ReminderSentAt != nil || sp.ScheduledAt.Sub(sp.CreatedAt) <= t.partyReminderLead {
+			continue
+		}
+		claimed, err := t.questsDB.ClaimScheduledPartyReminder(ctx, sp.ID)
+		if err != nil {
+			t.logger.Error("Failed to claim scheduled party reminder", zap.String("id", sp.ID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := t.scheduledPartyNotifier.NotifyScheduledPartyReminder(ctx, sp); err != nil {
+			t.logger.Error("Failed to send scheduled party reminder", zap.String("id", sp.ID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package tracker
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSnapshotInterval is how often every registered player is snapshotted
+	DefaultSnapshotInterval = 1 * time.Hour
+	// snapshotPruneInterval is how often snapshots past the retention period are thinned out
+	snapshotPruneInterval = 24 * time.Hour
+	// requestDelay spaces out profile requests to stay under the API rate limit
+	// (40 requests per 2 minutes, shared with the rest of the bot)
+	requestDelay = 4 * time.Second
+)
+
+// Tracker periodically snapshots the profiles of all registered players,
+// syncs their weekly quest progress from the PvM kill counts and runs competitions
+type Tracker struct {
+	db       *DB
+	questsDB *quests.DB
+	client   *idleclans.Client
+	logger   *zap.Logger
+	interval time.Duration
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+
+	// Snapshots older than retention are thinned to one per player per day (0 = keep all)
+	retention time.Duration
+
+	// Profile requests from every caller are spaced requestDelay apart
+	paceMu      sync.Mutex
+	nextRequest time.Time
+
+	// Competition standings notification callback
+	competitionNotifier CompetitionNotifier
+
+	// End-of-week quest reminder callback, sent questReminderHours before the reset
+	questReminderNotifier QuestReminderNotifier
+	questReminderHours    int
+
+	// Scheduled party reminder and start callback, reminding partyReminderLead before the start
+	scheduledPartyNotifier ScheduledPartyNotifier
+	partyReminderLead      time.Duration
+
+	// Data change notification callback (for SSE)
+	dataChangeNotifier func(changeType string)
+}
+
+// TrackerConfig holds configuration for the tracker
+type TrackerConfig struct {
+	Interval           time.Duration
+	Retention          time.Duration // How long every snapshot is kept before thinning to one per day (0 = keep all)
+	QuestReminderHours int           // Hours before the weekly reset to remind about incomplete quests (0 = disabled)
+	PartyReminderLead  time.Duration // How long before a scheduled party to remind its players (0 = no reminder)
+}
+
+// NewTracker creates a new player snapshot tracker
+func NewTracker(db *DB, questsDB *quests.DB, client *idleclans.Client, logger *zap.Logger, config *TrackerConfig) *Tracker {
+	if config == nil {
+		config = &TrackerConfig{Interval: DefaultSnapshotInterval}
+	}
+	if config.Interval == 0 {
+		config.Interval = DefaultSnapshotInterval
+	}
+
+	return &Tracker{
+		db:       db,
+		questsDB: questsDB,
+		client:   client,
+		logger:   logger,
+		interval: config.Interval,
+		stopCh:   make(chan struct{}),
+
+		retention:          config.Retention,
+		questReminderHours: config.QuestReminderHours,
+		partyReminderLead:  config.PartyReminderLead,
+	}
+}
+
+// SetCompetitionNotifier sets the callback for competition standings and results
+func (t *Tracker) SetCompetitionNotifier(notifier CompetitionNotifier) {
+	t.competitionNotifier = notifier
+}
+
+// SetQuestReminderNotifier sets the callback for end-of-week quest reminders
+func (t *Tracker) SetQuestReminderNotifier(notifier QuestReminderNotifier) {
+	t.questReminderNotifier = notifier
+}
+
+// SetScheduledPartyNotifier sets the callback for scheduled party reminders and starts
+func (t *Tracker) SetScheduledPartyNotifier(notifier ScheduledPartyNotifier) {
+	t.scheduledPartyNotifier = notifier
+}
+
+// SetDataChangeNotifier sets the callback for SSE data change notifications
+func (t *Tracker) SetDataChangeNotifier(notifier func(changeType string)) {
+	t.dataChangeNotifier = notifier
+}
+
+// Start begins the snapshot loop
+func (t *Tracker) Start(ctx context.Context) {
+	t.wg.Add(1)
+	go t.run(ctx)
+}
+
+// Stop gracefully stops the tracker
+func (t *Tracker) Stop() {
+	close(t.stopCh)
+	t.wg.Wait()
+}
+
+func (t *Tracker) run(ctx context.Context) {
+	defer t.wg.Done()
+
+	t.logger.Info("Starting player snapshot tracker", zap.Duration("interval", t.interval))
+
+	// A snapshot pass takes requestDelay per player, so it runs on its own to keep the
+	// competition, reminder and scheduled party checks on time
+	t.wg.Add(1)
+	go t.runSnapshots(ctx)
+
+	t.processCompetitions(ctx)
+	t.processQuestReminders(ctx)
+	t.processScheduledParties(ctx)
+	t.pruneSnapshots(ctx)
+
+	// Competitions are checked often so they start and end close to their scheduled times
+	competitionTicker := time.NewTicker(competitionCheckInterval)
+	defer competitionTicker.Stop()
+
+	pruneTicker := time.NewTicker(snapshotPruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.logger.Info("Tracker stopped due to context cancellation")
+			return
+		case <-t.stopCh:
+			t.logger.Info("Tracker stopped")
+			return
+		case <-competitionTicker.C:
+			t.processCompetitions(ctx)
+			t.processQuestReminders(ctx)
+			t.processScheduledParties(ctx)
+		case <-pruneTicker.C:
+			t.pruneSnapshots(ctx)
+		}
+	}
+}
+
+// pruneSnapshots thins snapshots older than the retention period to the last one of each day,
+// which is all gains and history need that far back
+func (t *Tracker) pruneSnapshots(ctx context.Context) {
+	if t.retention <= 0 {
+		return
+	}
+
+	deleted, err := t.db.PruneSnapshots(ctx, time.Now().Add(-t.retention))
+	if err != nil {
+		t.logger.Error("Failed to prune player snapshots", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		t.logger.Info("Pruned player snapshots", zap.Int64("deleted", deleted), zap.Duration("retention", t.retention))
+	}
+}
+
+// runSnapshots snapshots every registered player once per interval
+func (t *Tracker) runSnapshots(ctx context.Context) {
+	defer t.wg.Done()
+
+	t.snapshotAll(ctx)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.stopCh:
+			return
+		case <-ticker.C:
+			t.snapshotAll(ctx)
+		}
+	}
+}
+
+// snapshotAll snapshots every registered player whose latest snapshot is older than the interval,
+// and records their current values in active competitions. Players missing from a competition
+// get an entry starting from this snapshot.
+func (t *Tracker) snapshotAll(ctx context.Context) {
+	names, err := t.questsDB.GetAllRegisteredPlayerNames(ctx)
+	if err != nil {
+		t.logger.Error("Failed to get registered player names", zap.Error(err))
+		return
+	}
+
+	latest, err := t.db.GetLatestSnapshotTimes(ctx)
+	if err != nil {
+		t.logger.Error("Failed to get latest snapshot times", zap.Error(err))
+		return
+	}
+
+	// Skip players snapshotted recently (e.g. after a restart)
+	cutoff := time.Now().Add(-t.interval / 2)
+	var stale []string
+	for _, name := range names {
+		if last, ok := latest[strings.ToLower(name)]; ok && last.After(cutoff) {
+			continue
+		}
+		stale = append(stale, name)
+	}
+
+	active, err := t.db.GetCompetitionsByStatus(ctx, CompetitionActive)
+	if err != nil {
+		t.logger.Error("Failed to get active competitions", zap.Error(err))
+	}
+
+	var fn func(*Snapshot)
+	if len(active) > 0 {
+		fn = func(snapshot *Snapshot) {
+			for i := range active {
+				c := &active[i]
+				if err := t.db.UpsertCompetitionEntry(ctx, c.ID, snapshot.PlayerName, c.Value(snapshot)); err != nil {
+					t.logger.Warn("Failed to record competition entry",
+						zap.Int("id", c.ID), zap.String("player", snapshot.PlayerName), zap.Error(err))
+				}
+			}
+		}
+	}
+
+	t.snapshotPlayers(ctx, stale, fn)
+	if len(active) > 0 && len(stale) > 0 {
+		t.notifyCompetitionChange()
+	}
+}
+
+// snapshotPlayers snapshots each player in turn, spacing out requests to respect the
+// API rate limit, and syncs their quest progress. fn, if set, is called for each snapshot.
+// Returns false if the tracker was stopped before all players were processed.
+func (t *Tracker) snapshotPlayers(ctx context.Context, names []string, fn func(*Snapshot)) bool {
+	captured := 0
+	questsChanged := false
+	defer func() {
+		if captured > 0 {
+			t.logger.Info("Player snapshots captured", zap.Int("count", captured))
+			if t.dataChangeNotifier != nil {
+				t.dataChangeNotifier("snapshots")
+			}
+		}
+		if questsChanged && t.dataChangeNotifier != nil {
+			t.dataChangeNotifier("quest")
+		}
+	}()
+
+	for _, name := range names {
+		if !t.waitForRequest(ctx) {
+			return false
+		}
+
+		snapshot, err := t.SnapshotPlayer(ctx, name)
+		if err != nil {
+			t.logger.Warn("Failed to snapshot player", zap.String("player", name), zap.Error(err))
+			continue
+		}
+		captured++
+
+		changed, err := t.syncQuestProgress(ctx, snapshot)
+		if err != nil {
+			t.logger.Warn("Failed to sync quest progress", zap.String("player", name), zap.Error(err))
+		}
+		questsChanged = questsChanged || changed
+
+		if fn != nil {
+			fn(snapshot)
+		}
+	}
+
+	return true
+}
+
+// waitForRequest blocks until the next profile request may be sent. The snapshot pass and
+// on-demand fetches share one pace, so together they stay under the API rate limit.
+// Returns false if the tracker was stopped first.
+func (t *Tracker) waitForRequest(ctx context.Context) bool {
+	t.paceMu.Lock()
+	at := time.Now()
+	if t.nextRequest.After(at) {
+		at = t.nextRequest
+	}
+	t.nextRequest = at.Add(requestDelay)
+	t.paceMu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.stopCh:
+		return false
+	case <-time.After(time.Until(at)):
+		return true
+	}
+}
+
+// SnapshotPlayer fetches a player's profile and stores it as a new snapshot
+func (t *Tracker) SnapshotPlayer(ctx context.Context, playerName string) (*Snapshot, error) {
+	player, err := t.client.GetPlayer(ctx, playerName)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := SnapshotFromPlayer(playerName, player)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.db.InsertSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
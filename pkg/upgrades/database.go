@@ -0,0 +1,145 @@
+package upgrades
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// DB handles upgrade metadata storage
+type DB struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+// NewDB creates a new upgrades database using an existing sqlx.DB.
+// Every upgrade reported by the player API is seeded with a display name only. The API
+// doesn't report max tiers or tier costs, so they stay unknown (MaxTier 0, no tiers) until
+// they're entered through the admin API, and no recommendations are made before then.
+func NewDB(db *sqlx.DB, logger *zap.Logger) (*DB, error) {
+	d := &DB{db: db, logger: logger}
+	if err := d.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize upgrades schema: %w", err)
+	}
+	if err := d.seed(); err != nil {
+		return nil, fmt.Errorf("failed to seed upgrade metadata: %w", err)
+	}
+	return d, nil
+}
+
+func (d *DB) initSchema() error {
+	_, err := d.db.Exec(`
+	-- Clan/house upgrade tiers and the cost of each tier
+	CREATE TABLE IF NOT EXISTS upgrade_metadata (
+		upgrade_key TEXT PRIMARY KEY,
+		display_name TEXT NOT NULL,
+		max_tier INTEGER NOT NULL DEFAULT 0,
+		tiers JSONB NOT NULL DEFAULT '[]',
+		updated_at TIMESTAMPTZ DEFAULT NOW()
+	);
+	`)
+	return err
+}
+
+func (d *DB) seed() error {
+	query := d.db.Rebind(`
+		INSERT INTO upgrade_metadata (upgrade_key, display_name)
+		VALUES (?, ?)
+		ON CONFLICT (upgrade_key) DO NOTHING
+	`)
+	for _, key := range Keys() {
+		if _, err := d.db.Exec(query, key, DisplayName(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TierCost is the cost to reach a tier
+type TierCost struct {
+	Tier      int            `json:"tier"`
+	GoldCost  int64          `json:"gold_cost"`
+	Materials map[string]int `json:"materials,omitempty"` // item name_id -> quantity
+}
+
+// Upgrade is the metadata for a single upgrade
+type Upgrade struct {
+	Key         string     `json:"key"`
+	DisplayName string     `json:"display_name"`
+	MaxTier     int        `json:"max_tier"` // 0 if unknown
+	Tiers       []TierCost `json:"tiers"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CostOf returns the cost of reaching a tier, if known
+func (u *Upgrade) CostOf(tier int) (*TierCost, bool) {
+	for i := range u.Tiers {
+		if u.Tiers[i].Tier == tier {
+			return &u.Tiers[i], true
+		}
+	}
+	return nil, false
+}
+
+type upgradeRow struct {
+	Key         string    `db:"upgrade_key"`
+	DisplayName string    `db:"display_name"`
+	MaxTier     int       `db:"max_tier"`
+	Tiers       []byte    `db:"tiers"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// GetUpgrades returns the metadata for every upgrade, keyed by upgrade key
+func (d *DB) GetUpgrades(ctx context.Context) (map[string]*Upgrade, error) {
+	var rows []upgradeRow
+	query := `SELECT upgrade_key, display_name, max_tier, tiers, updated_at FROM upgrade_metadata`
+	if err := d.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	upgrades := make(map[string]*Upgrade, len(rows))
+	for _, row := range rows {
+		u := &Upgrade{
+			Key:         row.Key,
+			DisplayName: row.DisplayName,
+			MaxTier:     row.MaxTier,
+			UpdatedAt:   row.UpdatedAt,
+		}
+		if err := json.Unmarshal(row.Tiers, &u.Tiers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tiers for %s: %w", row.Key, err)
+		}
+		upgrades[row.Key] = u
+	}
+	return upgrades, nil
+}
+
+// UpsertUpgrade creates or replaces the metadata for an upgrade
+func (d *DB) UpsertUpgrade(ctx context.Context, u *Upgrade) error {
+	if u.Tiers == nil {
+		u.Tiers = []TierCost{}
+	}
+	tiersJSON, err := json.Marshal(u.Tiers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tiers: %w", err)
+	}
+	if u.DisplayName == "" {
+		u.DisplayName = DisplayName(u.Key)
+	}
+
+	query := `
+		INSERT INTO upgrade_metadata (upgrade_key, display_name, max_tier, tiers, updated_at)
+		VALUES (?, ?, ?, ?, NOW())
+		ON CONFLICT (upgrade_key) DO UPDATE SET
+			display_name = EXCLUDED.display_name,
+			max_tier = EXCLUDED.max_tier,
+			tiers = EXCLUDED.tiers,
+			updated_at = NOW()
+	`
+	query = d.db.Rebind(query)
+	_, err = d.db.ExecContext(ctx, query, u.Key, u.DisplayName, u.MaxTier, tiersJSON)
+	return err
+}
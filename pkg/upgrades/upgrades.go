@@ -0,0 +1,206 @@
+package upgrades
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/market"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Keys returns every upgrade key reported by the player API, sorted
+func Keys() []string {
+	tiers, _ := PlayerTiers(&idleclans.Player{})
+	keys := make([]string, 0, len(tiers))
+	for key := range tiers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PlayerTiers returns a player's current tier for each upgrade, keyed by the API upgrade key
+func PlayerTiers(player *idleclans.Player) (map[string]int, error) {
+	// Round-trip through JSON so the keys match the API
+	b, err := json.Marshal(player.Upgrades)
+	if err != nil {
+		return nil, err
+	}
+	tiers := make(map[string]int)
+	if err := json.Unmarshal(b, &tiers); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// DisplayName converts an upgrade key such as "keepItSpacious" or "ammo-saver" to "Keep It Spacious"
+func DisplayName(key string) string {
+	var sb strings.Builder
+	for i, r := range key {
+		switch {
+		case r == '-':
+			sb.WriteRune(' ')
+			continue
+		case i > 0 && r >= 'A' && r <= 'Z':
+			sb.WriteRune(' ')
+		}
+		sb.WriteRune(r)
+	}
+	return cases.Title(language.English).String(sb.String())
+}
+
+// MaterialCost is a material required for the next tier, priced at the latest market sell price
+type MaterialCost struct {
+	NameID    string `json:"name_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int    `json:"unit_price"`
+	Total     int64  `json:"total"`
+	Priced    bool   `json:"priced"`
+}
+
+// NextTier is the cost of the next tier of an upgrade
+type NextTier struct {
+	Tier        int            `json:"tier"`
+	GoldCost    int64          `json:"gold_cost"`
+	Materials   []MaterialCost `json:"materials"`
+	TotalCost   int64          `json:"total_cost"`   // Gold plus priced materials
+	FullyPriced bool           `json:"fully_priced"` // Every material has a market price
+}
+
+// Progress is a player's progress on a single upgrade
+type Progress struct {
+	Key         string    `json:"key"`
+	DisplayName string    `json:"display_name"`
+	Tier        int       `json:"tier"`
+	MaxTier     int       `json:"max_tier"` // 0 if unknown
+	Maxed       bool      `json:"maxed"`
+	Next        *NextTier `json:"next,omitempty"` // nil if maxed or the cost is unknown
+}
+
+// Report is a player's upgrade progress and recommended next upgrades
+type Report struct {
+	PlayerName      string     `json:"player_name"`
+	Upgrades        []Progress `json:"upgrades"`
+	Recommendations []Progress `json:"recommendations"` // Cheapest next tiers first
+}
+
+// BuildReport compares a player's tiers against the upgrade metadata and prices the next tier
+// of each upgrade. Recommendations rank upgrades by the total cost of their next tier.
+// prices may be nil, in which case only gold costs are counted.
+func (d *DB) BuildReport(ctx context.Context, prices *market.DB, playerName string, player *idleclans.Player) (*Report, error) {
+	tiers, err := PlayerTiers(player)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := d.GetUpgrades(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upgrade metadata: %w", err)
+	}
+
+	report := &Report{
+		PlayerName:      playerName,
+		Upgrades:        make([]Progress, 0, len(tiers)),
+		Recommendations: []Progress{},
+	}
+	unitPrices := make(map[string]*int)
+
+	for _, key := range Keys() {
+		progress := Progress{
+			Key:         key,
+			DisplayName: DisplayName(key),
+			Tier:        tiers[key],
+		}
+
+		if meta, ok := metadata[key]; ok {
+			progress.DisplayName = meta.DisplayName
+			progress.MaxTier = meta.MaxTier
+			progress.Maxed = meta.MaxTier > 0 && progress.Tier >= meta.MaxTier
+
+			if cost, ok := meta.CostOf(progress.Tier + 1); ok && !progress.Maxed {
+				next, err := priceTier(ctx, prices, cost, unitPrices)
+				if err != nil {
+					return nil, err
+				}
+				progress.Next = next
+			}
+		}
+
+		report.Upgrades = append(report.Upgrades, progress)
+		if progress.Next != nil {
+			report.Recommendations = append(report.Recommendations, progress)
+		}
+	}
+
+	sort.SliceStable(report.Recommendations, func(i, j int) bool {
+		a, b := report.Recommendations[i].Next, report.Recommendations[j].Next
+		// Fully priced tiers first, since their totals are reliable
+		if a.FullyPriced != b.FullyPriced {
+			return a.FullyPriced
+		}
+		return a.TotalCost < b.TotalCost
+	})
+
+	return report, nil
+}
+
+// priceTier prices a tier's materials at the latest market sell price, caching unit prices by name_id
+func priceTier(ctx context.Context, prices *market.DB, cost *TierCost, unitPrices map[string]*int) (*NextTier, error) {
+	next := &NextTier{
+		Tier:        cost.Tier,
+		GoldCost:    cost.GoldCost,
+		Materials:   make([]MaterialCost, 0, len(cost.Materials)),
+		TotalCost:   cost.GoldCost,
+		FullyPriced: true,
+	}
+
+	nameIDs := make([]string, 0, len(cost.Materials))
+	for nameID := range cost.Materials {
+		nameIDs = append(nameIDs, nameID)
+	}
+	sort.Strings(nameIDs)
+
+	for _, nameID := range nameIDs {
+		material := MaterialCost{
+			NameID:   nameID,
+			Name:     idleclans.ItemDisplayName(nameID),
+			Quantity: cost.Materials[nameID],
+		}
+
+		unitPrice, cached := unitPrices[nameID]
+		if !cached && prices != nil {
+			item, err := prices.GetItemByNameID(ctx, nameID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up item %s: %w", nameID, err)
+			}
+			if item != nil {
+				price, err := prices.GetLatestPrice(ctx, item.ID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get price for %s: %w", nameID, err)
+				}
+				if price != nil && price.LowestSellPrice > 0 {
+					unitPrice = &price.LowestSellPrice
+				}
+			}
+			unitPrices[nameID] = unitPrice
+		}
+
+		if unitPrice != nil {
+			material.Priced = true
+			material.UnitPrice = *unitPrice
+			material.Total = int64(*unitPrice) * int64(material.Quantity)
+			next.TotalCost += material.Total
+		} else {
+			next.FullyPriced = false
+		}
+		next.Materials = append(next.Materials, material)
+	}
+
+	return next, nil
+}
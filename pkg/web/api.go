@@ -17,7 +17,6 @@ import (
 	_ "image/gif"
 	_ "image/jpeg"
 
-	"github.com/jirwin/idleclans/pkg/openai"
 	"github.com/jirwin/idleclans/pkg/quests"
 	"go.uber.org/zap"
 )
@@ -917,7 +916,21 @@ func (s *Server) handleGetClanPlayers(w http.ResponseWriter, r *http.Request) {
 
 // PlanRequest represents a request to generate a party plan
 type PlanRequest struct {
-	OnlinePlayers []string `json:"online_players"` // Optional filter for online players
+	OnlinePlayers  []string `json:"online_players"`            // Optional filter for online players
+	FairnessWeight *float64 `json:"fairness_weight,omitempty"` // Optional override of the configured planner fairness
+}
+
+// newPlanner creates a planner with the configured fairness weight, or the request's override
+func (s *Server) newPlanner(fairnessWeight *float64) *quests.Planner {
+	planner := quests.NewPlanner(s.db)
+	if fairnessWeight != nil {
+		planner.SetFairnessWeight(*fairnessWeight)
+	} else {
+		planner.SetFairnessWeight(s.config.PlannerFairness)
+	}
+	planner.SetObjective(s.config.PlannerObjective)
+	planner.SetSearchBudget(s.config.PlannerSearchBudget)
+	return planner
 }
 
 // PlanPartyTask represents a task in a party
@@ -927,18 +940,72 @@ type PlanPartyTask struct {
 	KeyHolder string `json:"key_holder,omitempty"`
 	KeyType   string `json:"key_type"`
 	NoKeys    bool   `json:"no_keys"`
+
+	Explanation *PlanExplanation `json:"explanation,omitempty"` // Why the task is planned this way
+}
+
+// PlanExplanation says why the planner made a choice and what else it considered
+type PlanExplanation struct {
+	Code         string   `json:"code"`
+	Reason       string   `json:"reason"`
+	Alternatives []string `json:"alternatives,omitempty"`
+}
+
+// newPlanExplanation converts a planner explanation to the API type
+func newPlanExplanation(e quests.Explanation) *PlanExplanation {
+	if e.Code == "" {
+		return nil
+	}
+	return &PlanExplanation{
+		Code:         e.Code,
+		Reason:       e.Reason,
+		Alternatives: e.Alternatives,
+	}
+}
+
+// planLeftoverReasons converts the reasons a leftover's kills weren't planned to the API type
+func planLeftoverReasons(unmet map[string]quests.Explanation) map[string]PlanExplanation {
+	reasons := make(map[string]PlanExplanation, len(unmet))
+	for boss, e := range unmet {
+		if explanation := newPlanExplanation(e); explanation != nil {
+			reasons[boss] = *explanation
+		}
+	}
+	return reasons
 }
 
 // PlanParty represents a party in the plan
 type PlanParty struct {
-	Players []string        `json:"players"`
-	Tasks   []PlanPartyTask `json:"tasks"`
+	Players    []string            `json:"players"`
+	Tasks      []PlanPartyTask     `json:"tasks"`
+	Violations []PlanSizeViolation `json:"violations,omitempty"` // Bosses the party is too small or too large for
+}
+
+// PlanSizeViolation is a boss whose party size limit a planned party breaks
+type PlanSizeViolation struct {
+	BossName string `json:"boss_name"`
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+}
+
+// planSizeViolations converts the planner's party size violations to API types
+func planSizeViolations(violations []quests.PartyLimitViolation) []PlanSizeViolation {
+	var result []PlanSizeViolation
+	for _, v := range violations {
+		result = append(result, PlanSizeViolation{
+			BossName: v.BossName,
+			Min:      v.Limit.Min,
+			Max:      v.Limit.Max,
+		})
+	}
+	return result
 }
 
 // PlanLeftover represents a player with unmet needs
 type PlanLeftover struct {
-	PlayerName string         `json:"player_name"`
-	Needs      map[string]int `json:"needs"` // boss -> kills needed
+	PlayerName string                     `json:"player_name"`
+	Needs      map[string]int             `json:"needs"`             // boss -> kills needed
+	Reasons    map[string]PlanExplanation `json:"reasons,omitempty"` // boss -> why the kills weren't planned
 }
 
 // PlanData represents the full plan response
@@ -960,7 +1027,7 @@ func (s *Server) handleGetClanPlan(w http.ResponseWriter, r *http.Request) {
 		json.NewDecoder(r.Body).Decode(&req) // Ignore errors, use defaults
 	}
 
-	planner := quests.NewPlanner(s.db)
+	planner := s.newPlanner(req.FairnessWeight)
 	plan, err := planner.GeneratePlanFiltered(ctx, week, year, req.OnlinePlayers)
 	if err != nil {
 		s.logger.Error("Failed to generate plan", zap.Error(err))
@@ -979,11 +1046,14 @@ func (s *Server) handleGetClanPlan(w http.ResponseWriter, r *http.Request) {
 				KeyHolder: t.KeyHolder,
 				KeyType:   t.KeyType,
 				NoKeys:    t.NoKeys,
+
+				Explanation: newPlanExplanation(t.Explanation),
 			})
 		}
 		parties = append(parties, PlanParty{
-			Players: p.Players,
-			Tasks:   tasks,
+			Players:    p.Players,
+			Tasks:      tasks,
+			Violations: planSizeViolations(p.Violations),
 		})
 	}
 
@@ -1000,6 +1070,7 @@ func (s *Server) handleGetClanPlan(w http.ResponseWriter, r *http.Request) {
 			leftovers = append(leftovers, PlanLeftover{
 				PlayerName: l.Name,
 				Needs:      needs,
+				Reasons:    planLeftoverReasons(l.Unmet),
 			})
 		}
 	}
@@ -1017,8 +1088,9 @@ func (s *Server) handleGetClanPlan(w http.ResponseWriter, r *http.Request) {
 
 // SendPlanRequest represents a request to send a plan to Discord
 type SendPlanRequest struct {
-	Players []string `json:"players"` // Player names to include in the plan
-	NoPing  bool     `json:"no_ping"` // If true, don't ping users
+	Players        []string `json:"players"`                   // Player names to include in the plan
+	NoPing         bool     `json:"no_ping"`                   // If true, don't ping users
+	FairnessWeight *float64 `json:"fairness_weight,omitempty"` // Optional override of the configured planner fairness
 }
 
 // handleSendPlanToDiscord sends a plan message to Discord as an embed
@@ -1048,7 +1120,7 @@ func (s *Server) handleSendPlanToDiscord(w http.ResponseWriter, r *http.Request)
 
 	// Generate the plan
 	week, year := getWeekAndYear()
-	planner := quests.NewPlanner(s.db)
+	planner := s.newPlanner(req.FairnessWeight)
 	plan, err := planner.GeneratePlanFiltered(ctx, week, year, req.Players)
 	if err != nil {
 		s.logger.Error("Failed to generate plan", zap.Error(err))
@@ -1097,8 +1169,15 @@ func (s *Server) handleSendPlanToDiscord(w http.ResponseWriter, r *http.Request)
 		var taskLines []string
 		for _, task := range tasksWithKeys {
 			bossLabel := strings.Title(task.BossName)
+			if task.KeyHolder == "" {
+				taskLines = append(taskLines, fmt.Sprintf("• %s: %d", bossLabel, task.Kills))
+				continue
+			}
 			taskLines = append(taskLines, fmt.Sprintf("• %s: %d (Key: %s)", bossLabel, task.Kills, task.KeyHolder))
 		}
+		for _, v := range party.Violations {
+			taskLines = append(taskLines, fmt.Sprintf("⚠️ %s allows %s", strings.Title(v.BossName), v.Limit))
+		}
 
 		fields = append(fields, DiscordEmbedField{
 			Name:   strings.Join(party.Players, ", "),
@@ -1533,47 +1612,15 @@ func (s *Server) handleAnalyzeQuests(w http.ResponseWriter, r *http.Request) {
 	// Encode to base64
 	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
 
-	// Call OpenAI with JSON mode
-	resp, err := s.openaiClient.ChatCompletionWithImageJSON(
-		questAnalysisPrompt,
-		"Please analyze this quest tracker screenshot and extract the boss kill requirements.",
-		imageBase64,
-		imageType,
-		true, // Force JSON output
-	)
+	results, err := s.analyzeQuestImage(imageBase64, imageType)
 	if err != nil {
 		s.logger.Error("Failed to analyze quest screenshot", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(AnalyzeQuestsResponse{Error: "Failed to analyze image: " + err.Error()})
+		json.NewEncoder(w).Encode(AnalyzeQuestsResponse{Error: analysisErrorMessage(err)})
 		return
 	}
 
-	// Parse LLM response
-	content := extractJSON(resp.Choices[0].Message.Content)
-	var llmResp llmQuestResponse
-	if err := json.Unmarshal([]byte(content), &llmResp); err != nil {
-		s.logger.Error("Failed to parse LLM response",
-			zap.Error(err),
-			zap.String("content", resp.Choices[0].Message.Content))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(AnalyzeQuestsResponse{Error: "Failed to parse analysis result"})
-		return
-	}
-
-	// Validate and convert results
-	results := make([]BossKillResult, 0)
-	for _, b := range llmResp.Bosses {
-		bossName := strings.ToLower(strings.TrimSpace(b.Name))
-		if quests.IsValidBoss(bossName) && b.Kills > 0 {
-			results = append(results, BossKillResult{
-				Name:  bossName,
-				Kills: b.Kills,
-			})
-		}
-	}
-
 	response := AnalyzeQuestsResponse{
 		Bosses:  results,
 		Applied: false,
@@ -1690,60 +1737,15 @@ func (s *Server) handleAnalyzeKeys(w http.ResponseWriter, r *http.Request) {
 	// Encode to base64
 	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
 
-	// Call OpenAI with JSON mode - use reference images if available
-	var resp *openai.ChatResponse
-	if s.keyReferenceImages != nil && s.keyReferenceImages.HasImages() {
-		resp, err = s.openaiClient.ChatCompletionWithReferences(
-			keyAnalysisPromptWithRefs,
-			"Analyze this key inventory screenshot. Match keys to the reference images provided.",
-			s.keyReferenceImages.GetImages(),
-			imageBase64,
-			imageType,
-			true, // Force JSON output
-		)
-	} else {
-		resp, err = s.openaiClient.ChatCompletionWithImageJSON(
-			keyAnalysisPrompt,
-			"Please analyze this key inventory screenshot and extract the key counts.",
-			imageBase64,
-			imageType,
-			true, // Force JSON output
-		)
-	}
+	results, err := s.analyzeKeyImage(imageBase64, imageType)
 	if err != nil {
 		s.logger.Error("Failed to analyze key screenshot", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(AnalyzeKeysResponse{Error: "Failed to analyze image: " + err.Error()})
+		json.NewEncoder(w).Encode(AnalyzeKeysResponse{Error: analysisErrorMessage(err)})
 		return
 	}
 
-	// Parse LLM response
-	content := extractJSON(resp.Choices[0].Message.Content)
-	var llmResp llmKeyResponse
-	if err := json.Unmarshal([]byte(content), &llmResp); err != nil {
-		s.logger.Error("Failed to parse LLM response",
-			zap.Error(err),
-			zap.String("content", resp.Choices[0].Message.Content))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(AnalyzeKeysResponse{Error: "Failed to parse analysis result"})
-		return
-	}
-
-	// Validate and convert results
-	// Filter out invalid counts (> 300 is likely misdetection of non-key items)
-	results := make([]KeyCountResult, 0)
-	for _, k := range llmResp.Keys {
-		keyType, ok := quests.ResolveKeyType(k.Type)
-		if ok && k.Count > 0 && k.Count <= 300 {
-			results = append(results, KeyCountResult{
-				Type:  keyType,
-				Count: k.Count,
-			})
-		}
-	}
-
 	response := AnalyzeKeysResponse{
 		Keys:    results,
 		Applied: false,
@@ -1984,47 +1986,15 @@ func (s *Server) handleAdminAnalyzeQuests(w http.ResponseWriter, r *http.Request
 	// Encode to base64
 	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
 
-	// Call OpenAI with JSON mode
-	resp, err := s.openaiClient.ChatCompletionWithImageJSON(
-		questAnalysisPrompt,
-		"Please analyze this quest tracker screenshot and extract the boss kill requirements.",
-		imageBase64,
-		imageType,
-		true, // Force JSON output
-	)
+	results, err := s.analyzeQuestImage(imageBase64, imageType)
 	if err != nil {
 		s.logger.Error("Failed to analyze quest screenshot", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(AnalyzeQuestsResponse{Error: "Failed to analyze image: " + err.Error()})
-		return
-	}
-
-	// Parse LLM response
-	content := extractJSON(resp.Choices[0].Message.Content)
-	var llmResp llmQuestResponse
-	if err := json.Unmarshal([]byte(content), &llmResp); err != nil {
-		s.logger.Error("Failed to parse LLM response",
-			zap.Error(err),
-			zap.String("content", resp.Choices[0].Message.Content))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(AnalyzeQuestsResponse{Error: "Failed to parse analysis result"})
+		json.NewEncoder(w).Encode(AnalyzeQuestsResponse{Error: analysisErrorMessage(err)})
 		return
 	}
 
-	// Validate and convert results
-	results := make([]BossKillResult, 0)
-	for _, b := range llmResp.Bosses {
-		bossName := strings.ToLower(strings.TrimSpace(b.Name))
-		if quests.IsValidBoss(bossName) && b.Kills > 0 {
-			results = append(results, BossKillResult{
-				Name:  bossName,
-				Kills: b.Kills,
-			})
-		}
-	}
-
 	response := AnalyzeQuestsResponse{
 		Bosses:  results,
 		Applied: false,
@@ -2100,63 +2070,20 @@ func (s *Server) handleAdminAnalyzeKeys(w http.ResponseWriter, r *http.Request)
 
 	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
 
-	// Single pass with JSON mode using OpenAI - use reference images if available
-	var resp *openai.ChatResponse
+	// Single pass using OpenAI - use reference images if available
 	if s.keyReferenceImages != nil && s.keyReferenceImages.HasImages() {
 		s.logger.Info("Using reference images for key analysis",
 			zap.Int("ref_count", len(s.keyReferenceImages.GetImages())))
-		resp, err = s.openaiClient.ChatCompletionWithReferences(
-			keyAnalysisPromptWithRefs,
-			"Analyze this key inventory screenshot. Match keys to the reference images provided.",
-			s.keyReferenceImages.GetImages(),
-			imageBase64,
-			imageType,
-			true, // Force JSON output
-		)
-	} else {
-		resp, err = s.openaiClient.ChatCompletionWithImageJSON(
-			keyAnalysisPrompt,
-			"Please analyze this key inventory screenshot and extract the key counts.",
-			imageBase64,
-			imageType,
-			true, // Force JSON output
-		)
 	}
+	results, err := s.analyzeKeyImage(imageBase64, imageType)
 	if err != nil {
 		s.logger.Error("Failed to analyze keys", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(AnalyzeKeysResponse{Error: "Failed to analyze image: " + err.Error()})
-		return
-	}
-
-	content := extractJSON(resp.Choices[0].Message.Content)
-	s.logger.Info("Key analysis response", zap.String("content", content))
-
-	var llmResp llmKeyResponse
-	if err := json.Unmarshal([]byte(content), &llmResp); err != nil {
-		s.logger.Error("Failed to parse JSON response",
-			zap.Error(err),
-			zap.String("content", resp.Choices[0].Message.Content))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(AnalyzeKeysResponse{Error: "Failed to parse analysis result"})
+		json.NewEncoder(w).Encode(AnalyzeKeysResponse{Error: analysisErrorMessage(err)})
 		return
 	}
 
-	// Validate and convert results
-	// Filter out invalid counts (> 300 is likely misdetection of non-key items)
-	results := make([]KeyCountResult, 0)
-	for _, k := range llmResp.Keys {
-		keyType, ok := quests.ResolveKeyType(k.Type)
-		if ok && k.Count > 0 && k.Count <= 300 {
-			results = append(results, KeyCountResult{
-				Type:  keyType,
-				Count: k.Count,
-			})
-		}
-	}
-
 	s.logger.Info("Key analysis complete", zap.Int("keys_found", len(results)))
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,78 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+// handleAdminGetBosses returns the boss catalog
+func (s *Server) handleAdminGetBosses(w http.ResponseWriter, r *http.Request) {
+	bosses, err := s.db.GetBossCatalog(r.Context())
+	if err != nil {
+		s.logger.Error("Failed to get boss catalog", zap.Error(err))
+		http.Error(w, "Failed to get boss catalog", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bosses)
+}
+
+// handleAdminUpdateBoss adds a boss to the catalog or replaces it
+func (s *Server) handleAdminUpdateBoss(w http.ResponseWriter, r *http.Request) {
+	var b quests.Boss
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	b.Name = r.PathValue("name")
+
+	saved, err := s.db.SaveBoss(r.Context(), b)
+	if errors.Is(err, quests.ErrInvalidBoss) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to save boss", zap.Error(err), zap.String("boss", b.Name))
+		http.Error(w, "Failed to save boss", http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Info("Admin saved boss",
+		zap.String("boss", saved.Name),
+		zap.String("key_type", saved.KeyType),
+		zap.Bool("raid", saved.Raid),
+	)
+
+	s.NotifyDataChange("bosses")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// handleAdminDeleteBoss removes a boss from the catalog. Quests recorded for it are kept.
+func (s *Server) handleAdminDeleteBoss(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	deleted, err := s.db.DeleteBoss(r.Context(), name)
+	if err != nil {
+		s.logger.Error("Failed to delete boss", zap.Error(err), zap.String("boss", name))
+		http.Error(w, "Failed to delete boss", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Boss not found", http.StatusNotFound)
+		return
+	}
+
+	s.logger.Info("Admin deleted boss", zap.String("boss", name))
+
+	s.NotifyDataChange("bosses")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,151 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+)
+
+// competitionStandingsShown is how many places are listed in Discord standings posts
+const competitionStandingsShown = 10
+
+// CompetitionResponse is a competition with its current standings
+type CompetitionResponse struct {
+	Competition *tracker.Competition `json:"competition"`
+	Standings   []tracker.Standing   `json:"standings"`
+}
+
+// handleGetCompetitions returns the most recent competitions
+func (s *Server) handleGetCompetitions(w http.ResponseWriter, r *http.Request) {
+	competitions, err := s.trackerDB.GetCompetitions(r.Context(), 50)
+	if err != nil {
+		s.logger.Error("Failed to get competitions", zap.Error(err))
+		http.Error(w, "Failed to get competitions", http.StatusInternalServerError)
+		return
+	}
+	if competitions == nil {
+		competitions = []tracker.Competition{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(competitions)
+}
+
+// handleGetCompetition returns a competition and its leaderboard
+func (s *Server) handleGetCompetition(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid competition ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	competition, err := s.trackerDB.GetCompetition(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get competition", zap.Error(err), zap.Int("id", id))
+		http.Error(w, "Failed to get competition", http.StatusInternalServerError)
+		return
+	}
+	if competition == nil {
+		http.Error(w, "Competition not found", http.StatusNotFound)
+		return
+	}
+
+	standings, err := s.trackerDB.GetStandings(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get competition standings", zap.Error(err), zap.Int("id", id))
+		http.Error(w, "Failed to get competition standings", http.StatusInternalServerError)
+		return
+	}
+	if standings == nil {
+		standings = []tracker.Standing{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(CompetitionResponse{
+		Competition: competition,
+		Standings:   standings,
+	})
+}
+
+// CompetitionDiscordNotifier implements tracker.CompetitionNotifier to post standings to Discord
+type CompetitionDiscordNotifier struct {
+	sender    DiscordMessageSender
+	channelID string // Fallback when the competition has no channel
+	baseURL   string
+	logger    *zap.Logger
+}
+
+// NotifyCompetitionStandings posts the current standings, or the winners once a competition ends
+func (n *CompetitionDiscordNotifier) NotifyCompetitionStandings(ctx context.Context, competition *tracker.Competition, standings []tracker.Standing, final bool) error {
+	channelID := competition.ChannelID
+	if channelID == "" {
+		channelID = n.channelID
+	}
+	if n.sender == nil || channelID == "" {
+		return nil // No Discord configured
+	}
+
+	unit := "XP"
+	if competition.MetricType == tracker.MetricBoss {
+		unit = "kills"
+	}
+
+	var sb strings.Builder
+	for i, standing := range standings {
+		if i >= competitionStandingsShown {
+			sb.WriteString(fmt.Sprintf("*...and %d more*\n", len(standings)-competitionStandingsShown))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("**%d.** %s — %s %s\n", standing.Rank, standing.PlayerName, formatPrice(int(standing.Gain)), unit))
+	}
+	if len(standings) == 0 {
+		sb.WriteString("No participants yet.")
+	}
+
+	title := fmt.Sprintf("%s Standings", competition.Name)
+	content := ""
+	color := 0x3498db // Blue color
+	if final {
+		title = fmt.Sprintf("%s Results", competition.Name)
+		color = 0xf1c40f // Gold color
+
+		var winners []string
+		for _, standing := range standings {
+			if standing.Rank != 1 || standing.Gain <= 0 {
+				break
+			}
+			winners = append(winners, "**"+standing.PlayerName+"**")
+		}
+		if len(winners) > 0 {
+			content = fmt.Sprintf("🏆 %s won **%s** with %s %s!", strings.Join(winners, " & "), competition.Name,
+				formatPrice(int(standings[0].Gain)), unit)
+		}
+	}
+
+	embed := &DiscordEmbed{
+		Title:       title,
+		Description: sb.String(),
+		Color:       color,
+		Fields: []DiscordEmbedField{
+			{
+				Name:   "Ends",
+				Value:  fmt.Sprintf("<t:%d:R>", competition.EndsAt.Unix()),
+				Inline: true,
+			},
+			{
+				Name:   "Leaderboard",
+				Value:  fmt.Sprintf("[View live](%s/competitions?id=%d)", n.baseURL, competition.ID),
+				Inline: true,
+			},
+		},
+	}
+
+	return n.sender.SendMessageWithEmbed(channelID, content, embed)
+}
@@ -71,6 +71,13 @@ func (s *Server) serveStaticFile(w http.ResponseWriter, r *http.Request, isAdmin
 				}
 			}
 		}
+
+		// Player pages unfurl with the rendered player card
+		if r.URL.Path == "/player" {
+			if playerName := r.URL.Query().Get("name"); playerName != "" {
+				contentStr = strings.Replace(contentStr, "</head>", s.generatePlayerOGTags(r.Context(), playerName)+"</head>", 1)
+			}
+		}
 		
 		// Inject admin mode flag if needed
 		if isAdmin {
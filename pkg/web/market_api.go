@@ -3,6 +3,7 @@ package web
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -707,15 +708,15 @@ func (m *MarketAPI) handleCreateWatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check watch limit (max 10 active watches per user)
+	// Check watch limit
 	count, err := m.db.GetWatchCountByUser(ctx, session.UserID)
 	if err != nil {
 		m.logger.Error("Failed to get watch count", zap.Error(err))
 		http.Error(w, "Internal error", http.StatusInternalServerError)
 		return
 	}
-	if count >= 10 {
-		http.Error(w, "Maximum of 10 active watches allowed", http.StatusBadRequest)
+	if count >= market.MaxWatchesPerUser {
+		http.Error(w, fmt.Sprintf("Maximum of %d active watches allowed", market.MaxWatchesPerUser), http.StatusBadRequest)
 		return
 	}
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/jirwin/idleclans/pkg/market"
+	"github.com/jirwin/idleclans/pkg/render"
 	"go.uber.org/zap"
 )
 
@@ -89,18 +90,18 @@ func generateMarketItemPNG(displayName string, currentPrice *market.PriceSnapsho
 	}
 
 	// Draw border
-	drawRect(img, 0, 0, width-1, height-1, borderColor)
-	drawRect(img, 1, 1, width-2, height-2, borderColor)
+	render.DrawRect(img, 0, 0, width-1, height-1, borderColor)
+	render.DrawRect(img, 1, 1, width-2, height-2, borderColor)
 
 	// Draw chart area background (slightly lighter)
 	chartBg := color.RGBA{13, 19, 33, 255} // #0d1321
-	fillRect(img, chartMarginLeft, chartMarginTop, chartMarginLeft+chartWidth, chartMarginTop+chartHeight, chartBg)
+	render.FillRect(img, chartMarginLeft, chartMarginTop, chartMarginLeft+chartWidth, chartMarginTop+chartHeight, chartBg)
 
 	// Draw chart grid lines
 	gridColor := color.RGBA{31, 41, 55, 128} // #1f2937 with alpha
 	for i := 0; i <= 4; i++ {
 		y := chartMarginTop + (chartHeight * i / 4)
-		drawHLine(img, chartMarginLeft, chartMarginLeft+chartWidth, y, gridColor)
+		render.DrawHLine(img, chartMarginLeft, chartMarginLeft+chartWidth, y, gridColor)
 	}
 
 	// Draw chart if we have history
@@ -150,7 +151,7 @@ func generateMarketItemPNG(displayName string, currentPrice *market.PriceSnapsho
 		}
 
 		// Build points for the chart line
-		var points []point
+		var points []render.Point
 		for i, h := range history {
 			if h.LowestSellPrice > 0 {
 				x := chartMarginLeft + int(float64(i)/float64(len(history)-1)*float64(chartWidth))
@@ -162,7 +163,7 @@ func generateMarketItemPNG(displayName string, currentPrice *market.PriceSnapsho
 				if y > chartMarginTop+chartHeight {
 					y = chartMarginTop + chartHeight
 				}
-				points = append(points, point{x, y})
+				points = append(points, render.Point{X: x, Y: y})
 			}
 		}
 
@@ -171,10 +172,10 @@ func generateMarketItemPNG(displayName string, currentPrice *market.PriceSnapsho
 			bottomY := chartMarginTop + chartHeight
 			for i := 0; i < len(points)-1; i++ {
 				// Fill vertical strips
-				x1 := points[i].x
-				x2 := points[i+1].x
-				y1 := points[i].y
-				y2 := points[i+1].y
+				x1 := points[i].X
+				x2 := points[i+1].X
+				y1 := points[i].Y
+				y2 := points[i+1].Y
 				
 				for x := x1; x <= x2; x++ {
 					// Interpolate y
@@ -184,127 +185,28 @@ func generateMarketItemPNG(displayName string, currentPrice *market.PriceSnapsho
 						// Gradient fill - more opaque at top
 						alpha := uint8(50 - 40*float64(y-topY)/float64(bottomY-topY))
 						c := color.RGBA{lineColor.R, lineColor.G, lineColor.B, alpha}
-						blendPixel(img, x, y, c)
+						render.BlendPixel(img, x, y, c)
 					}
 				}
 			}
 
 			// Draw the line (thicker)
 			for i := 0; i < len(points)-1; i++ {
-				drawThickLine(img, points[i].x, points[i].y, points[i+1].x, points[i+1].y, lineColor, 3)
+				render.DrawThickLine(img, points[i].X, points[i].Y, points[i+1].X, points[i+1].Y, lineColor, 3)
 			}
 		}
 
 		// Draw price indicator dot at the end
 		if len(points) > 0 {
 			lastPoint := points[len(points)-1]
-			drawFilledCircle(img, lastPoint.x, lastPoint.y, 6, lineColor)
-			drawFilledCircle(img, lastPoint.x, lastPoint.y, 3, textColor)
+			render.DrawFilledCircle(img, lastPoint.X, lastPoint.Y, 6, lineColor)
+			render.DrawFilledCircle(img, lastPoint.X, lastPoint.Y, 3, textColor)
 		}
 	}
 
 	// Draw axis lines
-	drawHLine(img, chartMarginLeft, chartMarginLeft+chartWidth, chartMarginTop+chartHeight, borderColor)
-	drawVLine(img, chartMarginLeft, chartMarginTop, chartMarginTop+chartHeight, borderColor)
+	render.DrawHLine(img, chartMarginLeft, chartMarginLeft+chartWidth, chartMarginTop+chartHeight, borderColor)
+	render.DrawVLine(img, chartMarginLeft, chartMarginTop, chartMarginTop+chartHeight, borderColor)
 
 	return img
 }
-
-type point struct {
-	x, y int
-}
-
-// Drawing helper functions
-
-func fillRect(img *image.RGBA, x1, y1, x2, y2 int, c color.Color) {
-	for y := y1; y <= y2; y++ {
-		for x := x1; x <= x2; x++ {
-			img.Set(x, y, c)
-		}
-	}
-}
-
-func drawRect(img *image.RGBA, x1, y1, x2, y2 int, c color.Color) {
-	drawHLine(img, x1, x2, y1, c)
-	drawHLine(img, x1, x2, y2, c)
-	drawVLine(img, x1, y1, y2, c)
-	drawVLine(img, x2, y1, y2, c)
-}
-
-func drawHLine(img *image.RGBA, x1, x2, y int, c color.Color) {
-	for x := x1; x <= x2; x++ {
-		img.Set(x, y, c)
-	}
-}
-
-func drawVLine(img *image.RGBA, x, y1, y2 int, c color.Color) {
-	for y := y1; y <= y2; y++ {
-		img.Set(x, y, c)
-	}
-}
-
-func drawThickLine(img *image.RGBA, x1, y1, x2, y2 int, c color.Color, thickness int) {
-	// Bresenham's line algorithm with thickness
-	dx := abs(x2 - x1)
-	dy := abs(y2 - y1)
-	sx := 1
-	if x1 >= x2 {
-		sx = -1
-	}
-	sy := 1
-	if y1 >= y2 {
-		sy = -1
-	}
-	err := dx - dy
-
-	for {
-		// Draw thick point
-		for ty := -thickness / 2; ty <= thickness/2; ty++ {
-			for tx := -thickness / 2; tx <= thickness/2; tx++ {
-				img.Set(x1+tx, y1+ty, c)
-			}
-		}
-
-		if x1 == x2 && y1 == y2 {
-			break
-		}
-		e2 := 2 * err
-		if e2 > -dy {
-			err -= dy
-			x1 += sx
-		}
-		if e2 < dx {
-			err += dx
-			y1 += sy
-		}
-	}
-}
-
-func drawFilledCircle(img *image.RGBA, cx, cy, r int, c color.Color) {
-	for y := -r; y <= r; y++ {
-		for x := -r; x <= r; x++ {
-			if x*x+y*y <= r*r {
-				img.Set(cx+x, cy+y, c)
-			}
-		}
-	}
-}
-
-func blendPixel(img *image.RGBA, x, y int, c color.RGBA) {
-	if x < 0 || y < 0 || x >= img.Bounds().Dx() || y >= img.Bounds().Dy() {
-		return
-	}
-	existing := img.RGBAAt(x, y)
-	alpha := float64(c.A) / 255.0
-	newR := uint8(float64(existing.R)*(1-alpha) + float64(c.R)*alpha)
-	newG := uint8(float64(existing.G)*(1-alpha) + float64(c.G)*alpha)
-	newB := uint8(float64(existing.B)*(1-alpha) + float64(c.B)*alpha)
-	img.Set(x, y, color.RGBA{newR, newG, newB, 255})
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
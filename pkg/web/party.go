@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -15,7 +16,8 @@ import (
 
 // PartyRequest represents a request to create a party
 type PartyRequest struct {
-	Players []string `json:"players"`
+	Players        []string `json:"players"`
+	FairnessWeight *float64 `json:"fairness_weight,omitempty"` // Optional override of the configured planner fairness
 }
 
 // PartyResponse represents the full party state returned to clients
@@ -81,77 +83,8 @@ func (s *Server) handleCreateParty(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	// Generate the plan for these players
-	week, year := getWeekAndYear()
-	planner := quests.NewPlanner(s.db)
-	plan, err := planner.GeneratePlanFiltered(ctx, week, year, req.Players)
-	if err != nil {
-		s.logger.Error("Failed to generate plan", zap.Error(err))
-		http.Error(w, "Failed to generate plan", http.StatusInternalServerError)
-		return
-	}
-
-	// Convert plan to API format
-	parties := make([]PlanParty, 0, len(plan.Parties))
-	for _, p := range plan.Parties {
-		tasks := make([]PlanPartyTask, 0, len(p.Tasks))
-		for _, t := range p.Tasks {
-			tasks = append(tasks, PlanPartyTask{
-				BossName:  t.BossName,
-				Kills:     t.Kills,
-				KeyHolder: t.KeyHolder,
-				KeyType:   t.KeyType,
-				NoKeys:    t.NoKeys,
-			})
-		}
-		parties = append(parties, PlanParty{
-			Players: p.Players,
-			Tasks:   tasks,
-		})
-	}
-
-	leftovers := make([]PlanLeftover, 0, len(plan.Leftovers))
-	for _, l := range plan.Leftovers {
-		needs := make(map[string]int)
-		for boss, n := range l.Needs {
-			if n > 0 {
-				needs[boss] = n
-			}
-		}
-		if len(needs) > 0 {
-			leftovers = append(leftovers, PlanLeftover{
-				PlayerName: l.Name,
-				Needs:      needs,
-			})
-		}
-	}
-
-	planData := PlanData{
-		Week:      week,
-		Year:      year,
-		Parties:   parties,
-		Leftovers: leftovers,
-	}
-
-	// Serialize plan data
-	planJSON, err := json.Marshal(planData)
-	if err != nil {
-		s.logger.Error("Failed to serialize plan", zap.Error(err))
-		http.Error(w, "Failed to create party", http.StatusInternalServerError)
-		return
-	}
-
-	// Serialize players
-	playersJSON, err := json.Marshal(req.Players)
+	partyID, err := s.CreatePartySession(ctx, req.Players, req.FairnessWeight)
 	if err != nil {
-		s.logger.Error("Failed to serialize players", zap.Error(err))
-		http.Error(w, "Failed to create party", http.StatusInternalServerError)
-		return
-	}
-
-	// Create party in database
-	partyID := generatePartyID()
-	if err := s.db.CreateParty(ctx, partyID, string(playersJSON), string(planJSON)); err != nil {
 		s.logger.Error("Failed to create party", zap.Error(err))
 		http.Error(w, "Failed to create party", http.StatusInternalServerError)
 		return
@@ -184,7 +117,7 @@ func (s *Server) handleCreateParty(w http.ResponseWriter, r *http.Request) {
 		pingContent := strings.Join(pings, " ")
 
 		// Build party URL
-		partyURL := fmt.Sprintf("%s/party/%s", s.config.BaseURL, partyID)
+		partyURL := s.PartyURL(partyID)
 
 		// Create embed
 		embed := &DiscordEmbed{
@@ -216,6 +149,89 @@ func (s *Server) handleCreateParty(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"id": partyID})
 }
 
+// CreatePartySession plans a party for the given players and saves it as a web party session,
+// returning the party ID. fairnessWeight optionally overrides the configured planner fairness.
+func (s *Server) CreatePartySession(ctx context.Context, players []string, fairnessWeight *float64) (string, error) {
+	// Generate the plan for these players
+	week, year := getWeekAndYear()
+	planner := s.newPlanner(fairnessWeight)
+	plan, err := planner.GeneratePlanFiltered(ctx, week, year, players)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate plan: %w", err)
+	}
+
+	// Convert plan to API format
+	parties := make([]PlanParty, 0, len(plan.Parties))
+	for _, p := range plan.Parties {
+		tasks := make([]PlanPartyTask, 0, len(p.Tasks))
+		for _, t := range p.Tasks {
+			tasks = append(tasks, PlanPartyTask{
+				BossName:  t.BossName,
+				Kills:     t.Kills,
+				KeyHolder: t.KeyHolder,
+				KeyType:   t.KeyType,
+				NoKeys:    t.NoKeys,
+
+				Explanation: newPlanExplanation(t.Explanation),
+			})
+		}
+		parties = append(parties, PlanParty{
+			Players:    p.Players,
+			Tasks:      tasks,
+			Violations: planSizeViolations(p.Violations),
+		})
+	}
+
+	leftovers := make([]PlanLeftover, 0, len(plan.Leftovers))
+	for _, l := range plan.Leftovers {
+		needs := make(map[string]int)
+		for boss, n := range l.Needs {
+			if n > 0 {
+				needs[boss] = n
+			}
+		}
+		if len(needs) > 0 {
+			leftovers = append(leftovers, PlanLeftover{
+				PlayerName: l.Name,
+				Needs:      needs,
+				Reasons:    planLeftoverReasons(l.Unmet),
+			})
+		}
+	}
+
+	planData := PlanData{
+		Week:      week,
+		Year:      year,
+		Parties:   parties,
+		Leftovers: leftovers,
+	}
+
+	// Serialize plan data
+	planJSON, err := json.Marshal(planData)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize plan: %w", err)
+	}
+
+	// Serialize players
+	playersJSON, err := json.Marshal(players)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize players: %w", err)
+	}
+
+	// Create party in database
+	partyID := generatePartyID()
+	if err := s.db.CreateParty(ctx, partyID, string(playersJSON), string(planJSON)); err != nil {
+		return "", err
+	}
+
+	return partyID, nil
+}
+
+// PartyURL returns the web page for a party session
+func (s *Server) PartyURL(partyID string) string {
+	return fmt.Sprintf("%s/party/%s", s.config.BaseURL, partyID)
+}
+
 // handleGetUserParties returns parties the authenticated user has been part of
 func (s *Server) handleGetUserParties(w http.ResponseWriter, r *http.Request) {
 	session := getSession(r)
@@ -663,12 +679,40 @@ func (s *Server) handleUpdatePartyKeys(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	progress, err := s.db.GetPartyStepProgress(ctx, partyID, party.CurrentStepIndex)
+	if err != nil {
+		s.logger.Error("Failed to get step progress", zap.Error(err))
+		http.Error(w, "Failed to update keys", http.StatusInternalServerError)
+		return
+	}
+	oldKeysUsed := 0
+	if progress != nil {
+		oldKeysUsed = progress.KeysUsed
+	}
+
 	if err := s.db.UpdatePartyStepKeys(ctx, partyID, party.CurrentStepIndex, req.KeysUsed); err != nil {
 		s.logger.Error("Failed to update keys", zap.Error(err))
 		http.Error(w, "Failed to update keys", http.StatusInternalServerError)
 		return
 	}
 
+	// Record the holder's keys spent on behalf of the party in the key ledger. Only the plan
+	// party the step belongs to benefits; a session can hold several parties.
+	beneficiaries := players
+	if stepParty := s.getPartyForStep(planData, party.CurrentStepIndex); stepParty != nil {
+		beneficiaries = stepParty.Players
+	}
+	if currentTask.KeyHolder != "" && currentTask.KeyType != "" {
+		if err := s.db.RecordKeyConsumption(ctx, partyID, party.CurrentStepIndex, currentTask.KeyType,
+			currentTask.KeyHolder, beneficiaries, req.KeysUsed-oldKeysUsed, session.UserID); err != nil {
+			s.logger.Error("Failed to record key consumption",
+				zap.Error(err),
+				zap.String("party_id", partyID),
+				zap.String("holder", currentTask.KeyHolder))
+			// Don't fail the request - the step progress is already saved
+		}
+	}
+
 	s.NotifyDataChange("party:" + partyID)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -857,3 +901,15 @@ func (s *Server) getAllTasksFromPlan(plan PlanData) []PlanPartyTask {
 	return tasks
 }
 
+// getPartyForStep returns the plan party whose tasks include the step at stepIndex of
+// getAllTasksFromPlan, or nil if the index is out of range
+func (s *Server) getPartyForStep(plan PlanData, stepIndex int) *PlanParty {
+	for i := range plan.Parties {
+		if stepIndex < len(plan.Parties[i].Tasks) {
+			return &plan.Parties[i]
+		}
+		stepIndex -= len(plan.Parties[i].Tasks)
+	}
+	return nil
+}
+
@@ -0,0 +1,214 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/render"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+)
+
+// handlePlayerCardImage renders a player's profile card as a PNG.
+// It is public so Discord can fetch it when unfurling player page links.
+func (s *Server) handlePlayerCardImage(w http.ResponseWriter, r *http.Request) {
+	playerName := r.PathValue("playerName")
+	if playerName == "" {
+		http.Error(w, "Player name required", http.StatusBadRequest)
+		return
+	}
+
+	var img image.Image
+	card, err := s.getPlayerCard(r.Context(), playerName)
+	switch {
+	case errors.Is(err, errPlayerNotTracked):
+		img = render.RenderPlaceholderCard(playerName)
+	case err != nil:
+		s.logger.Error("Failed to get player profile for card", zap.Error(err), zap.String("player", playerName))
+		http.Error(w, "Failed to get player profile", http.StatusBadGateway)
+		return
+	default:
+		img = render.RenderPlayerCard(card)
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=300") // Cache for 5 minutes
+
+	if err := png.Encode(w, img); err != nil {
+		s.logger.Error("Failed to encode PNG", zap.Error(err))
+		http.Error(w, "Failed to generate image", http.StatusInternalServerError)
+	}
+}
+
+const (
+	// playerCardTTL is how long a card fetched from the API is reused
+	playerCardTTL = 10 * time.Minute
+	// playerCardErrorTTL is how long a failed fetch is remembered, so unknown names aren't retried on every request
+	playerCardErrorTTL = 1 * time.Minute
+	// maxPlayerCardCacheEntries bounds the card cache; the entry closest to expiring is evicted first
+	maxPlayerCardCacheEntries = 500
+)
+
+// errPlayerNotTracked is returned for players who are neither snapshotted nor registered,
+// whose cards are never fetched from the API
+var errPlayerNotTracked = errors.New("player is not tracked")
+
+// playerCardCache holds cards fetched from the API for players without a snapshot.
+// The card endpoints are public, so this keeps unfurls and scrapers from using up the API rate limit.
+type playerCardCache struct {
+	mu      sync.Mutex
+	entries map[string]playerCardCacheEntry
+}
+
+type playerCardCacheEntry struct {
+	card      *render.PlayerCard
+	err       error
+	expiresAt time.Time
+}
+
+func (c *playerCardCache) get(playerName string) (playerCardCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[strings.ToLower(playerName)]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return playerCardCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *playerCardCache) put(playerName string, card *render.PlayerCard, err error) {
+	ttl := playerCardTTL
+	if err != nil {
+		ttl = playerCardErrorTTL
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]playerCardCacheEntry)
+	}
+	now := time.Now()
+	for name, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, name)
+		}
+	}
+	for len(c.entries) >= maxPlayerCardCacheEntries {
+		c.evictNext()
+	}
+	c.entries[strings.ToLower(playerName)] = playerCardCacheEntry{card: card, err: err, expiresAt: now.Add(ttl)}
+}
+
+// evictNext removes the entry that expires soonest. The caller must hold c.mu.
+func (c *playerCardCache) evictNext() {
+	var next string
+	var nextExpiry time.Time
+	for name, entry := range c.entries {
+		if next == "" || entry.expiresAt.Before(nextExpiry) {
+			next, nextExpiry = name, entry.expiresAt
+		}
+	}
+	delete(c.entries, next)
+}
+
+// getPlayerCard builds the card data for a player from their latest snapshot. Registered players
+// without one are fetched from the API, through the card cache; anyone else gets errPlayerNotTracked.
+func (s *Server) getPlayerCard(ctx context.Context, playerName string) (*render.PlayerCard, error) {
+	snapshot, err := s.trackerDB.GetLatestSnapshot(ctx, playerName)
+	if err != nil {
+		s.logger.Warn("Failed to get latest snapshot for card", zap.Error(err), zap.String("player", playerName))
+	}
+	if snapshot != nil {
+		return &render.PlayerCard{
+			Name:     snapshot.PlayerName,
+			Skills:   snapshot.Skills,
+			PvmStats: snapshot.PvmStats,
+		}, nil
+	}
+
+	if entry, ok := s.playerCards.get(playerName); ok {
+		return entry.card, entry.err
+	}
+
+	// The card endpoint is public, so only players the clan knows about are looked up
+	registered, err := s.db.GetAllRegisteredPlayerNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	isRegistered := false
+	for _, name := range registered {
+		if strings.EqualFold(name, playerName) {
+			isRegistered = true
+			break
+		}
+	}
+	if !isRegistered {
+		return nil, errPlayerNotTracked
+	}
+
+	card, err := s.fetchPlayerCard(ctx, playerName)
+	s.playerCards.put(playerName, card, err)
+	return card, err
+}
+
+// fetchPlayerCard builds the card data for a player from their profile in the API
+func (s *Server) fetchPlayerCard(ctx context.Context, playerName string) (*render.PlayerCard, error) {
+	player, err := s.icClient.GetPlayer(ctx, playerName)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := tracker.SnapshotFromPlayer(playerName, player)
+	if err != nil {
+		return nil, err
+	}
+	return &render.PlayerCard{
+		Name:     playerName,
+		Skills:   snapshot.Skills,
+		PvmStats: snapshot.PvmStats,
+	}, nil
+}
+
+// generatePlayerOGTags generates Open Graph meta tags for a player page
+func (s *Server) generatePlayerOGTags(ctx context.Context, playerName string) string {
+	description := "View skills, gear and PvM stats"
+	if card, err := s.getPlayerCard(ctx, playerName); err == nil {
+		description = fmt.Sprintf("Total level %d | %s", card.TotalLevel(), description)
+	}
+
+	escapedName := html.EscapeString(playerName)
+	escapedDesc := html.EscapeString(description)
+	baseURL := s.config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://idleclans.jirwin.dev"
+	}
+
+	ogImageURL := fmt.Sprintf("%s/api/players/%s/card.png", baseURL, url.PathEscape(playerName))
+	pageURL := fmt.Sprintf("%s/player?name=%s", baseURL, url.QueryEscape(playerName))
+
+	return fmt.Sprintf(`
+    <!-- Open Graph / Discord Unfurl -->
+    <meta property="og:type" content="profile" />
+    <meta property="og:title" content="%s - IdleClans" />
+    <meta property="og:description" content="%s" />
+    <meta property="og:image" content="%s" />
+    <meta property="og:image:width" content="%d" />
+    <meta property="og:image:height" content="%d" />
+    <meta property="og:url" content="%s" />
+    <meta property="og:site_name" content="IdleClans" />
+    <meta name="twitter:card" content="summary_large_image" />
+    <meta name="twitter:title" content="%s - IdleClans" />
+    <meta name="twitter:description" content="%s" />
+    <meta name="twitter:image" content="%s" />
+    <meta name="theme-color" content="#8b5cf6" />
+    `, escapedName, escapedDesc, html.EscapeString(ogImageURL), render.CardWidth, render.CardHeight,
+		html.EscapeString(pageURL), escapedName, escapedDesc, html.EscapeString(ogImageURL))
+}
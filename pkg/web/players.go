@@ -0,0 +1,159 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+)
+
+// GearResponse represents a player's equipped items by slot
+type GearResponse struct {
+	PlayerName string               `json:"player_name"`
+	Gear       []idleclans.GearSlot `json:"gear"`
+}
+
+// handleGetPlayerGear returns a player's equipment resolved to item names
+func (s *Server) handleGetPlayerGear(w http.ResponseWriter, r *http.Request) {
+	playerName := r.PathValue("playerName")
+	if playerName == "" {
+		http.Error(w, "Player name required", http.StatusBadRequest)
+		return
+	}
+
+	player, err := s.icClient.GetPlayer(r.Context(), playerName)
+	if err != nil {
+		s.logger.Error("Failed to get player profile", zap.Error(err), zap.String("player", playerName))
+		http.Error(w, "Failed to get player profile", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GearResponse{
+		PlayerName: playerName,
+		Gear:       s.icClient.ResolveGear(player),
+	})
+}
+
+// ClanGearValueResponse represents the clan's combined gear value over time
+type ClanGearValueResponse struct {
+	Days    int                     `json:"days"`
+	History []tracker.ClanGearValue `json:"history"`
+}
+
+// handleGetPlayerNetWorth values a player's equipped gear at the latest market prices.
+// The live profile of a clan player is recorded as an on-demand snapshot so it also feeds the
+// clan gear history.
+func (s *Server) handleGetPlayerNetWorth(w http.ResponseWriter, r *http.Request) {
+	if s.marketDB == nil {
+		http.Error(w, "Market data not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	playerName := r.PathValue("playerName")
+	if playerName == "" {
+		http.Error(w, "Player name required", http.StatusBadRequest)
+		return
+	}
+
+	player, err := s.icClient.GetPlayer(r.Context(), playerName)
+	if err != nil {
+		s.logger.Error("Failed to get player profile", zap.Error(err), zap.String("player", playerName))
+		http.Error(w, "Failed to get player profile", http.StatusBadGateway)
+		return
+	}
+
+	// Only clan players are recorded, so lookups of others stay out of the clan history
+	registered, err := s.db.GetAllRegisteredPlayerNames(r.Context())
+	if err != nil {
+		s.logger.Warn("Failed to get registered player names", zap.Error(err))
+	}
+	if name, ok := tracker.OnDemandSnapshotName(player, playerName, registered, s.config.RequiredGuild); ok {
+		snapshot, err := tracker.SnapshotFromPlayer(name, player)
+		if err != nil {
+			s.logger.Error("Failed to build player snapshot", zap.Error(err), zap.String("player", name))
+		} else if err := s.trackerDB.InsertSnapshot(r.Context(), snapshot); err != nil {
+			s.logger.Warn("Failed to save player snapshot", zap.Error(err), zap.String("player", name))
+		}
+	}
+
+	worth, err := tracker.ValueGear(r.Context(), s.marketDB, playerName, s.icClient.ResolveGear(player))
+	if err != nil {
+		s.logger.Error("Failed to value gear", zap.Error(err), zap.String("player", playerName))
+		http.Error(w, "Failed to value gear", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(worth)
+}
+
+// handleGetClanGearValue returns the clan's combined gear value per day.
+// Accepts an optional ?days= (default 30, max 365).
+func (s *Server) handleGetClanGearValue(w http.ResponseWriter, r *http.Request) {
+	if s.marketDB == nil {
+		http.Error(w, "Market data not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	days := 30
+	if d := r.URL.Query().Get("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed <= 0 || parsed > 365 {
+			http.Error(w, "days must be between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	ctx := r.Context()
+	names, err := s.db.GetAllRegisteredPlayerNames(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get registered players", zap.Error(err))
+		http.Error(w, "Failed to get registered players", http.StatusInternalServerError)
+		return
+	}
+
+	history, err := s.trackerDB.GetClanGearValueHistory(ctx, s.marketDB, names, days)
+	if err != nil {
+		s.logger.Error("Failed to get clan gear value history", zap.Error(err))
+		http.Error(w, "Failed to get clan gear value history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ClanGearValueResponse{
+		Days:    days,
+		History: history,
+	})
+}
+
+// handleComparePlayers returns a side-by-side comparison of skills, PvM kills and keys.
+// Players are passed as ?players=a,b,c.
+func (s *Server) handleComparePlayers(w http.ResponseWriter, r *http.Request) {
+	var playerNames []string
+	for _, name := range strings.Split(r.URL.Query().Get("players"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			playerNames = append(playerNames, name)
+		}
+	}
+	if len(playerNames) < 2 || len(playerNames) > tracker.MaxComparePlayers {
+		http.Error(w, fmt.Sprintf("Between 2 and %d players required", tracker.MaxComparePlayers), http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := tracker.ComparePlayers(r.Context(), s.icClient, s.db, playerNames)
+	if err != nil {
+		s.logger.Error("Failed to compare players", zap.Error(err), zap.Strings("players", playerNames))
+		http.Error(w, "Failed to compare players", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comparison)
+}
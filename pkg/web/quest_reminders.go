@@ -0,0 +1,73 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+)
+
+// questReminderHelpersShown is how many key holders are listed per incomplete quest
+const questReminderHelpersShown = 3
+
+// QuestReminderDiscordNotifier implements tracker.QuestReminderNotifier to ping players
+// with incomplete quests before the weekly reset
+type QuestReminderDiscordNotifier struct {
+	sender    DiscordMessageSender
+	channelID string
+	baseURL   string
+	logger    *zap.Logger
+}
+
+// NotifyQuestReminders posts one message per Discord user, pinging them with their missing bosses
+func (n *QuestReminderDiscordNotifier) NotifyQuestReminders(ctx context.Context, resetAt time.Time, reminders []tracker.QuestReminder) error {
+	if n.sender == nil || n.channelID == "" {
+		return nil // No Discord configured
+	}
+
+	hoursLeft := int(time.Until(resetAt).Round(time.Hour).Hours())
+	title := fmt.Sprintf("⏰ Quests reset in %d hours", hoursLeft)
+	if hoursLeft <= 1 {
+		title = "⏰ Quests reset within the hour"
+	}
+
+	var failed int
+	for _, reminder := range reminders {
+		var sb strings.Builder
+		for _, quest := range reminder.Quests {
+			sb.WriteString(fmt.Sprintf("**%s** — %s: %d kills left\n", quest.PlayerName, capitalizeFirst(quest.BossName), quest.Remaining))
+			if len(quest.Helpers) == 0 {
+				continue
+			}
+			helpers := make([]string, 0, questReminderHelpersShown)
+			for i, helper := range quest.Helpers {
+				if i >= questReminderHelpersShown {
+					helpers = append(helpers, fmt.Sprintf("+%d more", len(quest.Helpers)-questReminderHelpersShown))
+					break
+				}
+				helpers = append(helpers, fmt.Sprintf("%s (%d keys)", helper.PlayerName, helper.Keys))
+			}
+			sb.WriteString(fmt.Sprintf("  ↳ Can help: %s\n", strings.Join(helpers, ", ")))
+		}
+		sb.WriteString(fmt.Sprintf("\n[Plan a party](%s/clan)", n.baseURL))
+
+		embed := &DiscordEmbed{
+			Title:       title,
+			Description: sb.String(),
+			Color:       0xe67e22, // Orange color
+		}
+		content := fmt.Sprintf("<@%s> you still have incomplete quests this week", reminder.DiscordUserID)
+		if err := n.sender.SendMessageWithEmbed(n.channelID, content, embed); err != nil {
+			n.logger.Warn("Failed to send quest reminder", zap.String("user_id", reminder.DiscordUserID), zap.Error(err))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to send %d of %d quest reminders", failed, len(reminders))
+	}
+	return nil
+}
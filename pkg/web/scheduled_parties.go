@@ -0,0 +1,332 @@
+package web
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+// ScheduledPartyRequest is the body for scheduling a party
+type ScheduledPartyRequest struct {
+	Title       string    `json:"title"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Players     []string  `json:"players"`
+}
+
+// RSVPRequest is the body for responding to a scheduled party
+type RSVPRequest struct {
+	Status string `json:"status"` // "accepted" or "declined"
+}
+
+// ScheduledPartyResponse is a scheduled party with the current user's invited players
+type ScheduledPartyResponse struct {
+	quests.ScheduledParty
+	MyPlayers []string `json:"my_players"` // The user's players (main or alts) invited to the party
+	CanCancel bool     `json:"can_cancel"`
+}
+
+// handleGetScheduledParties returns every upcoming scheduled party
+func (s *Server) handleGetScheduledParties(w http.ResponseWriter, r *http.Request) {
+	session := getSession(r)
+	if session == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	parties, err := s.db.GetUpcomingScheduledParties(ctx, time.Time{})
+	if err != nil {
+		s.logger.Error("Failed to get scheduled parties", zap.Error(err))
+		http.Error(w, "Failed to get scheduled parties", http.StatusInternalServerError)
+		return
+	}
+
+	names, _ := s.db.GetAllPlayerNames(ctx, session.UserID)
+	response := make([]ScheduledPartyResponse, 0, len(parties))
+	for _, sp := range parties {
+		response = append(response, newScheduledPartyResponse(sp, session.UserID, names))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleCreateScheduledParty schedules a party and posts the invitation to Discord
+func (s *Server) handleCreateScheduledParty(w http.ResponseWriter, r *http.Request) {
+	session := getSession(r)
+	if session == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req ScheduledPartyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Players) == 0 {
+		http.Error(w, "No players specified", http.StatusBadRequest)
+		return
+	}
+	if !req.ScheduledAt.After(time.Now()) {
+		http.Error(w, "Scheduled time must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	sp := &quests.ScheduledParty{
+		Title:       strings.TrimSpace(req.Title),
+		ScheduledAt: req.ScheduledAt,
+		CreatedBy:   session.UserID,
+	}
+	if err := s.db.CreateScheduledParty(ctx, sp, req.Players); err != nil {
+		s.logger.Error("Failed to schedule party", zap.Error(err))
+		http.Error(w, "Failed to schedule party", http.StatusInternalServerError)
+		return
+	}
+
+	// Send the invitation if configured (don't fail scheduling if Discord fails)
+	if s.discordSender != nil && s.config.DiscordChannelID != "" {
+		content := fmt.Sprintf("%s you're invited to a party <t:%d:R>!", s.playerMentions(ctx, req.Players), sp.ScheduledAt.Unix())
+		embed := scheduledPartyEmbed(sp, "📅 Party Scheduled")
+		if err := s.discordSender.SendMessageWithButtons(s.config.DiscordChannelID, content, embed, rsvpButtons(sp.ID)); err != nil {
+			s.logger.Warn("Failed to send scheduled party invitation to Discord",
+				zap.Error(err),
+				zap.String("id", sp.ID))
+		}
+	}
+
+	s.NotifyDataChange("scheduled_party")
+
+	names, _ := s.db.GetAllPlayerNames(ctx, session.UserID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newScheduledPartyResponse(*sp, session.UserID, names))
+}
+
+// handleScheduledPartyRSVP records the user's response for all of their invited players
+func (s *Server) handleScheduledPartyRSVP(w http.ResponseWriter, r *http.Request) {
+	session := getSession(r)
+	if session == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req RSVPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Status != quests.RSVPAccepted && req.Status != quests.RSVPDeclined {
+		http.Error(w, "Status must be accepted or declined", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := s.db.SetScheduledPartyRSVP(r.Context(), r.PathValue("id"), session.UserID, req.Status)
+	if err != nil {
+		s.logger.Error("Failed to record RSVP", zap.Error(err))
+		http.Error(w, "Failed to record RSVP", http.StatusInternalServerError)
+		return
+	}
+	if len(updated) == 0 {
+		http.Error(w, "You're not invited to this party, or it has already started", http.StatusForbidden)
+		return
+	}
+
+	s.NotifyDataChange("scheduled_party")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"players": updated, "status": req.Status})
+}
+
+// handleCancelScheduledParty cancels a scheduled party. Only the person who scheduled it can cancel it.
+func (s *Server) handleCancelScheduledParty(w http.ResponseWriter, r *http.Request) {
+	session := getSession(r)
+	if session == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	sp, err := s.db.GetScheduledParty(ctx, r.PathValue("id"))
+	if err != nil {
+		s.logger.Error("Failed to get scheduled party", zap.Error(err))
+		http.Error(w, "Failed to cancel party", http.StatusInternalServerError)
+		return
+	}
+	if sp == nil {
+		http.Error(w, "Scheduled party not found", http.StatusNotFound)
+		return
+	}
+	if sp.CreatedBy != session.UserID {
+		http.Error(w, "Only the person who scheduled this party can cancel it", http.StatusForbidden)
+		return
+	}
+
+	err = s.db.CancelScheduledParty(ctx, sp.ID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Party has already started or been cancelled", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to cancel scheduled party", zap.Error(err))
+		http.Error(w, "Failed to cancel party", http.StatusInternalServerError)
+		return
+	}
+
+	s.NotifyDataChange("scheduled_party")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newScheduledPartyResponse adds which of the user's players are invited to a scheduled party
+func newScheduledPartyResponse(sp quests.ScheduledParty, userID string, names []string) ScheduledPartyResponse {
+	mine := make([]string, 0)
+	for _, rsvp := range sp.RSVPs {
+		for _, name := range names {
+			if strings.EqualFold(rsvp.PlayerName, name) {
+				mine = append(mine, rsvp.PlayerName)
+				break
+			}
+		}
+	}
+	return ScheduledPartyResponse{
+		ScheduledParty: sp,
+		MyPlayers:      mine,
+		CanCancel:      sp.CreatedBy == userID,
+	}
+}
+
+// playerMentions returns a Discord mention for each distinct user owning one of the players
+func (s *Server) playerMentions(ctx context.Context, players []string) string {
+	seen := make(map[string]bool)
+	var mentions []string
+	for _, player := range players {
+		discordID, err := s.db.GetDiscordUserIDForPlayer(ctx, player)
+		if err != nil || discordID == "" || seen[discordID] {
+			continue
+		}
+		seen[discordID] = true
+		mentions = append(mentions, fmt.Sprintf("<@%s>", discordID))
+	}
+	return strings.Join(mentions, " ")
+}
+
+// ScheduledPartyDiscordNotifier implements tracker.ScheduledPartyNotifier to remind invited
+// players on Discord and create the party session when a scheduled party starts
+type ScheduledPartyDiscordNotifier struct {
+	server *Server
+}
+
+// NotifyScheduledPartyReminder pings everyone who hasn't declined, with buttons to respond
+func (n *ScheduledPartyDiscordNotifier) NotifyScheduledPartyReminder(ctx context.Context, sp *quests.ScheduledParty) error {
+	s := n.server
+	channelID := s.scheduledPartyChannel(sp)
+	if s.discordSender == nil || channelID == "" {
+		return nil // No Discord configured
+	}
+
+	players := append(sp.PlayersWithStatus(quests.RSVPAccepted), sp.PlayersWithStatus(quests.RSVPPending)...)
+	if len(players) == 0 {
+		return nil
+	}
+
+	content := fmt.Sprintf("%s the party starts <t:%d:R>!", s.playerMentions(ctx, players), sp.ScheduledAt.Unix())
+	return s.discordSender.SendMessageWithButtons(channelID, content, scheduledPartyEmbed(sp, "⏰ Party Starting Soon"), rsvpButtons(sp.ID))
+}
+
+// StartScheduledParty creates a party session with the players who accepted and posts its link
+func (n *ScheduledPartyDiscordNotifier) StartScheduledParty(ctx context.Context, sp *quests.ScheduledParty) error {
+	s := n.server
+	channelID := s.scheduledPartyChannel(sp)
+
+	accepted := sp.PlayersWithStatus(quests.RSVPAccepted)
+	if len(accepted) == 0 {
+		s.logger.Info("Scheduled party started without any accepted players", zap.String("id", sp.ID))
+		if s.discordSender != nil && channelID != "" {
+			return s.discordSender.SendMessage(channelID, fmt.Sprintf("📅 **%s** didn't start because nobody accepted the invitation", scheduledPartyTitle(sp)))
+		}
+		return nil
+	}
+
+	partyID, err := s.CreatePartySession(ctx, accepted, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.db.SetScheduledPartySession(ctx, sp.ID, partyID); err != nil {
+		return err
+	}
+	s.NotifyDataChange("scheduled_party")
+
+	s.logger.Info("Scheduled party started",
+		zap.String("id", sp.ID),
+		zap.String("party_id", partyID),
+		zap.Strings("players", accepted))
+
+	if s.discordSender == nil || channelID == "" {
+		return nil
+	}
+	embed := &DiscordEmbed{
+		Title:       "Party Started!",
+		Description: fmt.Sprintf("**%s** has started with: %s", scheduledPartyTitle(sp), strings.Join(accepted, ", ")),
+		Color:       0x5865F2, // Discord blurple
+		Fields: []DiscordEmbedField{
+			{
+				Name:   "Party Link",
+				Value:  fmt.Sprintf("[Join Party](%s)", s.PartyURL(partyID)),
+				Inline: false,
+			},
+		},
+	}
+	return s.discordSender.SendMessageWithEmbed(channelID, s.playerMentions(ctx, accepted), embed)
+}
+
+// scheduledPartyChannel returns where a scheduled party's messages go
+func (s *Server) scheduledPartyChannel(sp *quests.ScheduledParty) string {
+	if sp.ChannelID != "" {
+		return sp.ChannelID
+	}
+	return s.config.DiscordChannelID
+}
+
+func scheduledPartyTitle(sp *quests.ScheduledParty) string {
+	if sp.Title != "" {
+		return sp.Title
+	}
+	return "Scheduled Party"
+}
+
+// scheduledPartyEmbed lists a scheduled party's start time and RSVPs
+func scheduledPartyEmbed(sp *quests.ScheduledParty, title string) *DiscordEmbed {
+	rsvpList := func(status string) string {
+		players := sp.PlayersWithStatus(status)
+		if len(players) == 0 {
+			return "—"
+		}
+		return strings.Join(players, "\n")
+	}
+
+	return &DiscordEmbed{
+		Title:       title,
+		Description: fmt.Sprintf("**%s** starts <t:%d:F> (<t:%d:R>)\nOnly players who accept are included in the party plan.", scheduledPartyTitle(sp), sp.ScheduledAt.Unix(), sp.ScheduledAt.Unix()),
+		Color:       0x3498db, // Blue color
+		Fields: []DiscordEmbedField{
+			{Name: "✅ Accepted", Value: rsvpList(quests.RSVPAccepted), Inline: true},
+			{Name: "❔ Pending", Value: rsvpList(quests.RSVPPending), Inline: true},
+			{Name: "❌ Declined", Value: rsvpList(quests.RSVPDeclined), Inline: true},
+		},
+	}
+}
+
+// rsvpButtons returns the accept and decline buttons handled by the bot
+func rsvpButtons(scheduledPartyID string) []DiscordButton {
+	return []DiscordButton{
+		{Label: "Accept", CustomID: quests.RSVPButtonID(scheduledPartyID, quests.RSVPAccepted)},
+		{Label: "Decline", CustomID: quests.RSVPButtonID(scheduledPartyID, quests.RSVPDeclined), Danger: true},
+	}
+}
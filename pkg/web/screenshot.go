@@ -0,0 +1,137 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jirwin/idleclans/pkg/openai"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrAnalysisNotConfigured is returned when no OpenAI API key is configured
+	ErrAnalysisNotConfigured = errors.New("image analysis not configured")
+	// errAnalysisParse is returned when the model's response isn't the expected JSON
+	errAnalysisParse = errors.New("failed to parse analysis result")
+)
+
+// maxKeyCount filters out implausible key counts (> 300 is likely misdetection of non-key items)
+const maxKeyCount = 300
+
+// AnalyzeQuestScreenshot extracts boss kill requirements from a quest tracker screenshot.
+// This is the same analysis as the web upload, for use by the Discord bot.
+func (s *Server) AnalyzeQuestScreenshot(imageData []byte, imageType string) ([]BossKillResult, error) {
+	if s.openaiClient == nil {
+		return nil, ErrAnalysisNotConfigured
+	}
+	return s.analyzeQuestImage(base64.StdEncoding.EncodeToString(imageData), imageType)
+}
+
+// AnalyzeKeyScreenshot extracts key counts from an inventory screenshot.
+// This is the same analysis as the web upload, for use by the Discord bot.
+func (s *Server) AnalyzeKeyScreenshot(imageData []byte, imageType string) ([]KeyCountResult, error) {
+	if s.openaiClient == nil {
+		return nil, ErrAnalysisNotConfigured
+	}
+	return s.analyzeKeyImage(base64.StdEncoding.EncodeToString(imageData), imageType)
+}
+
+// analyzeQuestImage asks the vision model for the bosses in a quest screenshot and
+// keeps only valid bosses with kills remaining
+func (s *Server) analyzeQuestImage(imageBase64, imageType string) ([]BossKillResult, error) {
+	resp, err := s.openaiClient.ChatCompletionWithImageJSON(
+		questAnalysisPrompt,
+		"Please analyze this quest tracker screenshot and extract the boss kill requirements.",
+		imageBase64,
+		imageType,
+		true, // Force JSON output
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse LLM response
+	content := extractJSON(resp.Choices[0].Message.Content)
+	var llmResp llmQuestResponse
+	if err := json.Unmarshal([]byte(content), &llmResp); err != nil {
+		s.logger.Error("Failed to parse LLM response",
+			zap.Error(err),
+			zap.String("content", resp.Choices[0].Message.Content))
+		return nil, errAnalysisParse
+	}
+
+	// Validate and convert results
+	results := make([]BossKillResult, 0)
+	for _, b := range llmResp.Bosses {
+		bossName := strings.ToLower(strings.TrimSpace(b.Name))
+		if quests.IsValidBoss(bossName) && b.Kills > 0 {
+			results = append(results, BossKillResult{
+				Name:  bossName,
+				Kills: b.Kills,
+			})
+		}
+	}
+	return results, nil
+}
+
+// analyzeKeyImage asks the vision model for the keys in an inventory screenshot, matching them
+// against the embedded reference images when available
+func (s *Server) analyzeKeyImage(imageBase64, imageType string) ([]KeyCountResult, error) {
+	var resp *openai.ChatResponse
+	var err error
+	if s.keyReferenceImages != nil && s.keyReferenceImages.HasImages() {
+		resp, err = s.openaiClient.ChatCompletionWithReferences(
+			keyAnalysisPromptWithRefs,
+			"Analyze this key inventory screenshot. Match keys to the reference images provided.",
+			s.keyReferenceImages.GetImages(),
+			imageBase64,
+			imageType,
+			true, // Force JSON output
+		)
+	} else {
+		resp, err = s.openaiClient.ChatCompletionWithImageJSON(
+			keyAnalysisPrompt,
+			"Please analyze this key inventory screenshot and extract the key counts.",
+			imageBase64,
+			imageType,
+			true, // Force JSON output
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	content := extractJSON(resp.Choices[0].Message.Content)
+	var llmResp llmKeyResponse
+	if err := json.Unmarshal([]byte(content), &llmResp); err != nil {
+		s.logger.Error("Failed to parse LLM response",
+			zap.Error(err),
+			zap.String("content", resp.Choices[0].Message.Content))
+		return nil, errAnalysisParse
+	}
+
+	// Validate and convert results
+	results := make([]KeyCountResult, 0)
+	for _, k := range llmResp.Keys {
+		keyType, ok := quests.ResolveKeyType(k.Type)
+		if ok && k.Count > 0 && k.Count <= maxKeyCount {
+			results = append(results, KeyCountResult{
+				Type:  keyType,
+				Count: k.Count,
+			})
+		}
+	}
+	return results, nil
+}
+
+// analysisErrorMessage formats an analysis error for API responses
+func analysisErrorMessage(err error) string {
+	if errors.Is(err, errAnalysisParse) {
+		return "Failed to parse analysis result"
+	}
+	return fmt.Sprintf("Failed to analyze image: %s", err.Error())
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/jirwin/idleclans/pkg/market"
 	"github.com/jirwin/idleclans/pkg/openai"
 	"github.com/jirwin/idleclans/pkg/quests"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"github.com/jirwin/idleclans/pkg/upgrades"
 	"go.uber.org/zap"
 )
 
@@ -21,11 +23,18 @@ type Config struct {
 	DiscordClientID     string
 	DiscordClientSecret string
 	SessionSecret       string
-	RequiredGuild       string // Guild name required for registration
-	DiscordChannelID    string // Channel to send messages to
-	OpenAIAPIKey        string // OpenAI API key for image analysis
-	OpenAIModel         string // Vision model for image analysis (e.g., gpt-4o)
-	EnableMarket        bool   // Enable market price tracking
+	RequiredGuild       string               // Guild name required for registration
+	DiscordChannelID    string               // Channel to send messages to
+	OpenAIAPIKey        string               // OpenAI API key for image analysis
+	OpenAIModel         string               // Vision model for image analysis (e.g., gpt-4o)
+	EnableMarket        bool                 // Enable market price tracking
+	SnapshotInterval    time.Duration        // How often player profiles are snapshotted (0 = default)
+	SnapshotRetention   time.Duration        // How long every snapshot is kept before thinning to one per day (0 = keep all)
+	QuestReminderHours  int                  // Hours before the weekly quest reset to ping incomplete quests (0 = disabled)
+	PartyReminderLead   time.Duration        // How long before a scheduled party to remind its players (0 = no reminder)
+	PlannerFairness     float64              // Weight for spreading key usage across holders in plans (0 = fewest parties)
+	PlannerObjective    quests.PlanObjective // What the plan optimizer minimizes
+	PlannerSearchBudget time.Duration        // How long the plan optimizer searches (0 = greedy plans only)
 }
 
 // DiscordEmbed represents a Discord embed for the web server
@@ -43,10 +52,19 @@ type DiscordEmbedField struct {
 	Inline bool
 }
 
+// DiscordButton represents a button below a Discord message, handled by the bot
+type DiscordButton struct {
+	Label    string
+	CustomID string
+	Danger   bool // Red instead of green
+}
+
 // DiscordMessageSender interface for sending messages to Discord
 type DiscordMessageSender interface {
 	SendMessage(channelID, message string) error
 	SendMessageWithEmbed(channelID, content string, embed *DiscordEmbed) error
+	SendMessageWithButtons(channelID, content string, embed *DiscordEmbed, buttons []DiscordButton) error
+	SendDirectMessageWithEmbed(userID, content string, embed *DiscordEmbed) error
 }
 
 // Server represents the web server
@@ -67,14 +85,22 @@ type Server struct {
 	marketDB        *market.DB
 	marketCollector *market.Collector
 	marketAPI       *MarketAPI
+	// Player tracking components
+	trackerDB *tracker.DB
+	tracker   *tracker.Tracker
+	// Upgrade metadata
+	upgradesDB *upgrades.DB
+	// Player cards fetched from the API for the public card endpoints
+	playerCards playerCardCache
 }
 
 // SetDiscordSender sets the Discord message sender
 func (s *Server) SetDiscordSender(sender DiscordMessageSender) {
 	s.discordSender = sender
 
-	// Set up market watch notifier now that Discord is available
-	if s.marketCollector != nil && s.config.DiscordChannelID != "" {
+	// Set up market watch notifier now that Discord is available.
+	// Watches can be delivered by DM, so this doesn't need a shared channel.
+	if s.marketCollector != nil {
 		watchNotifier := &MarketWatchNotifier{
 			sender:    sender,
 			channelID: s.config.DiscordChannelID,
@@ -90,6 +116,32 @@ func (s *Server) SetDiscordSender(sender DiscordMessageSender) {
 		s.marketCollector.SetDataChangeNotifier(s.NotifyDataChange)
 		s.logger.Info("Market SSE notifications enabled")
 	}
+
+	// Set up competition standings notifier
+	if s.tracker != nil {
+		s.tracker.SetCompetitionNotifier(&CompetitionDiscordNotifier{
+			sender:    sender,
+			channelID: s.config.DiscordChannelID,
+			baseURL:   s.config.BaseURL,
+			logger:    s.logger,
+		})
+	}
+
+	// Set up end-of-week quest reminders
+	if s.tracker != nil && s.config.DiscordChannelID != "" && s.config.QuestReminderHours > 0 {
+		s.tracker.SetQuestReminderNotifier(&QuestReminderDiscordNotifier{
+			sender:    sender,
+			channelID: s.config.DiscordChannelID,
+			baseURL:   s.config.BaseURL,
+			logger:    s.logger,
+		})
+		s.logger.Info("Quest reminders enabled", zap.Int("hours_before_reset", s.config.QuestReminderHours))
+	}
+
+	// Set up scheduled party reminders and starts
+	if s.tracker != nil {
+		s.tracker.SetScheduledPartyNotifier(&ScheduledPartyDiscordNotifier{server: s})
+	}
 }
 
 // MarketWatchNotifier implements market.WatchNotifier to send Discord notifications
@@ -101,8 +153,9 @@ type MarketWatchNotifier struct {
 }
 
 // NotifyWatchTriggered sends a Discord notification when a market watch triggers
+// Watches are sent to the user's DMs or to a channel, depending on how they were created.
 func (n *MarketWatchNotifier) NotifyWatchTriggered(ctx context.Context, watch *market.TriggeredWatch) error {
-	if n.sender == nil || n.channelID == "" {
+	if n.sender == nil {
 		return nil // No Discord configured
 	}
 
@@ -151,7 +204,24 @@ func (n *MarketWatchNotifier) NotifyWatchTriggered(ctx context.Context, watch *m
 		},
 	}
 
-	return n.sender.SendMessageWithEmbed(n.channelID, pingContent, embed)
+	if watch.Delivery == market.WatchDeliveryDM {
+		err := n.sender.SendDirectMessageWithEmbed(watch.UserID, "", embed)
+		if err == nil {
+			return nil
+		}
+		// The user may have DMs disabled; fall back to the channel
+		n.logger.Warn("Failed to DM watch notification, falling back to channel",
+			zap.Int("watch_id", watch.WatchID), zap.Error(err))
+	}
+
+	channelID := watch.ChannelID
+	if channelID == "" {
+		channelID = n.channelID
+	}
+	if channelID == "" {
+		return nil // No channel configured
+	}
+	return n.sender.SendMessageWithEmbed(channelID, pingContent, embed)
 }
 
 // formatPrice formats a price with commas
@@ -214,6 +284,25 @@ func NewServer(config *Config, db *quests.DB, logger *zap.Logger) (*Server, erro
 		s.keyReferenceImages = NewKeyReferenceImages(logger)
 	}
 
+	// Initialize player snapshot tracking using the same connection
+	var err error
+	s.trackerDB, err = tracker.NewDB(db.GetDB(), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracker database: %w", err)
+	}
+	s.tracker = tracker.NewTracker(s.trackerDB, db, s.icClient, logger, &tracker.TrackerConfig{
+		Interval:           config.SnapshotInterval,
+		Retention:          config.SnapshotRetention,
+		QuestReminderHours: config.QuestReminderHours,
+		PartyReminderLead:  config.PartyReminderLead,
+	})
+	s.tracker.SetDataChangeNotifier(s.NotifyDataChange)
+
+	s.upgradesDB, err = upgrades.NewDB(db.GetDB(), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize upgrades database: %w", err)
+	}
+
 	return s, nil
 }
 
@@ -266,6 +355,25 @@ func (s *Server) StopMarketCollector() {
 	}
 }
 
+// StartTracker starts the background player snapshot tracker
+func (s *Server) StartTracker(ctx context.Context) {
+	if s.tracker != nil {
+		s.tracker.Start(ctx)
+	}
+}
+
+// Tracker returns the player snapshot tracker
+func (s *Server) Tracker() *tracker.Tracker {
+	return s.tracker
+}
+
+// StopTracker stops the background player snapshot tracker
+func (s *Server) StopTracker() {
+	if s.tracker != nil {
+		s.tracker.Stop()
+	}
+}
+
 // Start starts both the public and admin HTTP servers
 func (s *Server) Start(ctx context.Context) error {
 	// Setup public server routes
@@ -280,6 +388,9 @@ func (s *Server) Start(ctx context.Context) error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Load the item catalog used to resolve equipment IDs
+	s.icClient.Run(ctx)
+
 	// Setup admin server routes
 	adminMux := http.NewServeMux()
 	s.setupAdminRoutes(adminMux)
@@ -352,12 +463,26 @@ func (s *Server) setupPublicRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/alts", s.withAuth(s.handleAddAlt))
 	mux.HandleFunc("DELETE /api/alts/{playerName}", s.withAuth(s.handleRemoveAlt))
 
+	// Player tracking routes (authenticated)
+	mux.HandleFunc("GET /api/players/compare", s.withAuth(s.handleComparePlayers))
+	mux.HandleFunc("GET /api/players/{playerName}/gains", s.withAuth(s.handleGetPlayerGains))
+	mux.HandleFunc("GET /api/players/{playerName}/gear", s.withAuth(s.handleGetPlayerGear))
+	mux.HandleFunc("GET /api/players/{playerName}/networth", s.withAuth(s.handleGetPlayerNetWorth))
+	mux.HandleFunc("GET /api/players/{playerName}/upgrades", s.withAuth(s.handleGetPlayerUpgrades))
+	mux.HandleFunc("GET /api/competitions", s.withAuth(s.handleGetCompetitions))
+	mux.HandleFunc("GET /api/competitions/{id}", s.withAuth(s.handleGetCompetition))
+
 	// Clan view routes (authenticated)
 	mux.HandleFunc("GET /api/clan/bosses", s.withAuth(s.handleGetClanBosses))
 	mux.HandleFunc("GET /api/clan/keys", s.withAuth(s.handleGetClanKeys))
 	mux.HandleFunc("GET /api/clan/players", s.withAuth(s.handleGetClanPlayers))
 	mux.HandleFunc("POST /api/clan/plan", s.withAuth(s.handleGetClanPlan))
 	mux.HandleFunc("POST /api/clan/plan/send", s.withAuth(s.handleSendPlanToDiscord))
+	mux.HandleFunc("GET /api/clan/quest-sync", s.withAuth(s.handleGetQuestSyncConflicts))
+	mux.HandleFunc("GET /api/clan/quest-history", s.withAuth(s.handleGetQuestHistory))
+	mux.HandleFunc("GET /api/clan/key-forecast", s.withAuth(s.handleGetKeyForecast))
+	mux.HandleFunc("GET /api/clan/key-history", s.withAuth(s.handleGetKeyHistory))
+	mux.HandleFunc("GET /api/clan/gear-value", s.withAuth(s.handleGetClanGearValue))
 
 	// Screenshot analysis routes (authenticated)
 	mux.HandleFunc("POST /api/analyze/quests", s.withAuth(s.handleAnalyzeQuests))
@@ -373,6 +498,15 @@ func (s *Server) setupPublicRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/parties/{partyId}/next-step", s.withAuth(s.handleNextPartyStep))
 	mux.HandleFunc("POST /api/parties/{partyId}/end", s.withAuth(s.handleEndParty))
 
+	// Scheduled party routes
+	mux.HandleFunc("GET /api/scheduled-parties", s.withAuth(s.handleGetScheduledParties))
+	mux.HandleFunc("POST /api/scheduled-parties", s.withAuth(s.handleCreateScheduledParty))
+	mux.HandleFunc("POST /api/scheduled-parties/{id}/rsvp", s.withAuth(s.handleScheduledPartyRSVP))
+	mux.HandleFunc("DELETE /api/scheduled-parties/{id}", s.withAuth(s.handleCancelScheduledParty))
+
+	// Player card image (public, fetched by Discord when unfurling player pages)
+	mux.HandleFunc("GET /api/players/{playerName}/card.png", s.handlePlayerCardImage)
+
 	// SSE endpoint for live updates
 	mux.HandleFunc("GET /api/events", s.handleSSE)
 
@@ -397,6 +531,15 @@ func (s *Server) setupAdminRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/players/{discordId}/unregister", s.handleAdminUnregisterPlayer)
 	mux.HandleFunc("DELETE /api/players/{discordId}", s.handleAdminDeletePlayer)
 
+	// Upgrade metadata routes (no auth required - internal network only)
+	mux.HandleFunc("GET /api/upgrades", s.handleAdminGetUpgrades)
+	mux.HandleFunc("PUT /api/upgrades/{key}", s.handleAdminUpdateUpgrade)
+
+	// Boss catalog routes (no auth required - internal network only)
+	mux.HandleFunc("GET /api/bosses", s.handleAdminGetBosses)
+	mux.HandleFunc("PUT /api/bosses/{name}", s.handleAdminUpdateBoss)
+	mux.HandleFunc("DELETE /api/bosses/{name}", s.handleAdminDeleteBoss)
+
 	// Admin screenshot analysis routes (no auth required - internal network only)
 	mux.HandleFunc("POST /api/admin/analyze/quests", s.handleAdminAnalyzeQuests)
 	mux.HandleFunc("POST /api/admin/analyze/keys", s.handleAdminAnalyzeKeys)
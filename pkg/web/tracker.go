@@ -0,0 +1,177 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jirwin/idleclans/pkg/quests"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+)
+
+// GainsResponse represents a player's gains for each requested period
+type GainsResponse struct {
+	PlayerName string                    `json:"player_name"`
+	Periods    map[string]*tracker.Gains `json:"periods"` // period -> gains (null if not enough history)
+}
+
+// QuestSyncResponse lists quests whose recorded kills disagree with the API for a week
+type QuestSyncResponse struct {
+	Week      int                        `json:"week"`
+	Year      int                        `json:"year"`
+	Conflicts []quests.QuestSyncConflict `json:"conflicts"`
+}
+
+// handleGetPlayerGains returns XP, level and PvM kill deltas for a player.
+// Accepts an optional ?period=day|week|month; all periods are returned if omitted.
+func (s *Server) handleGetPlayerGains(w http.ResponseWriter, r *http.Request) {
+	if s.trackerDB == nil {
+		http.Error(w, "Player tracking not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	playerName := r.PathValue("playerName")
+	if playerName == "" {
+		http.Error(w, "Player name required", http.StatusBadRequest)
+		return
+	}
+
+	periods := []string{tracker.PeriodDay, tracker.PeriodWeek, tracker.PeriodMonth}
+	if period := r.URL.Query().Get("period"); period != "" {
+		name, _, err := tracker.PeriodDuration(period)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		periods = []string{name}
+	}
+
+	response := GainsResponse{
+		PlayerName: playerName,
+		Periods:    make(map[string]*tracker.Gains),
+	}
+	for _, period := range periods {
+		gains, err := s.trackerDB.GetGains(r.Context(), playerName, period)
+		if err != nil {
+			s.logger.Error("Failed to get player gains", zap.Error(err), zap.String("player", playerName))
+			http.Error(w, "Failed to get player gains", http.StatusInternalServerError)
+			return
+		}
+		response.Periods[period] = gains
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleGetQuestSyncConflicts returns quests whose recorded kills disagree with the API kill counts
+func (s *Server) handleGetQuestSyncConflicts(w http.ResponseWriter, r *http.Request) {
+	weekNumber, year := getWeekAndYear()
+
+	conflicts, err := s.db.GetQuestSyncConflicts(r.Context(), weekNumber, year)
+	if err != nil {
+		s.logger.Error("Failed to get quest sync conflicts", zap.Error(err))
+		http.Error(w, "Failed to get quest sync conflicts", http.StatusInternalServerError)
+		return
+	}
+	if conflicts == nil {
+		conflicts = []quests.QuestSyncConflict{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(QuestSyncResponse{
+		Week:      weekNumber,
+		Year:      year,
+		Conflicts: conflicts,
+	})
+}
+
+// handleGetQuestHistory returns quest completion statistics for the clan, or a single player
+// with ?player=. Accepts an optional ?weeks= (default 8, max 52).
+func (s *Server) handleGetQuestHistory(w http.ResponseWriter, r *http.Request) {
+	weeks := quests.DefaultHistoryWeeks
+	if v := r.URL.Query().Get("weeks"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 || parsed > quests.MaxHistoryWeeks {
+			http.Error(w, fmt.Sprintf("weeks must be between 1 and %d", quests.MaxHistoryWeeks), http.StatusBadRequest)
+			return
+		}
+		weeks = parsed
+	}
+	playerName := r.URL.Query().Get("player")
+
+	history, err := s.db.GetQuestHistory(r.Context(), playerName, weeks)
+	if err != nil {
+		s.logger.Error("Failed to get quest history", zap.Error(err), zap.String("player", playerName))
+		http.Error(w, "Failed to get quest history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// handleGetKeyForecast returns how many keys the clan is forecast to be short by at the weekly
+// reset for each key type this week's quests need. Accepts an optional ?days= of key history
+// to estimate drop rates from (default 28, max 365).
+func (s *Server) handleGetKeyForecast(w http.ResponseWriter, r *http.Request) {
+	days := quests.DefaultForecastWindowDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 || parsed > quests.MaxForecastWindowDays {
+			http.Error(w, fmt.Sprintf("days must be between 1 and %d", quests.MaxForecastWindowDays), http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	forecast, err := s.db.GetKeyForecast(r.Context(), days)
+	if err != nil {
+		s.logger.Error("Failed to get key forecast", zap.Error(err))
+		http.Error(w, "Failed to get key forecast", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forecast)
+}
+
+// handleGetKeyHistory returns recent key count changes, optionally filtered with ?player= and ?key=.
+// Accepts an optional ?limit= (default 50, max 500).
+func (s *Server) handleGetKeyHistory(w http.ResponseWriter, r *http.Request) {
+	limit := quests.DefaultKeyHistoryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 || parsed > quests.MaxKeyHistoryLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", quests.MaxKeyHistoryLimit), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+	playerName := r.URL.Query().Get("player")
+
+	keyType := ""
+	if v := r.URL.Query().Get("key"); v != "" {
+		resolved, ok := quests.ResolveKeyType(v)
+		if !ok {
+			http.Error(w, "Invalid key type", http.StatusBadRequest)
+			return
+		}
+		keyType = resolved
+	}
+
+	history, err := s.db.GetKeyHistory(r.Context(), playerName, keyType, limit)
+	if err != nil {
+		s.logger.Error("Failed to get key history", zap.Error(err), zap.String("player", playerName))
+		http.Error(w, "Failed to get key history", http.StatusInternalServerError)
+		return
+	}
+	if history == nil {
+		history = []quests.KeyHistoryEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
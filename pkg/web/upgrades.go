@@ -0,0 +1,96 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/jirwin/idleclans/pkg/upgrades"
+	"go.uber.org/zap"
+)
+
+// handleGetPlayerUpgrades returns a player's upgrade tiers and the cheapest next upgrades
+func (s *Server) handleGetPlayerUpgrades(w http.ResponseWriter, r *http.Request) {
+	playerName := r.PathValue("playerName")
+	if playerName == "" {
+		http.Error(w, "Player name required", http.StatusBadRequest)
+		return
+	}
+
+	player, err := s.icClient.GetPlayer(r.Context(), playerName)
+	if err != nil {
+		s.logger.Error("Failed to get player profile", zap.Error(err), zap.String("player", playerName))
+		http.Error(w, "Failed to get player profile", http.StatusBadGateway)
+		return
+	}
+
+	report, err := s.upgradesDB.BuildReport(r.Context(), s.marketDB, playerName, player)
+	if err != nil {
+		s.logger.Error("Failed to build upgrade report", zap.Error(err), zap.String("player", playerName))
+		http.Error(w, "Failed to build upgrade report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleAdminGetUpgrades returns the upgrade metadata table
+func (s *Server) handleAdminGetUpgrades(w http.ResponseWriter, r *http.Request) {
+	metadata, err := s.upgradesDB.GetUpgrades(r.Context())
+	if err != nil {
+		s.logger.Error("Failed to get upgrade metadata", zap.Error(err))
+		http.Error(w, "Failed to get upgrade metadata", http.StatusInternalServerError)
+		return
+	}
+
+	list := make([]*upgrades.Upgrade, 0, len(metadata))
+	for _, u := range metadata {
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// handleAdminUpdateUpgrade replaces the max tier and tier costs for an upgrade
+func (s *Server) handleAdminUpdateUpgrade(w http.ResponseWriter, r *http.Request) {
+	var u upgrades.Upgrade
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	u.Key = r.PathValue("key")
+
+	valid := false
+	for _, key := range upgrades.Keys() {
+		if key == u.Key {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		http.Error(w, "Unknown upgrade", http.StatusBadRequest)
+		return
+	}
+	if u.MaxTier < 0 {
+		http.Error(w, "max_tier must not be negative", http.StatusBadRequest)
+		return
+	}
+	for _, t := range u.Tiers {
+		if t.Tier <= 0 || (u.MaxTier > 0 && t.Tier > u.MaxTier) || t.GoldCost < 0 {
+			http.Error(w, "Invalid tier cost", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := s.upgradesDB.UpsertUpgrade(r.Context(), &u); err != nil {
+		s.logger.Error("Failed to update upgrade metadata", zap.Error(err), zap.String("upgrade", u.Key))
+		http.Error(w, "Failed to update upgrade metadata", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(u)
+}
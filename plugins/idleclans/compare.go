@@ -0,0 +1,126 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// compareColumnWidth is the width of each player's column in !compare tables
+const compareColumnWidth = 10
+
+func (p *plugin) compareCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		if !strings.HasPrefix(m.Content, "!compare") {
+			return
+		}
+
+		playerNames := strings.Fields(strings.TrimPrefix(m.Content, "!compare"))
+		if len(playerNames) < 2 || len(playerNames) > tracker.MaxComparePlayers {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Usage: `!compare <player> <player> [player...]` (up to %d players)", tracker.MaxComparePlayers))
+			return
+		}
+
+		l.Info(
+			"Processing compare command",
+			zap.Strings("players", playerNames),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		var questsDB *quests.DB
+		if p.questsHandler != nil {
+			questsDB = p.questsHandler.db
+		}
+
+		comparison, err := tracker.ComparePlayers(ctx, p.client, questsDB, playerNames)
+		if err != nil {
+			l.Error("Error comparing players", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error comparing players: %s", err.Error()))
+			return
+		}
+
+		s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{
+			buildCompareEmbed(comparison, tracker.CompareSkills, "Skill Levels", 0x3498db), // Blue color
+			buildCompareEmbed(comparison, tracker.ComparePvM, "PvM Kills", 0xe74c3c),       // Red color
+			buildCompareEmbed(comparison, tracker.CompareKeys, "Keys", 0xf1c40f),           // Gold color
+		})
+	}
+}
+
+// buildCompareEmbed renders one category of a comparison as a fixed-width table.
+// The leader in each row is marked with an asterisk.
+func buildCompareEmbed(comparison *tracker.Comparison, category, title string, color int) *discordgo.MessageEmbed {
+	titleCaser := cases.Title(language.English)
+
+	var rows []tracker.ComparisonRow
+	nameWidth := 0
+	for _, row := range comparison.Rows {
+		if row.Category == category {
+			rows = append(rows, row)
+			nameWidth = max(nameWidth, len(row.Name))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	sb.WriteString(strings.Repeat(" ", nameWidth))
+	for _, player := range comparison.Players {
+		if len(player) > compareColumnWidth-1 {
+			player = player[:compareColumnWidth-1]
+		}
+		sb.WriteString(fmt.Sprintf(" %*s", compareColumnWidth, player))
+	}
+	sb.WriteString("\n")
+
+	for _, row := range rows {
+		name := row.Name
+		if category != tracker.ComparePvM {
+			// PvM stats are already CamelCase; title-casing would flatten them
+			name = titleCaser.String(name)
+		}
+		sb.WriteString(fmt.Sprintf("%-*s", nameWidth, name))
+		for i, v := range row.Values {
+			cell := fmt.Sprintf("%d", v)
+			if isLeader(row, i) {
+				cell = "*" + cell
+			}
+			sb.WriteString(fmt.Sprintf(" %*s", compareColumnWidth, cell))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("```")
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: sb.String(),
+		Color:       color,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "* marks the leader",
+		},
+	}
+}
+
+func isLeader(row tracker.ComparisonRow, index int) bool {
+	for _, leader := range row.Leaders {
+		if leader == index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+package idleclans
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const compUsage = "Usage: `!comp start <skill|boss> <duration e.g. 7d or 48h> [name]`, `!comp list`, `!comp standings [id]`, `!comp cancel <id>`"
+
+func (p *plugin) compCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		parts := strings.Fields(m.Content)
+		if len(parts) == 0 || parts[0] != "!comp" {
+			return
+		}
+
+		if p.trackerDB == nil {
+			s.ChannelMessageSend(m.ChannelID, "Error: Player tracking unavailable")
+			return
+		}
+
+		if len(parts) < 2 {
+			s.ChannelMessageSend(m.ChannelID, compUsage)
+			return
+		}
+
+		l.Info(
+			"Processing competition command",
+			zap.String("command", parts[1]),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		switch parts[1] {
+		case "start":
+			p.handleCompStart(ctx, s, m, parts[2:])
+		case "list":
+			p.handleCompList(ctx, s, m)
+		case "standings":
+			p.handleCompStandings(ctx, s, m, parts[2:])
+		case "cancel":
+			p.handleCompCancel(ctx, s, m, parts[2:])
+		default:
+			s.ChannelMessageSend(m.ChannelID, compUsage)
+		}
+	}
+}
+
+// isOfficer reports whether the message author can manage messages in the channel
+func isOfficer(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	perms, err := s.UserChannelPermissions(m.Author.ID, m.ChannelID)
+	if err != nil {
+		return false
+	}
+	return perms&discordgo.PermissionManageMessages != 0
+}
+
+func (p *plugin) handleCompStart(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	if !isOfficer(s, m) {
+		s.ChannelMessageSend(m.ChannelID, "Only officers can start competitions")
+		return
+	}
+
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!comp start <skill|boss> <duration e.g. 7d or 48h> [name]`")
+		return
+	}
+
+	metricType, metric, ok := tracker.ResolveMetric(args[0])
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Unknown skill or boss: %s", args[0]))
+		return
+	}
+
+	duration, err := parseCompDuration(args[1])
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: %s", err.Error()))
+		return
+	}
+
+	name := strings.Join(args[2:], " ")
+	if name == "" {
+		name = fmt.Sprintf("%s Competition", cases.Title(language.English).String(metric))
+	}
+
+	now := time.Now()
+	competition := &tracker.Competition{
+		Name:       name,
+		MetricType: metricType,
+		Metric:     metric,
+		StartsAt:   now,
+		EndsAt:     now.Add(duration),
+		ChannelID:  m.ChannelID,
+		CreatedBy:  m.Author.Username,
+	}
+	if err := p.trackerDB.CreateCompetition(ctx, competition); err != nil {
+		l.Error("Error creating competition", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error creating competition")
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf(
+		"🏁 **%s** (#%d) has started! Most %s gained wins. Ends <t:%d:R>. Standings will be posted here every %d hours.",
+		competition.Name, competition.ID, compUnit(competition), competition.EndsAt.Unix(), competition.StandingsInterval))
+}
+
+func (p *plugin) handleCompList(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate) {
+	l := ctxzap.Extract(ctx)
+
+	competitions, err := p.trackerDB.GetCompetitions(ctx, 10)
+	if err != nil {
+		l.Error("Error getting competitions", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error getting competitions")
+		return
+	}
+	if len(competitions) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No competitions yet. Officers can start one with `!comp start <skill|boss> <duration>`")
+		return
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(competitions))
+	for _, c := range competitions {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("#%d %s", c.ID, c.Name),
+			Value:  fmt.Sprintf("%s • %s • <t:%d:d> – <t:%d:d>", c.Metric, c.Status, c.StartsAt.Unix(), c.EndsAt.Unix()),
+			Inline: false,
+		})
+	}
+
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{{
+		Title:  "Competitions",
+		Color:  0x9b59b6, // Purple color
+		Fields: fields,
+	}})
+}
+
+func (p *plugin) handleCompStandings(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	var competition *tracker.Competition
+	if len(args) > 0 {
+		id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Invalid competition ID: %s", args[0]))
+			return
+		}
+		competition, err = p.trackerDB.GetCompetition(ctx, id)
+		if err != nil {
+			l.Error("Error getting competition", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, "Error getting competition")
+			return
+		}
+	} else {
+		// Default to the most recent competition
+		competitions, err := p.trackerDB.GetCompetitions(ctx, 1)
+		if err != nil {
+			l.Error("Error getting competitions", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, "Error getting competitions")
+			return
+		}
+		if len(competitions) > 0 {
+			competition = &competitions[0]
+		}
+	}
+	if competition == nil {
+		s.ChannelMessageSend(m.ChannelID, "Competition not found")
+		return
+	}
+
+	standings, err := p.trackerDB.GetStandings(ctx, competition.ID)
+	if err != nil {
+		l.Error("Error getting competition standings", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error getting competition standings")
+		return
+	}
+
+	printer := message.NewPrinter(language.English)
+	unit := compUnit(competition)
+
+	var sb strings.Builder
+	for _, standing := range standings {
+		line := printer.Sprintf("**%d.** %s — %.0f %s\n", standing.Rank, standing.PlayerName, standing.Gain, unit)
+		if sb.Len()+len(line) > 4000 {
+			sb.WriteString("*...*")
+			break
+		}
+		sb.WriteString(line)
+	}
+	if len(standings) == 0 {
+		sb.WriteString("No values captured yet. Start values are recorded shortly after the competition begins.")
+	}
+
+	footer := fmt.Sprintf("%s • %s", competition.Status, competition.Metric)
+	if competition.LastStandingsAt != nil {
+		footer += fmt.Sprintf(" • Last updated %s UTC", competition.LastStandingsAt.UTC().Format("Jan 2 15:04"))
+	}
+
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{{
+		Title:       fmt.Sprintf("#%d %s", competition.ID, competition.Name),
+		Description: sb.String(),
+		Color:       0x3498db, // Blue color
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: footer,
+		},
+	}})
+}
+
+func (p *plugin) handleCompCancel(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	if !isOfficer(s, m) {
+		s.ChannelMessageSend(m.ChannelID, "Only officers can cancel competitions")
+		return
+	}
+
+	if len(args) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!comp cancel <id>`")
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Invalid competition ID: %s", args[0]))
+		return
+	}
+
+	err = p.trackerDB.CancelCompetition(ctx, id)
+	if err == sql.ErrNoRows {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Competition #%d not found or already over", id))
+		return
+	}
+	if err != nil {
+		l.Error("Error cancelling competition", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error cancelling competition")
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Competition #%d cancelled", id))
+}
+
+// compUnit returns what a competition measures, for display
+func compUnit(c *tracker.Competition) string {
+	if c.MetricType == tracker.MetricBoss {
+		return c.Metric + " kills"
+	}
+	return c.Metric + " XP"
+}
+
+// parseCompDuration parses a competition length such as "7d", "48h" or "1d12h"
+func parseCompDuration(input string) (time.Duration, error) {
+	duration, err := parseDayDuration(input)
+	if err != nil {
+		return 0, err
+	}
+	if duration < time.Hour {
+		return 0, fmt.Errorf("competitions must last at least an hour")
+	}
+	return duration, nil
+}
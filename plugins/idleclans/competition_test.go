@@ -0,0 +1,37 @@
+package idleclans
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCompDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "7d", want: 7 * 24 * time.Hour},
+		{input: "1d12h", want: 36 * time.Hour},
+		{input: "1h", want: time.Hour},
+		{input: "59m", wantErr: true}, // Competitions last at least an hour
+		{input: "week", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseCompDuration(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseCompDuration(%q) = %v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCompDuration(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseCompDuration(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package idleclans
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseDayDuration parses a duration that may start with a number of days, such as "1d12h" or "90m"
+func parseDayDuration(input string) (time.Duration, error) {
+	input = strings.ToLower(input)
+
+	var days int
+	if idx := strings.Index(input, "d"); idx >= 0 {
+		n, err := strconv.Atoi(input[:idx])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", input)
+		}
+		days = n
+		input = input[idx+1:]
+	}
+
+	duration := time.Duration(days) * 24 * time.Hour
+	if input != "" {
+		d, err := time.ParseDuration(input)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", input)
+		}
+		duration += d
+	}
+	return duration, nil
+}
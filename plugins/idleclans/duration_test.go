@@ -0,0 +1,41 @@
+package idleclans
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDayDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "7d", want: 7 * 24 * time.Hour},
+		{input: "1d12h", want: 36 * time.Hour},
+		{input: "48h", want: 48 * time.Hour},
+		{input: "90m", want: 90 * time.Minute},
+		{input: "2D30M", want: 48*time.Hour + 30*time.Minute},
+		{input: "d", wantErr: true},
+		{input: "xd", wantErr: true},
+		{input: "1d5x", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseDayDuration(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseDayDuration(%q) = %v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDayDuration(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseDayDuration(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
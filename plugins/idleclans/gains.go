@@ -0,0 +1,125 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+func (p *plugin) gainsCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		if !strings.HasPrefix(m.Content, "!gains") {
+			return
+		}
+
+		if p.trackerDB == nil {
+			s.ChannelMessageSend(m.ChannelID, "Error: Player tracking unavailable")
+			return
+		}
+
+		parts := strings.Fields(strings.TrimPrefix(m.Content, "!gains"))
+		if len(parts) == 0 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!gains <player> [day|week|month]`")
+			return
+		}
+
+		playerName := parts[0]
+		period := tracker.PeriodWeek
+		if len(parts) > 1 {
+			name, _, err := tracker.PeriodDuration(parts[1])
+			if err != nil {
+				s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: %s", err.Error()))
+				return
+			}
+			period = name
+		}
+
+		l.Info(
+			"Processing gains command",
+			zap.String("player", playerName),
+			zap.String("period", period),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		gains, err := p.trackerDB.GetGains(ctx, playerName, period)
+		if err != nil {
+			l.Error("Error getting player gains", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, "Error getting player gains")
+			return
+		}
+		if gains == nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Not enough snapshot history for %s yet. Snapshots are taken periodically for registered players.", playerName))
+			return
+		}
+
+		s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{buildGainsEmbed(gains)})
+	}
+}
+
+// buildGainsEmbed renders a player's gains as an embed
+func buildGainsEmbed(gains *tracker.Gains) *discordgo.MessageEmbed {
+	printer := message.NewPrinter(language.English)
+	titleCaser := cases.Title(language.English)
+
+	var skillsValue strings.Builder
+	for _, gain := range gains.Skills {
+		skillsValue.WriteString(printer.Sprintf("**%s**: +%.0f XP", titleCaser.String(gain.Skill), gain.XPGained))
+		if gain.LevelsGained > 0 {
+			skillsValue.WriteString(fmt.Sprintf(" (%d → %d)", gain.StartLevel, gain.EndLevel))
+		}
+		skillsValue.WriteString("\n")
+	}
+	if skillsValue.Len() == 0 {
+		skillsValue.WriteString("No XP gained")
+	}
+
+	var pvmValue strings.Builder
+	for _, gain := range gains.PvM {
+		pvmValue.WriteString(printer.Sprintf("**%s**: +%d\n", gain.Boss, gain.Kills))
+	}
+	if pvmValue.Len() == 0 {
+		pvmValue.WriteString("No kills")
+	}
+
+	footer := fmt.Sprintf("%s → %s UTC", gains.From.UTC().Format("Jan 2 15:04"), gains.To.UTC().Format("Jan 2 15:04"))
+	if gains.PartialRange {
+		footer += " (tracking started within this period)"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Gains: %s (%s)", gains.PlayerName, titleCaser.String(gains.Period)),
+		Description: printer.Sprintf("**%.0f** XP • **%d** levels • **%d** boss kills", gains.TotalXP, gains.TotalLevels, gains.TotalKills),
+		Color:       0x2ecc71, // Green color
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Skills",
+				Value:  skillsValue.String(),
+				Inline: true,
+			},
+			{
+				Name:   "PvM",
+				Value:  pvmValue.String(),
+				Inline: true,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: footer,
+		},
+	}
+}
@@ -2,11 +2,15 @@ package idleclans
 
 import (
 	"context"
-	"fmt"
+	"os"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"github.com/jirwin/idleclans/pkg/bot"
 	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/market"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"github.com/jirwin/idleclans/pkg/upgrades"
 	"go.uber.org/zap"
 )
 
@@ -16,7 +20,20 @@ type DataChangeNotifier func(changeType string)
 type plugin struct {
 	client       *idleclans.Client
 	questsHandler *questsHandler
+	questsDB     *quests.DB
+	plannerConfig PlannerConfig
 	notifyFunc   DataChangeNotifier
+	analyzer     ScreenshotAnalyzer
+	parties      PartyCreator
+	trackerDB    *tracker.DB
+	tracker      *tracker.Tracker
+	marketDB     *market.DB
+	upgradesDB   *upgrades.DB
+
+	// marketEnabled is set when the market collector is running, so the market DB has price history
+	marketEnabled bool
+	webBaseURL    string
+	clanGuild     string // Guild whose members are recorded in the clan history when looked up
 }
 
 func (p *plugin) Name() string {
@@ -26,45 +43,76 @@ func (p *plugin) Name() string {
 func (p *plugin) Load(ctx context.Context) []bot.Option {
 	p.client.Run(ctx)
 
-	// Initialize quests handler
+	p.marketEnabled = os.Getenv("ENABLE_MARKET") == "true" || os.Getenv("ENABLE_MARKET") == "1"
+	p.webBaseURL = os.Getenv("WEB_BASE_URL")
+	p.clanGuild = os.Getenv("REQUIRED_GUILD")
+	if p.webBaseURL == "" {
+		p.webBaseURL = "https://idleclans.jirwin.dev"
+	}
+
+	// Initialize quests handler on the web server's database connection
 	var err error
-	p.questsHandler, err = newQuestsHandler()
-	if err != nil {
-		// Log error but continue - quests will be unavailable
-		ctxzap.Extract(ctx).Error("Failed to initialize quests handler", zap.Error(err))
+	if p.questsDB != nil {
+		p.questsHandler = newQuestsHandler(p.questsDB, p.plannerConfig)
+	} else {
+		// Continue without quests - they need the web server's database
+		ctxzap.Extract(ctx).Info("Quest system unavailable (web server not configured)")
 	}
 	
+	// Player snapshot history shares the quests database connection
+	if p.questsHandler != nil {
+		p.trackerDB, err = tracker.NewDB(p.questsHandler.db.GetDB(), ctxzap.Extract(ctx))
+		if err != nil {
+			ctxzap.Extract(ctx).Error("Failed to initialize tracker database", zap.Error(err))
+		}
+
+		p.marketDB, err = market.NewDB(p.questsHandler.db.GetDB(), ctxzap.Extract(ctx))
+		if err != nil {
+			ctxzap.Extract(ctx).Error("Failed to initialize market database", zap.Error(err))
+		}
+
+		p.upgradesDB, err = upgrades.NewDB(p.questsHandler.db.GetDB(), ctxzap.Extract(ctx))
+		if err != nil {
+			ctxzap.Extract(ctx).Error("Failed to initialize upgrades database", zap.Error(err))
+		}
+	}
+
 	// Pass notify function to handler if set
 	if p.questsHandler != nil && p.notifyFunc != nil {
 		p.questsHandler.notifyFunc = p.notifyFunc
 	}
+	if p.questsHandler != nil {
+		p.questsHandler.analyzer = p.analyzer
+		p.questsHandler.parties = p.parties
+	}
 
 	opts := []bot.Option{
 		bot.WithMessageHandler(p.priceCmd(ctx)),
+		bot.WithMessageHandler(p.marketCmd(ctx)),
+		bot.WithMessageHandler(p.watchCmd(ctx)),
 		bot.WithMessageHandler(p.pvmCmd(ctx)),
 		bot.WithMessageHandler(p.playerCmd(ctx)),
+		bot.WithMessageHandler(p.xpCmd(ctx)),
+		bot.WithMessageHandler(p.gainsCmd(ctx)),
+		bot.WithMessageHandler(p.compCmd(ctx)),
+		bot.WithMessageHandler(p.networthCmd(ctx)),
+		bot.WithMessageHandler(p.upgradesCmd(ctx)),
+		bot.WithMessageHandler(p.compareCmd(ctx)),
 		bot.WithMessageHandler(p.questsCmd(ctx)),
+		bot.WithMessageHandler(p.keysCmd(ctx)),
+		bot.WithMessageHandler(p.lfgCmd(ctx)),
+		bot.WithMessageHandler(p.partyCmd(ctx)),
 		bot.WithMessageHandler(p.bossPingCmd(ctx)),
+		bot.WithInteractionHandler(p.scanInteraction(ctx)),
+		bot.WithInteractionHandler(p.rsvpInteraction(ctx)),
 	}
 
 	return opts
 }
 
+// Close stops the API client. The quests database belongs to the web server, which closes it.
 func (p *plugin) Close(ctx context.Context) error {
-	var err error
-	if p.questsHandler != nil {
-		if closeErr := p.questsHandler.close(); closeErr != nil {
-			err = closeErr
-		}
-	}
-	if closeErr := p.client.Close(ctx); closeErr != nil {
-		if err != nil {
-			err = fmt.Errorf("multiple errors: %w; %w", err, closeErr)
-		} else {
-			err = closeErr
-		}
-	}
-	return err
+	return p.client.Close(ctx)
 }
 
 func New() bot.Plugin {
@@ -73,6 +121,23 @@ func New() bot.Plugin {
 	}
 }
 
+// SetQuestsDB sets the database connection the quest commands use. It's the web server's
+// connection, so quests are unavailable without the web server.
+func (p *plugin) SetQuestsDB(db *quests.DB) {
+	p.questsDB = db
+}
+
+// SetTracker sets the web server's player snapshot tracker, which paces profile requests
+// shared with its snapshot pass
+func (p *plugin) SetTracker(t *tracker.Tracker) {
+	p.tracker = t
+}
+
+// SetPlannerConfig sets how the quest commands' party planner is configured
+func (p *plugin) SetPlannerConfig(config PlannerConfig) {
+	p.plannerConfig = config
+}
+
 // SetNotifyFunc sets the function to call when data changes
 func (p *plugin) SetNotifyFunc(fn DataChangeNotifier) {
 	p.notifyFunc = fn
@@ -81,6 +146,22 @@ func (p *plugin) SetNotifyFunc(fn DataChangeNotifier) {
 	}
 }
 
+// SetScreenshotAnalyzer sets the analyzer used by `!quests scan`
+func (p *plugin) SetScreenshotAnalyzer(analyzer ScreenshotAnalyzer) {
+	p.analyzer = analyzer
+	if p.questsHandler != nil {
+		p.questsHandler.analyzer = analyzer
+	}
+}
+
+// SetPartyCreator sets how `!lfg` creates web party sessions for the groups it forms
+func (p *plugin) SetPartyCreator(creator PartyCreator) {
+	p.parties = creator
+	if p.questsHandler != nil {
+		p.questsHandler.parties = creator
+	}
+}
+
 // notifyDataChange notifies connected clients of data changes
 func (p *plugin) notifyDataChange(changeType string) {
 	if p.notifyFunc != nil {
@@ -0,0 +1,86 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+// handleKeyForecast shows how many keys the clan is forecast to be short by at the weekly reset.
+// Usage: !keys forecast [days of history for drop rates]
+func (h *questsHandler) handleKeyForecast(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	days := quests.DefaultForecastWindowDays
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > quests.MaxForecastWindowDays {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Days must be between 1 and %d", quests.MaxForecastWindowDays))
+			return
+		}
+		days = n
+	}
+
+	forecast, err := h.db.GetKeyForecast(ctx, days)
+	if err != nil {
+		l.Error("Failed to get key forecast", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error getting key forecast: %s", err.Error()))
+		return
+	}
+	if len(forecast.Keys) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No keys needed for this week's remaining quests 🎉")
+		return
+	}
+
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{buildKeyForecastEmbed(forecast)})
+}
+
+func buildKeyForecastEmbed(forecast *quests.KeyForecast) *discordgo.MessageEmbed {
+	var short, covered strings.Builder
+	for _, item := range forecast.Keys {
+		bosses := make([]string, 0, len(item.Bosses))
+		for _, boss := range item.Bosses {
+			bosses = append(bosses, formatBossNameWithEmoji(boss))
+		}
+		line := fmt.Sprintf("%s (%s) — need %d, have %d, +%d expected (%.1f/day)",
+			formatKeyTypeWithEmoji(item.KeyType), strings.Join(bosses, ", "),
+			item.KeysNeeded, item.KeysHeld, item.ExpectedDrops, item.DropsPerDay)
+		if item.Short > 0 {
+			short.WriteString(fmt.Sprintf("**Short by %d** — %s\n", item.Short, line))
+		} else {
+			covered.WriteString(line + "\n")
+		}
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	if short.Len() > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "⚠️ Keys short",
+			Value:  short.String(),
+			Inline: false,
+		})
+	}
+	if covered.Len() > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "✅ Covered",
+			Value:  covered.String(),
+			Inline: false,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Key Forecast - Week %d, %d", forecast.Week, forecast.Year),
+		Description: fmt.Sprintf("Keys needed for the clan's remaining quests before the reset <t:%d:R>", forecast.ResetAt.Unix()),
+		Color:       0xf1c40f, // Yellow color
+		Fields:      fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Needs include the %.0f%% yoink bonus • Drop rates from the last %d days of key updates", quests.YoinkBonus*100, forecast.WindowDays),
+		},
+	}
+}
@@ -0,0 +1,182 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+const (
+	// keyLedgerEntriesShown is how many recent entries a player's ledger view lists
+	keyLedgerEntriesShown = 15
+	// keyLedgerBalancesShown is how many players the clan ledger view lists
+	keyLedgerBalancesShown = 20
+)
+
+// keysCmd is a shortcut for `!quests keys`, so `!keys ledger` and `!keys give` work directly
+func (p *plugin) keysCmd(ctx context.Context) bot.MessageHandler {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if p.questsHandler == nil {
+			return
+		}
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		parts := strings.Fields(m.Content)
+		if len(parts) == 0 || parts[0] != "!keys" {
+			return
+		}
+
+		p.questsHandler.handleKeys(ctx, s, m, parts[1:])
+	}
+}
+
+// handleKeyLedger shows each player's net key contribution, or a player's recent ledger entries.
+// Usage: !keys ledger [player]
+func (h *questsHandler) handleKeyLedger(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	if len(args) > 0 {
+		playerName := strings.Join(args, " ")
+		entries, err := h.db.GetKeyLedgerEntries(ctx, playerName, keyLedgerEntriesShown)
+		if err != nil {
+			l.Error("Failed to get key ledger entries", zap.Error(err), zap.String("player", playerName))
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error getting key ledger: %s", err.Error()))
+			return
+		}
+		if len(entries) == 0 {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("No key ledger entries for **%s**", playerName))
+			return
+		}
+
+		var sb strings.Builder
+		for i := range entries {
+			sb.WriteString(formatKeyLedgerEntry(&entries[i]))
+			sb.WriteString("\n")
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("Key Ledger - %s", playerName),
+			Description: sb.String(),
+			Color:       0xf1c40f, // Yellow color
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("Most recent %d entries", len(entries)),
+			},
+		}
+		s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{embed})
+		return
+	}
+
+	balances, err := h.db.GetKeyLedgerBalances(ctx)
+	if err != nil {
+		l.Error("Failed to get key ledger balances", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error getting key ledger: %s", err.Error()))
+		return
+	}
+	if len(balances) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No key transfers or party key usage recorded yet")
+		return
+	}
+
+	var sb strings.Builder
+	for i, b := range balances {
+		if i >= keyLedgerBalancesShown {
+			sb.WriteString(fmt.Sprintf("*...and %d more*\n", len(balances)-keyLedgerBalancesShown))
+			break
+		}
+		indicator := "⚪"
+		if b.Net >= 0.5 {
+			indicator = "🟢"
+		} else if b.Net <= -0.5 {
+			indicator = "🔴"
+		}
+		sb.WriteString(fmt.Sprintf("%s **%s**: %+.1f (gave %.1f, received %.1f)\n", indicator, b.PlayerName, b.Net, b.Given, b.Received))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Key Ledger - Net Contributions",
+		Description: sb.String(),
+		Color:       0xf1c40f, // Yellow color
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Party keys are split evenly between the party's players • 🟢 owed keys • 🔴 owes keys",
+		},
+	}
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{embed})
+}
+
+// handleKeyGive records keys given from the caller's registered player to another player.
+// Usage: !keys give <player> <key> <count>
+func (h *questsHandler) handleKeyGive(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	const usage = "Usage: `!keys give <player> <key> <count>`"
+	if len(args) < 3 {
+		s.ChannelMessageSend(m.ChannelID, usage)
+		return
+	}
+
+	count, err := strconv.Atoi(args[len(args)-1])
+	if err != nil || count <= 0 {
+		s.ChannelMessageSend(m.ChannelID, "Error: Count must be a positive number")
+		return
+	}
+	keyType, ok := quests.ResolveKeyType(args[len(args)-2])
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Unknown key type '%s'", args[len(args)-2]))
+		return
+	}
+	toPlayer := strings.Join(args[:len(args)-2], " ")
+
+	fromPlayer, err := h.db.GetPlayerName(ctx, m.Author.ID)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Error: You need to register first with `!quests register <player_name>`")
+		return
+	}
+	if strings.EqualFold(fromPlayer, toPlayer) {
+		s.ChannelMessageSend(m.ChannelID, "Error: You can't give keys to yourself")
+		return
+	}
+
+	if err := h.db.TransferKeys(ctx, fromPlayer, toPlayer, keyType, count, m.Author.ID); err != nil {
+		l.Error("Failed to transfer keys",
+			zap.Error(err),
+			zap.String("from", fromPlayer),
+			zap.String("to", toPlayer))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error giving keys: %s", err.Error()))
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("**%s** gave %d %s to **%s**", fromPlayer, count, formatKeyTypeWithEmoji(keyType), toPlayer))
+	h.notifyDataChange("keys")
+}
+
+// formatKeyLedgerEntry renders one ledger entry as a single line
+func formatKeyLedgerEntry(e *quests.KeyLedgerEntry) string {
+	date := e.CreatedAt.Format("Jan 02")
+	key := formatKeyTypeWithEmoji(e.KeyType)
+
+	switch e.EntryType {
+	case quests.LedgerTransfer:
+		to := ""
+		if e.ToPlayer != nil {
+			to = *e.ToPlayer
+		}
+		return fmt.Sprintf("`%s` **%s** gave %d %s to **%s**", date, e.FromPlayer, e.Count, key, to)
+	case quests.LedgerConsumed:
+		line := fmt.Sprintf("`%s` **%s** used %d %s for %s", date, e.FromPlayer, e.Count, key, strings.Join(e.BeneficiaryList(), ", "))
+		if e.Count < 0 {
+			line = fmt.Sprintf("`%s` **%s** correction of %d %s", date, e.FromPlayer, e.Count, key)
+		}
+		return line
+	default:
+		return fmt.Sprintf("`%s` %s %d %s", date, e.EntryType, e.Count, key)
+	}
+}
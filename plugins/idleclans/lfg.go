@@ -0,0 +1,432 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+// lfgQueueTTL is how long a player stays queued before their entry expires
+const lfgQueueTTL = 30 * time.Minute
+
+const lfgUsage = "Usage: `!lfg <boss> [player]` to queue, `!lfg leave [boss]` to leave, or `!lfg` to see the queues"
+
+// PartyCreator saves a group of players as a web party session and returns its URL.
+// It is provided by the web server, which owns the party pages.
+type PartyCreator interface {
+	CreateParty(ctx context.Context, players []string) (string, error)
+}
+
+// lfgEntry is a Discord user queued for a boss with one of their players
+type lfgEntry struct {
+	userID     string
+	playerName string
+	needs      int // Kills remaining on the boss quest
+	keys       int // Keys for the boss
+	queuedAt   time.Time
+}
+
+// lfgQueue holds the players looking for a group, per boss
+type lfgQueue struct {
+	mu      sync.Mutex
+	queues  map[string][]*lfgEntry // boss -> entries, oldest first
+	forming sync.Mutex             // Serializes group formation so nobody is grouped twice
+}
+
+// pruneLocked drops expired entries and empty queues. q.mu must be held.
+func (q *lfgQueue) pruneLocked() {
+	for boss, entries := range q.queues {
+		kept := entries[:0]
+		for _, e := range entries {
+			if time.Since(e.queuedAt) <= lfgQueueTTL {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == 0 {
+			delete(q.queues, boss)
+		} else {
+			q.queues[boss] = kept
+		}
+	}
+}
+
+// join queues an entry for a boss, replacing the user's previous entry for it
+func (q *lfgQueue) join(boss string, entry *lfgEntry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.queues == nil {
+		q.queues = make(map[string][]*lfgEntry)
+	}
+	q.pruneLocked()
+
+	entries := q.queues[boss]
+	for i, e := range entries {
+		if e.userID == entry.userID {
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+	q.queues[boss] = append(entries, entry)
+}
+
+// leave removes a user from a boss queue, or from every queue if boss is empty.
+// It returns how many entries were removed.
+func (q *lfgQueue) leave(userID, boss string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	removed := 0
+	for b, entries := range q.queues {
+		if boss != "" && b != boss {
+			continue
+		}
+		kept := entries[:0]
+		for _, e := range entries {
+			if e.userID == userID {
+				removed++
+				continue
+			}
+			kept = append(kept, e)
+		}
+		q.queues[b] = kept
+	}
+	q.pruneLocked()
+	return removed
+}
+
+// entries returns a copy of the unexpired entries for a boss
+func (q *lfgQueue) entries(boss string) []lfgEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pruneLocked()
+
+	result := make([]lfgEntry, 0, len(q.queues[boss]))
+	for _, e := range q.queues[boss] {
+		result = append(result, *e)
+	}
+	return result
+}
+
+// all returns a copy of every unexpired queue
+func (q *lfgQueue) all() map[string][]lfgEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pruneLocked()
+
+	result := make(map[string][]lfgEntry, len(q.queues))
+	for boss, entries := range q.queues {
+		for _, e := range entries {
+			result[boss] = append(result[boss], *e)
+		}
+	}
+	return result
+}
+
+func (p *plugin) lfgCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if p.questsHandler == nil {
+			return
+		}
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		parts := strings.Fields(m.Content)
+		if len(parts) == 0 || parts[0] != "!lfg" {
+			return
+		}
+
+		l.Info(
+			"Processing lfg command",
+			zap.Strings("args", parts[1:]),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		h := p.questsHandler
+		if len(parts) == 1 {
+			h.handleLFGList(s, m)
+			return
+		}
+
+		switch strings.ToLower(parts[1]) {
+		case "leave":
+			h.handleLFGLeave(s, m, parts[2:])
+		case "help":
+			s.ChannelMessageSend(m.ChannelID, lfgUsage)
+		default:
+			h.handleLFGJoin(ctx, s, m, parts[1:])
+		}
+	}
+}
+
+// handleLFGJoin queues the user for a boss and forms a group if the queue is ready.
+// Usage: !lfg <boss> [player]
+func (h *questsHandler) handleLFGJoin(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	bossName, ok := quests.ResolveBossName(args[0])
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Unknown boss '%s'. %s", args[0], lfgUsage))
+		return
+	}
+	keyType, _ := quests.GetKeyForBoss(bossName)
+
+	playerNames, err := h.db.GetAllPlayerNames(ctx, m.Author.ID)
+	if err != nil || len(playerNames) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "Error: You need to register first with `!quests register <player_name>`")
+		return
+	}
+	if len(args) > 1 {
+		requested := strings.Join(args[1:], " ")
+		var matched []string
+		for _, name := range playerNames {
+			if strings.EqualFold(name, requested) {
+				matched = append(matched, name)
+			}
+		}
+		if len(matched) == 0 {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: **%s** isn't one of your registered players", requested))
+			return
+		}
+		playerNames = matched
+	}
+
+	weekNumber, year := getCurrentWeek()
+	questPlayers, err := h.db.GetPlayersWithBossQuest(ctx, bossName, weekNumber, year)
+	if err != nil {
+		l.Error("Failed to get players with boss quest", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error finding quests: %s", err.Error()))
+		return
+	}
+
+	// Queue the first player with the quest, otherwise the one with the most keys to help out
+	var entry *lfgEntry
+	for _, name := range playerNames {
+		needs := 0
+		for _, pqi := range questPlayers {
+			if strings.EqualFold(pqi.PlayerName, name) {
+				needs = pqi.RequiredKills - pqi.CurrentKills
+			}
+		}
+		keys, err := h.db.GetPlayerKeyCount(ctx, name, keyType)
+		if err != nil {
+			l.Error("Failed to get key count", zap.Error(err), zap.String("player", name))
+		}
+		if needs == 0 && keys == 0 {
+			continue
+		}
+		candidate := &lfgEntry{userID: m.Author.ID, playerName: name, needs: needs, keys: keys, queuedAt: time.Now()}
+		if entry == nil || (entry.needs == 0 && (needs > 0 || keys > entry.keys)) {
+			entry = candidate
+		}
+	}
+	if entry == nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("None of your players have a %s quest or %s keys this week", formatBossNameWithEmoji(bossName), keyType))
+		return
+	}
+
+	h.lfg.join(bossName, entry)
+
+	role := fmt.Sprintf("%d kills needed", entry.needs)
+	if entry.needs == 0 {
+		role = "helping"
+	}
+	if entry.keys > 0 {
+		role += fmt.Sprintf(", %d keys", entry.keys)
+	}
+	queued := len(h.lfg.entries(bossName))
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Queued **%s** for %s (%s) • %d in queue • expires in %d minutes",
+		entry.playerName, formatBossNameWithEmoji(bossName), role, queued, int(lfgQueueTTL.Minutes())))
+
+	h.tryFormLFGGroup(ctx, s, m.ChannelID, bossName)
+}
+
+// tryFormLFGGroup forms a party from a boss queue once it has enough players, at least one of
+// them needing the boss and, unless it needs no key, one holding its key. The group comes from
+// the quest planner.
+func (h *questsHandler) tryFormLFGGroup(ctx context.Context, s *discordgo.Session, channelID, bossName string) {
+	l := ctxzap.Extract(ctx)
+
+	h.lfg.forming.Lock()
+	defer h.lfg.forming.Unlock()
+
+	entries := h.lfg.entries(bossName)
+	if len(entries) < quests.GetPartySizeLimit(bossName).Max {
+		return
+	}
+	hasNeed, hasKeys := false, false
+	players := make([]string, 0, len(entries))
+	userByPlayer := make(map[string]string, len(entries))
+	for _, e := range entries {
+		hasNeed = hasNeed || e.needs > 0
+		hasKeys = hasKeys || e.keys > 0
+		players = append(players, e.playerName)
+		userByPlayer[e.playerName] = e.userID
+	}
+	if _, needsKey := quests.GetKeyForBoss(bossName); !hasNeed || (needsKey && !hasKeys) {
+		return
+	}
+
+	weekNumber, year := getCurrentWeek()
+	planner := h.newPlanner()
+	plan, err := planner.GeneratePlanFiltered(ctx, weekNumber, year, players)
+	if err != nil {
+		l.Error("Failed to plan lfg group", zap.Error(err), zap.String("boss", bossName))
+		return
+	}
+
+	// Use the first planned party that kills this boss with a key (or without one, if it needs none)
+	var group *quests.Party
+	for i := range plan.Parties {
+		for _, task := range plan.Parties[i].Tasks {
+			if task.BossName == bossName && !task.NoKeys {
+				group = &plan.Parties[i]
+				break
+			}
+		}
+		if group != nil {
+			break
+		}
+	}
+	if group == nil || len(group.Players) < quests.GetPartySizeLimit(bossName).Max {
+		return
+	}
+
+	// Grouped players leave every queue
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, name := range group.Players {
+		userID := userByPlayer[name]
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		h.lfg.leave(userID, "")
+		mentions = append(mentions, fmt.Sprintf("<@%s>", userID))
+	}
+
+	var tasks strings.Builder
+	for _, task := range group.Tasks {
+		if task.NoKeys {
+			tasks.WriteString(fmt.Sprintf("%s ×%d (⚠️ No keys)\n", formatBossNameWithEmoji(task.BossName), task.Kills))
+			continue
+		}
+		if task.KeyHolder == "" {
+			tasks.WriteString(fmt.Sprintf("%s ×%d\n", formatBossNameWithEmoji(task.BossName), task.Kills))
+			continue
+		}
+		tasks.WriteString(fmt.Sprintf("%s ×%d — keys from **%s**\n", formatBossNameWithEmoji(task.BossName), task.Kills, task.KeyHolder))
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Players",
+			Value:  strings.Join(group.Players, ", "),
+			Inline: false,
+		},
+		{
+			Name:   "Plan",
+			Value:  tasks.String(),
+			Inline: false,
+		},
+	}
+
+	if h.parties != nil {
+		partyURL, err := h.parties.CreateParty(ctx, group.Players)
+		if err != nil {
+			l.Error("Failed to create lfg party", zap.Error(err), zap.Strings("players", group.Players))
+		} else {
+			fields = append(fields, &discordgo.MessageEmbedField{
+				Name:   "Party Link",
+				Value:  fmt.Sprintf("[Join Party](%s)", partyURL),
+				Inline: false,
+			})
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:  fmt.Sprintf("%s Group Ready!", formatBossNameWithEmoji(bossName)),
+		Color:  0x2ecc71, // Green color
+		Fields: fields,
+	}
+	s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: strings.Join(mentions, " ") + " - your group is ready!",
+		Embeds:  []*discordgo.MessageEmbed{embed},
+	})
+
+	l.Info("Formed lfg group", zap.String("boss", bossName), zap.Strings("players", group.Players))
+}
+
+// handleLFGLeave removes the user from one boss queue or all of them.
+// Usage: !lfg leave [boss]
+func (h *questsHandler) handleLFGLeave(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	bossName := ""
+	if len(args) > 0 {
+		var ok bool
+		if bossName, ok = quests.ResolveBossName(args[0]); !ok {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Unknown boss '%s'", args[0]))
+			return
+		}
+	}
+
+	if h.lfg.leave(m.Author.ID, bossName) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "You're not in any matching queue")
+		return
+	}
+	if bossName == "" {
+		s.ChannelMessageSend(m.ChannelID, "Left all queues")
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Left the %s queue", formatBossNameWithEmoji(bossName)))
+}
+
+// handleLFGList shows everyone currently queued
+func (h *questsHandler) handleLFGList(s *discordgo.Session, m *discordgo.MessageCreate) {
+	queues := h.lfg.all()
+	if len(queues) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "Nobody is looking for a group. "+lfgUsage)
+		return
+	}
+
+	bosses := make([]string, 0, len(queues))
+	for boss := range queues {
+		bosses = append(bosses, boss)
+	}
+	sort.Strings(bosses)
+
+	var fields []*discordgo.MessageEmbedField
+	for _, boss := range bosses {
+		var sb strings.Builder
+		for _, e := range queues[boss] {
+			left := lfgQueueTTL - time.Since(e.queuedAt)
+			sb.WriteString(fmt.Sprintf("**%s** — %d kills, %d keys (%dm left)\n", e.playerName, e.needs, e.keys, int(left.Minutes())))
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%s (%d/%d)", formatBossNameWithEmoji(boss), len(queues[boss]), quests.GetPartySizeLimit(boss).Max),
+			Value:  sb.String(),
+			Inline: false,
+		})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:  "Looking for Group",
+		Color:  0xe67e22, // Orange color
+		Fields: fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "A group forms when enough players are queued with a quest and a key holder",
+		},
+	}
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{embed})
+}
@@ -3,16 +3,29 @@ package idleclans
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/market"
 	"go.uber.org/zap"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
 
+const (
+	// maxPriceItems is the most items a single !price can look up
+	maxPriceItems = 5
+	// sparklineWidth is the number of characters in a !price sparkline
+	sparklineWidth = 24
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
 func (p *plugin) priceCmd(ctx context.Context) bot.MessageHandler {
 	l := ctxzap.Extract(ctx)
 	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
@@ -21,31 +34,413 @@ func (p *plugin) priceCmd(ctx context.Context) bot.MessageHandler {
 		}
 
 		if strings.HasPrefix(m.Content, "!price") {
-			itemID := strings.TrimSpace(strings.TrimPrefix(m.Content, "!price"))
+			var items []string
+			for _, item := range strings.Split(strings.TrimPrefix(m.Content, "!price"), ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			if len(items) == 0 {
+				s.ChannelMessageSend(m.ChannelID, "Usage: `!price <item>[, <item>...]`")
+				return
+			}
+			if len(items) > maxPriceItems {
+				s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: At most %d items can be priced at once", maxPriceItems))
+				return
+			}
 
 			l.Info(
-				"Processing pvm command",
-				zap.String("item_id", itemID),
+				"Processing price command",
+				zap.Strings("items", items),
 				zap.String("from", m.Author.Username),
 				zap.String("channel", m.ChannelID),
 			)
 
-			price, err := p.client.GetLatestPrice(ctx, itemID)
-			if err != nil {
-				s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error getting price: %s", err.Error()))
+			// Without market tracking there is no history, so fall back to the live prices
+			if !p.marketEnabled || p.marketDB == nil {
+				p.sendLivePrices(ctx, s, m, items)
 				return
 			}
-			p := message.NewPrinter(language.English)
-			s.ChannelMessageSend(
-				m.ChannelID,
-				p.Sprintf(
-					"Lowest Sell: %dg (%d)\nHighest Buy: %dg (%d)",
-					price.LowestSellPrice,
-					price.LowestPriceVolume,
-					price.HighestBuyPrice,
-					price.HighestPriceVolume,
-				),
-			)
+
+			analytics := market.NewAnalytics(p.marketDB)
+			var embeds []*discordgo.MessageEmbed
+			var missing []string
+			for _, name := range items {
+				item, err := p.findMarketItem(ctx, name)
+				if err != nil {
+					l.Error("Error looking up market item", zap.Error(err), zap.String("item", name))
+				}
+				if item == nil {
+					missing = append(missing, name)
+					continue
+				}
+
+				embed, err := p.buildPriceEmbed(ctx, analytics, item)
+				if err != nil {
+					l.Error("Error building price summary", zap.Error(err), zap.Int("item_id", item.ID))
+					missing = append(missing, name)
+					continue
+				}
+				embeds = append(embeds, embed)
+			}
+
+			if len(missing) > 0 {
+				s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("No market data for: %s", strings.Join(missing, ", ")))
+			}
+			if len(embeds) > 0 {
+				s.ChannelMessageSendEmbeds(m.ChannelID, embeds)
+			}
+		}
+	}
+}
+
+// sendLivePrices replies with the lowest sell and highest buy from the live API
+func (p *plugin) sendLivePrices(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, items []string) {
+	printer := message.NewPrinter(language.English)
+	var sb strings.Builder
+	for _, itemID := range items {
+		price, err := p.client.GetLatestPrice(ctx, itemID)
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error getting price: %s", err.Error()))
+			return
+		}
+		if len(items) > 1 {
+			sb.WriteString(fmt.Sprintf("**%s**\n", itemID))
+		}
+		sb.WriteString(printer.Sprintf(
+			"Lowest Sell: %dg (%d)\nHighest Buy: %dg (%d)\n",
+			price.LowestSellPrice,
+			price.LowestPriceVolume,
+			price.HighestBuyPrice,
+			price.HighestPriceVolume,
+		))
+	}
+	s.ChannelMessageSend(m.ChannelID, strings.TrimSpace(sb.String()))
+}
+
+// findMarketItem resolves an item name or ID to a tracked market item, falling back to a name search
+func (p *plugin) findMarketItem(ctx context.Context, name string) (*market.Item, error) {
+	if itemID, ok := p.client.GetItemID(name); ok {
+		item, err := p.marketDB.GetItem(ctx, itemID)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			return item, nil
+		}
+	}
+
+	items, err := p.marketDB.SearchItems(ctx, name, 1)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// buildPriceEmbed summarizes an item's current prices, 24h change, spread and 7-day range
+func (p *plugin) buildPriceEmbed(ctx context.Context, analytics *market.Analytics, item *market.Item) (*discordgo.MessageEmbed, error) {
+	summary, err := analytics.GetItemSummary(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil || summary.CurrentPrice == nil {
+		return nil, fmt.Errorf("no price data for item %d", item.ID)
+	}
+
+	spread, err := analytics.GetSpreadAnalysis(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	history, err := p.marketDB.GetPriceHistory(ctx, item.ID, now.Add(-7*24*time.Hour), now, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	printer := message.NewPrinter(language.English)
+	current := summary.CurrentPrice
+
+	change := "n/a"
+	color := 0x3498db // Blue color
+	if summary.Change24h != nil {
+		change = printer.Sprintf("%+dg (%+.1f%%)", summary.Change24h.Change, summary.Change24h.ChangePercent)
+		if summary.Change24h.Change >= 0 {
+			color = 0x2ecc71 // Green color
+		} else {
+			color = 0xe74c3c // Red color
+		}
+	}
+
+	spreadValue := printer.Sprintf("%dg (%.1f%%)", summary.Spread, summary.SpreadPercent)
+	if spread != nil {
+		spreadValue += printer.Sprintf("\n24h avg: %.0fg", spread.AvgSpread24h)
+	}
+
+	var prices []int
+	for _, h := range history {
+		if h.LowestSellPrice > 0 {
+			prices = append(prices, h.LowestSellPrice)
+		}
+	}
+	weekRange := "n/a"
+	description := "*No price history yet*"
+	if len(prices) > 0 {
+		low, high := prices[0], prices[0]
+		for _, price := range prices {
+			low = min(low, price)
+			high = max(high, price)
+		}
+		weekRange = printer.Sprintf("%dg – %dg", low, high)
+		description = fmt.Sprintf("`%s`", sparkline(prices, sparklineWidth))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       item.DisplayName,
+		URL:         p.marketItemURL(item.ID),
+		Description: description,
+		Color:       color,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Lowest Sell", Value: printer.Sprintf("%dg (%d)", current.LowestSellPrice, current.LowestPriceVolume), Inline: true},
+			{Name: "Highest Buy", Value: printer.Sprintf("%dg (%d)", current.HighestBuyPrice, current.HighestPriceVolume), Inline: true},
+			{Name: "24h Change", Value: change, Inline: true},
+			{Name: "Spread", Value: spreadValue, Inline: true},
+			{Name: "7d Range", Value: weekRange, Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Sparkline: lowest sell price over the last 7 days",
+		},
+	}, nil
+}
+
+// sparkline draws values as a string of block characters, resampled to at most width characters
+func sparkline(values []int, width int) string {
+	if len(values) > width {
+		sampled := make([]int, width)
+		for i := range sampled {
+			sampled[i] = values[i*(len(values)-1)/(width-1)]
+		}
+		values = sampled
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = min(low, v)
+		high = max(high, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		level := 0
+		if high > low {
+			level = (v - low) * (len(sparkBlocks) - 1) / (high - low)
+		}
+		sb.WriteRune(sparkBlocks[level])
+	}
+	return sb.String()
+}
+
+const (
+	// marketListLimit is the most items listed in a !market embed
+	marketListLimit = 10
+	// maxMoverHours is the longest window !market movers can compare over
+	maxMoverHours = 7 * 24
+)
+
+func (p *plugin) marketCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		parts := strings.Fields(m.Content)
+		if len(parts) == 0 || parts[0] != "!market" {
+			return
+		}
+
+		if !p.marketEnabled || p.marketDB == nil {
+			s.ChannelMessageSend(m.ChannelID, "Error: Market tracking is not enabled")
+			return
+		}
+
+		if len(parts) < 2 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!market movers [hours]`, `!market traded` or `!market category <name>`")
+			return
+		}
+
+		l.Info(
+			"Processing market command",
+			zap.Strings("args", parts[1:]),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		var embeds []*discordgo.MessageEmbed
+		var err error
+		switch strings.ToLower(parts[1]) {
+		case "movers":
+			embeds, err = p.marketMovers(ctx, parts[2:])
+		case "traded":
+			embeds, err = p.marketMostTraded(ctx)
+		case "category":
+			embeds, err = p.marketCategory(ctx, strings.Join(parts[2:], " "))
+		default:
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!market movers [hours]`, `!market traded` or `!market category <name>`")
+			return
+		}
+		if err != nil {
+			l.Error("Error building market overview", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: %s", err.Error()))
+			return
 		}
+
+		s.ChannelMessageSendEmbeds(m.ChannelID, embeds)
 	}
 }
+
+// marketMovers lists the biggest gainers and losers. The default 24h window comes from the
+// cached overview; other windows are computed on demand.
+func (p *plugin) marketMovers(ctx context.Context, args []string) ([]*discordgo.MessageEmbed, error) {
+	hours := 24
+	if len(args) > 0 {
+		h, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(args[0]), "h"))
+		if err != nil || h < 1 || h > maxMoverHours {
+			return nil, fmt.Errorf("hours must be between 1 and %d", maxMoverHours)
+		}
+		hours = h
+	}
+
+	var gainers, losers []market.PriceChange
+	updatedAt := time.Now().UTC()
+	if hours == 24 {
+		overview, err := market.NewAnalytics(p.marketDB).GetMarketOverview(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get market overview: %w", err)
+		}
+		gainers, losers, updatedAt = overview.TopGainers, overview.TopLosers, overview.LastUpdated
+	} else {
+		var err error
+		if gainers, err = p.marketDB.GetTopMoversOptimized(ctx, hours, marketListLimit, true); err != nil {
+			return nil, fmt.Errorf("failed to get top gainers: %w", err)
+		}
+		if losers, err = p.marketDB.GetTopMoversOptimized(ctx, hours, marketListLimit, false); err != nil {
+			return nil, fmt.Errorf("failed to get top losers: %w", err)
+		}
+	}
+
+	footer := &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Lowest sell price change over %dh", hours)}
+	return []*discordgo.MessageEmbed{
+		{
+			Title:       fmt.Sprintf("📈 Top Gainers (%dh)", hours),
+			Description: p.formatPriceChanges(gainers, true),
+			Color:       0x2ecc71, // Green color
+			Timestamp:   updatedAt.Format(time.RFC3339),
+		},
+		{
+			Title:       fmt.Sprintf("📉 Top Losers (%dh)", hours),
+			Description: p.formatPriceChanges(losers, true),
+			Color:       0xe74c3c, // Red color
+			Footer:      footer,
+			Timestamp:   updatedAt.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+// marketMostTraded lists the items with the most listed volume from the cached overview
+func (p *plugin) marketMostTraded(ctx context.Context) ([]*discordgo.MessageEmbed, error) {
+	overview, err := market.NewAnalytics(p.marketDB).GetMarketOverview(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market overview: %w", err)
+	}
+
+	return []*discordgo.MessageEmbed{{
+		Title:       "🔥 Most Traded",
+		Description: p.formatPriceChanges(overview.MostTraded, false),
+		Color:       0xf39c12, // Orange color
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%d of %d items active in the last hour", overview.ActiveItems, overview.TotalItems),
+		},
+		Timestamp: overview.LastUpdated.Format(time.RFC3339),
+	}}, nil
+}
+
+// marketCategory lists the most actively listed items in a category
+func (p *plugin) marketCategory(ctx context.Context, category string) ([]*discordgo.MessageEmbed, error) {
+	categories, err := p.marketDB.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	// Accept "raw fish" for "raw_fish"
+	category = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(category)), " ", "_")
+	found := false
+	for _, c := range categories {
+		if c == category {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown category; choose one of: %s", strings.Join(categories, ", "))
+	}
+
+	items, err := p.marketDB.GetItemsWithPricesByCategory(ctx, category, marketListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s items: %w", category, err)
+	}
+
+	printer := message.NewPrinter(language.English)
+	var sb strings.Builder
+	for i, item := range items {
+		sb.WriteString(printer.Sprintf(
+			"%d. [%s](%s) — sell %dg (%d) · buy %dg (%d)\n",
+			i+1,
+			item.DisplayName,
+			p.marketItemURL(item.ID),
+			item.LowestSellPrice,
+			item.LowestPriceVolume,
+			item.HighestBuyPrice,
+			item.HighestPriceVolume,
+		))
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("*No items with price data*")
+	}
+
+	return []*discordgo.MessageEmbed{{
+		Title:       fmt.Sprintf("🏷️ %s", idleclans.ItemDisplayName(category)),
+		URL:         fmt.Sprintf("%s/market", p.webBaseURL),
+		Description: sb.String(),
+		Color:       0x3498db, // Blue color
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Most actively listed items first",
+		},
+	}}, nil
+}
+
+// formatPriceChanges lists price changes with links to each item's market page
+func (p *plugin) formatPriceChanges(changes []market.PriceChange, showChange bool) string {
+	printer := message.NewPrinter(language.English)
+	var sb strings.Builder
+	for i, c := range changes {
+		if i >= marketListLimit {
+			break
+		}
+		sb.WriteString(fmt.Sprintf("%d. [%s](%s) — ", i+1, c.DisplayName, p.marketItemURL(c.ItemID)))
+		if showChange {
+			sb.WriteString(printer.Sprintf("%dg (%+.1f%%)\n", c.CurrentPrice, c.ChangePercent))
+		} else {
+			sb.WriteString(printer.Sprintf("%dg · %d listed\n", c.CurrentPrice, c.Volume))
+		}
+	}
+	if sb.Len() == 0 {
+		return "*No data yet*"
+	}
+	return sb.String()
+}
+
+// marketItemURL links to an item's page on the web market
+func (p *plugin) marketItemURL(itemID int) string {
+	return fmt.Sprintf("%s/market?item=%d", p.webBaseURL, itemID)
+}
@@ -0,0 +1,217 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+func (p *plugin) networthCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		if !strings.HasPrefix(m.Content, "!networth") {
+			return
+		}
+
+		if p.marketDB == nil || p.trackerDB == nil {
+			s.ChannelMessageSend(m.ChannelID, "Error: Market data unavailable")
+			return
+		}
+
+		parts := strings.Fields(strings.TrimPrefix(m.Content, "!networth"))
+		if len(parts) == 0 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!networth <player>` or `!networth clan [days]`")
+			return
+		}
+
+		l.Info(
+			"Processing networth command",
+			zap.Strings("args", parts),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		if strings.EqualFold(parts[0], "clan") {
+			p.handleClanNetWorth(ctx, s, m, parts[1:])
+			return
+		}
+
+		playerName := parts[0]
+		player, err := p.client.GetPlayer(ctx, playerName)
+		if err != nil {
+			l.Error("Error getting player profile", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, "Error getting player profile")
+			return
+		}
+
+		// Record an on-demand snapshot of clan players so the gear also counts towards the clan history
+		var registered []string
+		if p.questsHandler != nil {
+			if registered, err = p.questsHandler.db.GetAllRegisteredPlayerNames(ctx); err != nil {
+				l.Warn("Error getting registered player names", zap.Error(err))
+			}
+		}
+		if name, ok := tracker.OnDemandSnapshotName(player, playerName, registered, p.clanGuild); ok {
+			snapshot, err := tracker.SnapshotFromPlayer(name, player)
+			if err != nil {
+				l.Error("Error building player snapshot", zap.Error(err))
+			} else if err := p.trackerDB.InsertSnapshot(ctx, snapshot); err != nil {
+				l.Warn("Error saving player snapshot", zap.Error(err))
+			}
+		}
+
+		worth, err := tracker.ValueGear(ctx, p.marketDB, playerName, p.client.ResolveGear(player))
+		if err != nil {
+			l.Error("Error valuing gear", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, "Error valuing gear")
+			return
+		}
+
+		s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{buildNetWorthEmbed(worth)})
+	}
+}
+
+// buildNetWorthEmbed lists each equipped item with its market prices
+func buildNetWorthEmbed(worth *tracker.NetWorth) *discordgo.MessageEmbed {
+	printer := message.NewPrinter(language.English)
+
+	var sb strings.Builder
+	for _, item := range worth.Items {
+		slot := idleclans.SlotDisplayName(item.Slot)
+		switch {
+		case item.Empty:
+			continue
+		case !item.Known:
+			sb.WriteString(fmt.Sprintf("**%s**: ❓ %s\n", slot, item.Name))
+		case !item.Priced:
+			sb.WriteString(fmt.Sprintf("**%s**: %s — *no market price*\n", slot, item.Name))
+		default:
+			sb.WriteString(printer.Sprintf("**%s**: %s — %dg sell / %dg buy\n", slot, item.Name, item.SellPrice, item.BuyPrice))
+		}
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("No gear equipped.")
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Sell Value",
+			Value:  printer.Sprintf("%dg", worth.SellValue),
+			Inline: true,
+		},
+		{
+			Name:   "Buy Value",
+			Value:  printer.Sprintf("%dg", worth.BuyValue),
+			Inline: true,
+		},
+	}
+	if worth.Unpriced > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Unpriced",
+			Value:  fmt.Sprintf("%d item(s) not valued", worth.Unpriced),
+			Inline: true,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Net Worth: %s", worth.PlayerName),
+		Description: sb.String(),
+		Color:       0xf1c40f, // Gold color
+		Fields:      fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Equipped gear at the latest market prices",
+		},
+	}
+}
+
+func (p *plugin) handleClanNetWorth(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	days := 30
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed <= 0 || parsed > 365 {
+			s.ChannelMessageSend(m.ChannelID, "Days must be between 1 and 365")
+			return
+		}
+		days = parsed
+	}
+
+	names, err := p.questsHandler.db.GetAllRegisteredPlayerNames(ctx)
+	if err != nil {
+		l.Error("Error getting registered players", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error getting registered players")
+		return
+	}
+
+	history, err := p.trackerDB.GetClanGearValueHistory(ctx, p.marketDB, names, days)
+	if err != nil {
+		l.Error("Error getting clan gear value history", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error getting clan gear value")
+		return
+	}
+
+	// Skip leading days before any gear was recorded
+	for len(history) > 0 && history[0].Players == 0 {
+		history = history[1:]
+	}
+	if len(history) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No gear history recorded yet. Gear is captured with player snapshots or `!networth <player>`.")
+		return
+	}
+
+	printer := message.NewPrinter(language.English)
+	first := history[0]
+	last := history[len(history)-1]
+	change := last.SellValue - first.SellValue
+
+	sign := "+"
+	if change < 0 {
+		sign = ""
+	}
+
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{{
+		Title: "Clan Gear Value",
+		Color: 0xf1c40f, // Gold color
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Current (sell)",
+				Value:  printer.Sprintf("%dg", last.SellValue),
+				Inline: true,
+			},
+			{
+				Name:   "Current (buy)",
+				Value:  printer.Sprintf("%dg", last.BuyValue),
+				Inline: true,
+			},
+			{
+				Name:   fmt.Sprintf("Change since %s", first.Date.Format("Jan 2")),
+				Value:  printer.Sprintf("%s%dg", sign, change),
+				Inline: true,
+			},
+			{
+				Name:   "Players",
+				Value:  fmt.Sprintf("%d", last.Players),
+				Inline: true,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Gear from player snapshots, valued at the latest market prices",
+		},
+	}})
+}
@@ -1,8 +1,10 @@
 package idleclans
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"image/png"
 	"math"
 	"sort"
 	"strings"
@@ -11,6 +13,8 @@ import (
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"github.com/jirwin/idleclans/pkg/bot"
 	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/render"
+	"github.com/jirwin/idleclans/pkg/tracker"
 	"go.uber.org/zap"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -236,6 +240,21 @@ func (p *plugin) playerCmd(ctx context.Context) bot.MessageHandler {
 				})
 			}
 
+			// Equipment slots and PvM stats are only in the full profile
+			card := &render.PlayerCard{Name: playerName, Skills: player.Skills}
+			if fullPlayer, err := p.client.GetPlayer(ctx, playerName); err != nil {
+				l.Warn("Error getting full player profile for gear", zap.Error(err))
+			} else {
+				fields = append(fields, &discordgo.MessageEmbedField{
+					Name:   "Gear",
+					Value:  formatGear(p.client.ResolveGear(fullPlayer)),
+					Inline: false,
+				})
+				if snapshot, err := tracker.SnapshotFromPlayer(playerName, fullPlayer); err == nil {
+					card.PvmStats = snapshot.PvmStats
+				}
+			}
+
 			embed := &discordgo.MessageEmbed{
 				Title:       fmt.Sprintf("Player: %s", playerName),
 				Description: "Skill Levels",
@@ -243,7 +262,22 @@ func (p *plugin) playerCmd(ctx context.Context) bot.MessageHandler {
 				Fields:      fields,
 			}
 
-			s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{embed})
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, render.RenderPlayerCard(card)); err != nil {
+				l.Warn("Error rendering player card", zap.Error(err))
+				s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{embed})
+				return
+			}
+
+			embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://player.png"}
+			s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+				Embeds: []*discordgo.MessageEmbed{embed},
+				Files: []*discordgo.File{{
+					Name:        "player.png",
+					ContentType: "image/png",
+					Reader:      &buf,
+				}},
+			})
 		}
 	}
 }
@@ -258,6 +292,11 @@ func (p *plugin) pvmCmd(ctx context.Context) bot.MessageHandler {
 
 		if strings.HasPrefix(m.Content, "!pvm") {
 			playerName := strings.TrimSpace(strings.TrimPrefix(m.Content, "!pvm"))
+			if args := strings.Fields(playerName); len(args) > 0 && strings.EqualFold(args[0], "clan") {
+				p.handleClanPvM(ctx, s, m, args[1:])
+				return
+			}
+
 			l.Info(
 				"Processing pvm command",
 				zap.String("player", playerName),
@@ -303,3 +342,19 @@ PvM Stats for %s:
 		}
 	}
 }
+
+// formatGear lists each equipment slot and its item, flagging empty slots and unknown items
+func formatGear(gear []idleclans.GearSlot) string {
+	var sb strings.Builder
+	for _, g := range gear {
+		switch {
+		case g.Empty:
+			sb.WriteString(fmt.Sprintf("**%s**: *empty*\n", idleclans.SlotDisplayName(g.Slot)))
+		case !g.Known:
+			sb.WriteString(fmt.Sprintf("**%s**: ❓ %s\n", idleclans.SlotDisplayName(g.Slot), g.Name))
+		default:
+			sb.WriteString(fmt.Sprintf("**%s**: %s\n", idleclans.SlotDisplayName(g.Slot), g.Name))
+		}
+	}
+	return sb.String()
+}
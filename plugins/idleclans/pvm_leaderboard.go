@@ -0,0 +1,113 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// leaderboardPageSize is the number of players on each page of a leaderboard embed
+const leaderboardPageSize = 20
+
+// handleClanPvM ranks every registered player and alt by kills of a boss, or by total kills
+func (p *plugin) handleClanPvM(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	if p.tracker == nil {
+		s.ChannelMessageSend(m.ChannelID, "Error: Player tracker unavailable")
+		return
+	}
+
+	boss := ""
+	if len(args) > 0 {
+		var ok bool
+		boss, ok = tracker.ResolveBoss(strings.Join(args, ""))
+		if !ok {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Unknown boss %q", strings.Join(args, " ")))
+			return
+		}
+	}
+
+	l.Info(
+		"Processing clan pvm command",
+		zap.String("boss", boss),
+		zap.String("from", m.Author.Username),
+		zap.String("channel", m.ChannelID),
+	)
+
+	s.ChannelTyping(m.ChannelID)
+
+	leaderboard, err := p.tracker.BuildPvMLeaderboard(ctx, boss)
+	if err != nil {
+		l.Error("Error building pvm leaderboard", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error building PvM leaderboard")
+		return
+	}
+	if len(leaderboard.Entries) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No registered players found")
+		return
+	}
+
+	// One page per message keeps each message under Discord's embed size limit
+	for _, embed := range buildLeaderboardEmbeds(leaderboard) {
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	}
+}
+
+// buildLeaderboardEmbeds renders a leaderboard as fixed-width tables, one embed per page
+func buildLeaderboardEmbeds(leaderboard *tracker.PvMLeaderboard) []*discordgo.MessageEmbed {
+	printer := message.NewPrinter(language.English)
+
+	title := "🏆 Clan PvM Leaderboard — Total Kills"
+	if leaderboard.Boss != "" {
+		title = fmt.Sprintf("🏆 Clan PvM Leaderboard — %s", leaderboard.Boss)
+	}
+
+	nameWidth := len("Player")
+	for _, e := range leaderboard.Entries {
+		nameWidth = max(nameWidth, len(e.PlayerName))
+	}
+
+	pages := (len(leaderboard.Entries) + leaderboardPageSize - 1) / leaderboardPageSize
+	embeds := make([]*discordgo.MessageEmbed, 0, pages)
+	for page := 0; page < pages; page++ {
+		var sb strings.Builder
+		sb.WriteString("```\n")
+		sb.WriteString(fmt.Sprintf("%4s %-*s %9s %7s\n", "#", nameWidth, "Player", "Kills", "7d"))
+
+		start := page * leaderboardPageSize
+		end := min(start+leaderboardPageSize, len(leaderboard.Entries))
+		for i := start; i < end; i++ {
+			e := leaderboard.Entries[i]
+			weekly := "-"
+			if e.WeeklyKills != nil {
+				weekly = printer.Sprintf("+%d", *e.WeeklyKills)
+			}
+			sb.WriteString(printer.Sprintf("%3d. %-*s %9d %7s\n", i+1, nameWidth, e.PlayerName, e.Kills, weekly))
+		}
+		sb.WriteString("```")
+
+		footer := fmt.Sprintf("Page %d/%d · 7d gains from player snapshots", page+1, pages)
+		if page == pages-1 && len(leaderboard.Failed) > 0 {
+			footer += fmt.Sprintf(" · Couldn't fetch: %s", strings.Join(leaderboard.Failed, ", "))
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Description: sb.String(),
+			Color:       0xe74c3c, // Red color
+			Footer:      &discordgo.MessageEmbedFooter{Text: footer},
+		}
+		if page == 0 {
+			embed.Title = title
+		}
+		embeds = append(embeds, embed)
+	}
+	return embeds
+}
@@ -19,8 +19,20 @@ import (
 )
 
 type questsHandler struct {
-	db         *quests.DB
-	notifyFunc DataChangeNotifier
+	db            *quests.DB
+	notifyFunc    DataChangeNotifier
+	analyzer      ScreenshotAnalyzer
+	scans         pendingScans
+	parties       PartyCreator
+	lfg           lfgQueue
+	plannerConfig PlannerConfig
+}
+
+// PlannerConfig configures the party planner the quest commands use, matching the web server's
+type PlannerConfig struct {
+	FairnessWeight float64              // Weight for spreading key usage across holders (0 = fewest parties)
+	Objective      quests.PlanObjective // What the planner's optimizer minimizes
+	SearchBudget   time.Duration        // How long the planner's optimizer searches (0 = greedy plans only)
 }
 
 // notifyDataChange notifies connected clients of data changes
@@ -31,18 +43,44 @@ func (h *questsHandler) notifyDataChange(changeType string) {
 	// Note: if notifyFunc is nil, notifications won't be sent (web server not configured)
 }
 
-func newQuestsHandler() (*questsHandler, error) {
-	dbPath := "quests.db"
-	db, err := quests.NewDB(dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create quests database: %w", err)
+// newQuestsHandler creates the quests handler on the database connection shared with the web server
+func newQuestsHandler(db *quests.DB, plannerConfig PlannerConfig) *questsHandler {
+	return &questsHandler{db: db, plannerConfig: plannerConfig}
+}
+
+// shortPlanReason summarizes why a leftover's kills weren't planned
+func shortPlanReason(e quests.Explanation) string {
+	switch e.Code {
+	case quests.ReasonNotSelected:
+		return "not selected"
+	case quests.ReasonNotEnoughKeys:
+		return "party ran out of keys"
+	case quests.ReasonNoParty:
+		return "no party"
+	default:
+		return "unplanned"
 	}
+}
 
-	return &questsHandler{db: db}, nil
+// formatKeyAlternatives lists the first key holders outside the party for a task without keys
+func formatKeyAlternatives(e quests.Explanation) string {
+	if len(e.Alternatives) == 0 {
+		return ""
+	}
+	alternatives := e.Alternatives
+	if len(alternatives) > 2 {
+		alternatives = alternatives[:2]
+	}
+	return " — try " + strings.Join(alternatives, ", ")
 }
 
-func (h *questsHandler) close() error {
-	return h.db.Close()
+// newPlanner creates a planner with the configured fairness, objective and search budget
+func (h *questsHandler) newPlanner() *quests.Planner {
+	planner := quests.NewPlanner(h.db)
+	planner.SetFairnessWeight(h.plannerConfig.FairnessWeight)
+	planner.SetObjective(h.plannerConfig.Objective)
+	planner.SetSearchBudget(h.plannerConfig.SearchBudget)
+	return planner
 }
 
 func (p *plugin) questsCmd(ctx context.Context) bot.MessageHandler {
@@ -117,6 +155,12 @@ func (p *plugin) questsCmd(ctx context.Context) bot.MessageHandler {
 			p.questsHandler.handlePlan(ctx, s, m, parts[1:])
 		case "alt", "alts":
 			p.questsHandler.handleAlt(ctx, s, m, parts[1:])
+		case "sync":
+			p.questsHandler.handleSync(ctx, s, m, parts[1:])
+		case "history":
+			p.questsHandler.handleHistory(ctx, s, m, parts[1:])
+		case "scan":
+			p.questsHandler.handleScan(ctx, s, m, parts[1:])
 		default:
 			// Additional input provided but not a known command - assume it's a quest update command
 			p.questsHandler.handleUpdate(ctx, s, m, parts)
@@ -192,7 +236,7 @@ func (h *questsHandler) handleHelp(ctx context.Context, s *discordgo.Session, m
 			},
 			{
 				Name:   "Keys",
-				Value:  "`!quests keys` - Show who has which keys (global)\n`!quests keys <player>` - View keys for a player\n`!quests keys <player> <key> <count> ...` - Set keys for a player\nExample: `!quests keys MyAlt mountain 50 stone 30`",
+				Value:  "`!quests keys` - Show who has which keys (global)\n`!quests keys <player>` - View keys for a player\n`!quests keys <player> <key> <count> ...` - Set keys for a player\nExample: `!quests keys MyAlt mountain 50 stone 30`\n`!keys ledger [player]` - Net key contributions, or a player's recent transfers and party key usage\n`!keys give <player> <key> <count>` - Give keys to another player\n`!keys forecast [days]` - Keys the clan will be short by at the weekly reset",
 				Inline: false,
 			},
 			{
@@ -215,6 +259,31 @@ func (h *questsHandler) handleHelp(ctx context.Context, s *discordgo.Session, m
 				Value:  "`!quests ping` - Ping players who have matching quests with you",
 				Inline: false,
 			},
+			{
+				Name:   "Looking for Group",
+				Value:  "`!lfg <boss> [player]` - Queue for a boss; once enough players with the quest and a key holder are queued, a group is formed, pinged and given a party link\n`!lfg` - Show the queues • `!lfg leave [boss]` - Leave\nQueue entries expire after 30 minutes.",
+				Inline: false,
+			},
+			{
+				Name:   "Scheduled Parties",
+				Value:  "`!party schedule <when> <player>[, <player>...] [| title]` - Invite players to a party at a later time (`2h`, `19:30` UTC or `2026-05-01 19:30` UTC)\nInvitees RSVP with buttons, are reminded before the start and a party session is created with those who accepted.\n`!party list` - Upcoming parties • `!party cancel <id>` - Cancel",
+				Inline: false,
+			},
+			{
+				Name:   "Quest Sync",
+				Value:  "`!quests sync [week|date]` - Show quests whose recorded kills disagree with the IdleClans kill counts\nProgress is synced automatically from the API for bosses it tracks.",
+				Inline: false,
+			},
+			{
+				Name:   "Screenshot Scan",
+				Value:  "`!quests scan [keys] [player_name]` - Attach a quest tracker (or key inventory) screenshot to detect kills (or key counts)\nThe changes are shown first and only saved when you press Apply.",
+				Inline: false,
+			},
+			{
+				Name:   "Quest History",
+				Value:  "`!quests history [player] [weeks]` - Completion rate per week, bosses most often left incomplete, average kills and completion streaks\nExample: `!quests history` or `!quests history MyAlt 12`",
+				Inline: false,
+			},
 			{
 				Name:   "Command Aliases",
 				Value:  "You can use `!quests`, `!quest`, or `!q` for all commands",
@@ -503,6 +572,18 @@ func (h *questsHandler) handleKeys(ctx context.Context, s *discordgo.Session, m
 		return
 	}
 
+	switch strings.ToLower(args[0]) {
+	case "ledger":
+		h.handleKeyLedger(ctx, s, m, args[1:])
+		return
+	case "give":
+		h.handleKeyGive(ctx, s, m, args[1:])
+		return
+	case "forecast":
+		h.handleKeyForecast(ctx, s, m, args[1:])
+		return
+	}
+
 	// Check if first arg is a player name
 	playerName := args[0]
 
@@ -1216,7 +1297,7 @@ func (h *questsHandler) handlePlan(ctx context.Context, s *discordgo.Session, m
 		weekNumber, year = getCurrentWeek()
 	}
 
-	planner := quests.NewPlanner(h.db)
+	planner := h.newPlanner()
 	plan, err := planner.GeneratePlan(ctx, weekNumber, year)
 	if err != nil {
 		l.Error("Failed to generate plan", zap.Error(err))
@@ -1241,7 +1322,12 @@ func (h *questsHandler) handlePlan(ctx context.Context, s *discordgo.Session, m
 		var value strings.Builder
 		for _, task := range party.Tasks {
 			if task.NoKeys {
-				value.WriteString(fmt.Sprintf("**%s**: %d (⚠️ No keys)\n",
+				value.WriteString(fmt.Sprintf("**%s**: %d (⚠️ No keys%s)\n",
+					formatBossNameWithEmoji(task.BossName),
+					task.Kills,
+					formatKeyAlternatives(task.Explanation)))
+			} else if task.KeyHolder == "" {
+				value.WriteString(fmt.Sprintf("**%s**: %d\n",
 					formatBossNameWithEmoji(task.BossName),
 					task.Kills))
 			} else {
@@ -1251,6 +1337,9 @@ func (h *questsHandler) handlePlan(ctx context.Context, s *discordgo.Session, m
 					task.KeyHolder))
 			}
 		}
+		for _, v := range party.Violations {
+			value.WriteString(fmt.Sprintf("⚠️ %s allows %s\n", formatBossNameWithEmoji(v.BossName), v.Limit))
+		}
 
 		playersList := strings.Join(party.Players, ", ")
 		fields = append(fields, &discordgo.MessageEmbedField{
@@ -1267,7 +1356,7 @@ func (h *questsHandler) handlePlan(ctx context.Context, s *discordgo.Session, m
 			var needs []string
 			for boss, n := range p.Needs {
 				if n > 0 {
-					needs = append(needs, fmt.Sprintf("%s (%d)", formatBossNameWithEmoji(boss), n))
+					needs = append(needs, fmt.Sprintf("%s (%d, %s)", formatBossNameWithEmoji(boss), n, shortPlanReason(p.Unmet[boss])))
 				}
 			}
 			if len(needs) > 0 {
@@ -1377,57 +1466,21 @@ func (h *questsHandler) handleBossPing(ctx context.Context, s *discordgo.Session
 
 // formatBossNameWithEmoji formats a boss name with its corresponding key color emoji
 func formatBossNameWithEmoji(bossName string) string {
-	// Map key colors to emoji/symbols for visual distinction
-	colorEmoji := map[string]string{
-		"brown":        "🟤",
-		"gray":         "⚫",
-		"blue":         "🔵",
-		"gold":         "⭐",
-		"red":          "🔴",
-		"green":        "🟢",
-		"otherworldly": "💫",
-		"ancient":      "🏛️",
-		"book":         "📖",
-	}
-
-	keyType, ok := quests.GetKeyForBoss(bossName)
+	boss, ok := quests.GetBoss(bossName)
 	if !ok {
 		return cases.Title(language.English).String(bossName)
 	}
-
-	keyColor, hasColor := quests.KeyToColor[keyType]
-	fieldName := cases.Title(language.English).String(bossName)
-	if hasColor {
-		emoji := colorEmoji[strings.ToLower(keyColor)]
-		if emoji != "" {
-			fieldName = fmt.Sprintf("%s %s", emoji, fieldName)
-		}
+	if boss.Emoji == "" {
+		return boss.DisplayName
 	}
-
-	return fieldName
+	return fmt.Sprintf("%s %s", boss.Emoji, boss.DisplayName)
 }
 
 // formatKeyTypeWithEmoji formats a key type with its corresponding color emoji
 func formatKeyTypeWithEmoji(keyType string) string {
-	colorEmoji := map[string]string{
-		"brown":        "🟤",
-		"gray":         "⚫",
-		"blue":         "🔵",
-		"gold":         "⭐",
-		"red":          "🔴",
-		"green":        "🟢",
-		"otherworldly": "💫",
-		"ancient":      "🏛️",
-		"book":         "📖",
-	}
-
-	keyColor, hasColor := quests.KeyToColor[keyType]
 	displayKey := cases.Title(language.English).String(keyType)
-	if hasColor {
-		emoji := colorEmoji[strings.ToLower(keyColor)]
-		if emoji != "" {
-			displayKey = fmt.Sprintf("%s %s", emoji, displayKey)
-		}
+	if emoji := quests.GetKeyEmoji(keyType); emoji != "" {
+		displayKey = fmt.Sprintf("%s %s", emoji, displayKey)
 	}
 	return displayKey
 }
@@ -0,0 +1,131 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+// questHistoryStreaksShown is how many players are listed in the clan streak field
+const questHistoryStreaksShown = 10
+
+// handleHistory shows quest completion statistics for the clan or a player.
+// Usage: !quests history [player] [weeks]
+func (h *questsHandler) handleHistory(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	weeks := quests.DefaultHistoryWeeks
+	if len(args) > 0 {
+		if n, err := strconv.Atoi(args[len(args)-1]); err == nil {
+			if n < 1 || n > quests.MaxHistoryWeeks {
+				s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Weeks must be between 1 and %d", quests.MaxHistoryWeeks))
+				return
+			}
+			weeks = n
+			args = args[:len(args)-1]
+		}
+	}
+	playerName := strings.Join(args, " ")
+
+	history, err := h.db.GetQuestHistory(ctx, playerName, weeks)
+	if err != nil {
+		l.Error("Failed to get quest history", zap.Error(err), zap.String("player", playerName))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error getting quest history: %s", err.Error()))
+		return
+	}
+	if len(history.Bosses) == 0 {
+		who := "the clan"
+		if playerName != "" {
+			who = playerName
+		}
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("No quests recorded for %s in the last %d weeks", who, weeks))
+		return
+	}
+
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{buildQuestHistoryEmbed(history, weeks)})
+}
+
+func buildQuestHistoryEmbed(history *quests.QuestHistory, weeks int) *discordgo.MessageEmbed {
+	who := "Clan"
+	if history.PlayerName != "" {
+		who = history.PlayerName
+	}
+
+	var sb strings.Builder
+	sb.WriteString("**Completion by week**\n")
+	totalQuests, totalCompleted := 0, 0
+	for _, wc := range history.Weeks {
+		totalQuests += wc.Quests
+		totalCompleted += wc.Completed
+		if wc.Quests == 0 {
+			sb.WriteString(fmt.Sprintf("`W%02d %d` —\n", wc.Week, wc.Year))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("`W%02d %d` %s %d/%d (%.0f%%)\n",
+			wc.Week, wc.Year, completionBar(wc.Rate, 10), wc.Completed, wc.Quests, wc.Rate*100))
+	}
+
+	var incomplete, averages strings.Builder
+	for _, boss := range history.Bosses {
+		name := formatBossNameWithEmoji(boss.Boss)
+		if boss.Incomplete > 0 {
+			incomplete.WriteString(fmt.Sprintf("%s — %d of %d left incomplete\n", name, boss.Incomplete, boss.Quests))
+		}
+		averages.WriteString(fmt.Sprintf("%s — %.1f / %.1f kills\n", name, boss.AvgKills, boss.AvgRequired))
+	}
+	if incomplete.Len() == 0 {
+		incomplete.WriteString("Every quest was completed 🎉")
+	}
+
+	var streaks strings.Builder
+	for i, streak := range history.Streaks {
+		if i >= questHistoryStreaksShown {
+			streaks.WriteString(fmt.Sprintf("*...and %d more*\n", len(history.Streaks)-questHistoryStreaksShown))
+			break
+		}
+		streaks.WriteString(fmt.Sprintf("**%s** — %d weeks (best %d)\n", streak.PlayerName, streak.Current, streak.Best))
+	}
+
+	rate := 0.0
+	if totalQuests > 0 {
+		rate = float64(totalCompleted) / float64(totalQuests) * 100
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Quest History - %s (last %d weeks)", who, weeks),
+		Description: sb.String(),
+		Color:       0x9b59b6, // Purple color
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Most often incomplete",
+				Value:  incomplete.String(),
+				Inline: false,
+			},
+			{
+				Name:   "Average kills per quest",
+				Value:  averages.String(),
+				Inline: false,
+			},
+			{
+				Name:   "🔥 Completion streaks",
+				Value:  streaks.String(),
+				Inline: false,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%d of %d quests completed (%.0f%%) • Streaks count consecutive fully completed weeks", totalCompleted, totalQuests, rate),
+		},
+	}
+}
+
+// completionBar renders a completion rate as a bar of width blocks
+func completionBar(rate float64, width int) string {
+	filled := int(rate*float64(width) + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
@@ -0,0 +1,413 @@
+package idleclans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxScanImageSize matches the web upload limit
+	maxScanImageSize = 2 << 20
+	// pendingScanTTL is how long a scan can be confirmed before it's discarded
+	pendingScanTTL = 15 * time.Minute
+
+	scanKindQuests = "quests"
+	scanKindKeys   = "keys"
+
+	scanConfirmPrefix = "quests_scan_confirm:"
+	scanCancelPrefix  = "quests_scan_cancel:"
+)
+
+// ScreenshotAnalyzer extracts quest kills (boss -> kills) and key counts (key type -> count)
+// from game screenshots. It is provided by the web server, which owns the OpenAI client.
+type ScreenshotAnalyzer interface {
+	AnalyzeQuestScreenshot(imageData []byte, imageType string) (map[string]int, error)
+	AnalyzeKeyScreenshot(imageData []byte, imageType string) (map[string]int, error)
+}
+
+// pendingScan is a screenshot analysis waiting for its author to confirm it
+type pendingScan struct {
+	userID     string
+	playerName string
+	kind       string
+	values     map[string]int
+	createdAt  time.Time
+}
+
+// pendingScans holds unconfirmed scans, keyed by the ID of the message that requested them
+type pendingScans struct {
+	mu    sync.Mutex
+	scans map[string]*pendingScan
+}
+
+func (p *pendingScans) add(id string, scan *pendingScan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.scans == nil {
+		p.scans = make(map[string]*pendingScan)
+	}
+	for key, existing := range p.scans {
+		if time.Since(existing.createdAt) > pendingScanTTL {
+			delete(p.scans, key)
+		}
+	}
+	p.scans[id] = scan
+}
+
+// take removes and returns a scan if it exists and hasn't expired
+func (p *pendingScans) take(id string) *pendingScan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	scan, ok := p.scans[id]
+	if !ok {
+		return nil
+	}
+	delete(p.scans, id)
+	if time.Since(scan.createdAt) > pendingScanTTL {
+		return nil
+	}
+	return scan
+}
+
+// peek returns a scan without removing it
+func (p *pendingScans) peek(id string) *pendingScan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.scans[id]
+}
+
+// handleScan analyzes an attached quest or key screenshot and shows the changes it would make.
+// Usage: !quests scan [keys] [player_name]
+func (h *questsHandler) handleScan(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	if h.analyzer == nil {
+		s.ChannelMessageSend(m.ChannelID, "Error: Image analysis is not configured")
+		return
+	}
+
+	kind := scanKindQuests
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case scanKindKeys, "key":
+			kind = scanKindKeys
+			args = args[1:]
+		case scanKindQuests, "quest":
+			args = args[1:]
+		}
+	}
+
+	if len(m.Attachments) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!quests scan [keys] [player_name]` with a screenshot attached")
+		return
+	}
+	attachment := m.Attachments[0]
+	if attachment.Size > maxScanImageSize {
+		s.ChannelMessageSend(m.ChannelID, "Error: Image too large. Maximum size is 2MB.")
+		return
+	}
+	imageType := attachment.ContentType
+	if imageType == "" {
+		imageType = "image/png" // Default to PNG
+	}
+	if !strings.HasPrefix(imageType, "image/") {
+		s.ChannelMessageSend(m.ChannelID, "Error: The attachment must be an image")
+		return
+	}
+
+	var playerName string
+	if len(args) > 0 {
+		playerName = strings.Join(args, " ")
+	} else {
+		var err error
+		playerName, err = h.db.GetPlayerName(ctx, m.Author.ID)
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, "No default player name registered. Use `!quests register <player_name>` first, or provide player name: `!quests scan [keys] <player_name>`")
+			return
+		}
+	}
+
+	s.ChannelTyping(m.ChannelID)
+
+	imageData, err := downloadAttachment(ctx, attachment.URL)
+	if err != nil {
+		l.Error("Failed to download screenshot", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error downloading the screenshot")
+		return
+	}
+
+	var detected map[string]int
+	if kind == scanKindKeys {
+		detected, err = h.analyzer.AnalyzeKeyScreenshot(imageData, imageType)
+	} else {
+		detected, err = h.analyzer.AnalyzeQuestScreenshot(imageData, imageType)
+	}
+	if err != nil {
+		l.Error("Failed to analyze screenshot", zap.Error(err), zap.String("kind", kind))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error analyzing screenshot: %s", err.Error()))
+		return
+	}
+	if len(detected) == 0 {
+		what := "bosses"
+		if kind == scanKindKeys {
+			what = "keys"
+		}
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("No %s were detected in the screenshot", what))
+		return
+	}
+
+	current, err := h.currentScanValues(ctx, kind, playerName)
+	if err != nil {
+		l.Error("Failed to get current values for scan", zap.Error(err), zap.String("player", playerName))
+		s.ChannelMessageSend(m.ChannelID, "Error getting current values")
+		return
+	}
+
+	h.scans.add(m.ID, &pendingScan{
+		userID:     m.Author.ID,
+		playerName: playerName,
+		kind:       kind,
+		values:     detected,
+		createdAt:  time.Now(),
+	})
+
+	_, err = s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{buildScanDiffEmbed(kind, playerName, current, detected)},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "Apply",
+						Style:    discordgo.SuccessButton,
+						CustomID: scanConfirmPrefix + m.ID,
+					},
+					discordgo.Button{
+						Label:    "Cancel",
+						Style:    discordgo.SecondaryButton,
+						CustomID: scanCancelPrefix + m.ID,
+					},
+				},
+			},
+		},
+		Reference: m.Reference(),
+	})
+	if err != nil {
+		l.Error("Failed to send scan results", zap.Error(err))
+	}
+}
+
+// currentScanValues returns the player's stored quests for this week or key counts
+func (h *questsHandler) currentScanValues(ctx context.Context, kind, playerName string) (map[string]int, error) {
+	if kind == scanKindKeys {
+		return h.db.GetPlayerKeys(ctx, playerName)
+	}
+
+	weekNumber, year := getCurrentWeek()
+	playerQuests, err := h.db.GetPlayerQuests(ctx, playerName, weekNumber, year)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]int, len(playerQuests))
+	for _, q := range playerQuests {
+		values[q.BossName] = q.RequiredKills
+	}
+	return values, nil
+}
+
+// buildScanDiffEmbed lists each detected value against what is currently stored
+func buildScanDiffEmbed(kind, playerName string, current, detected map[string]int) *discordgo.MessageEmbed {
+	names := make([]string, 0, len(detected))
+	for name := range detected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	changes := 0
+	for _, name := range names {
+		label := formatBossNameWithEmoji(name)
+		if kind == scanKindKeys {
+			label = formatKeyTypeWithEmoji(name)
+		}
+
+		value := detected[name]
+		old, ok := current[name]
+		switch {
+		case !ok:
+			sb.WriteString(fmt.Sprintf("➕ %s: **%d** (new)\n", label, value))
+			changes++
+		case old != value:
+			sb.WriteString(fmt.Sprintf("✏️ %s: %d → **%d**\n", label, old, value))
+			changes++
+		default:
+			sb.WriteString(fmt.Sprintf("▫️ %s: %d (unchanged)\n", label, value))
+		}
+	}
+
+	title := fmt.Sprintf("Quest Scan - %s", playerName)
+	footer := "Kills remaining per boss for this week"
+	if kind == scanKindKeys {
+		title = fmt.Sprintf("Key Scan - %s", playerName)
+		footer = "Keys not in the screenshot are left unchanged"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: sb.String(),
+		Color:       0x3498db, // Blue color
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%d change(s) • %s • Expires in %d minutes", changes, footer, int(pendingScanTTL.Minutes())),
+		},
+	}
+}
+
+// scanInteraction applies or discards a scan when its author presses one of its buttons
+func (p *plugin) scanInteraction(ctx context.Context) bot.InteractionHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if p.questsHandler == nil || i.Type != discordgo.InteractionMessageComponent {
+			return
+		}
+
+		customID := i.MessageComponentData().CustomID
+		var scanID string
+		confirm := false
+		switch {
+		case strings.HasPrefix(customID, scanConfirmPrefix):
+			scanID, confirm = strings.TrimPrefix(customID, scanConfirmPrefix), true
+		case strings.HasPrefix(customID, scanCancelPrefix):
+			scanID = strings.TrimPrefix(customID, scanCancelPrefix)
+		default:
+			return
+		}
+
+		userID := ""
+		if i.Member != nil && i.Member.User != nil {
+			userID = i.Member.User.ID
+		} else if i.User != nil {
+			userID = i.User.ID
+		}
+
+		h := p.questsHandler
+		if scan := h.scans.peek(scanID); scan != nil && scan.userID != userID {
+			respondEphemeral(s, i, "Only the person who requested this scan can apply it")
+			return
+		}
+
+		scan := h.scans.take(scanID)
+		if scan == nil {
+			updateScanMessage(s, i, "⌛ This scan has expired. Run `!quests scan` again.")
+			return
+		}
+		if !confirm {
+			updateScanMessage(s, i, "❌ Scan discarded, nothing was changed")
+			return
+		}
+
+		updates, err := h.applyScan(ctx, scan)
+		if err != nil {
+			l.Error("Failed to apply scan", zap.Error(err), zap.String("player", scan.playerName), zap.String("kind", scan.kind))
+		}
+		message := fmt.Sprintf("✅ Updated %d quest(s) for **%s**", updates, scan.playerName)
+		if scan.kind == scanKindKeys {
+			message = fmt.Sprintf("✅ Updated %d key count(s) for **%s**", updates, scan.playerName)
+		}
+		if err != nil {
+			message += fmt.Sprintf(" (some updates failed: %s)", err.Error())
+		}
+		updateScanMessage(s, i, message)
+	}
+}
+
+// applyScan writes a confirmed scan to weekly_quests or player_keys
+func (h *questsHandler) applyScan(ctx context.Context, scan *pendingScan) (int, error) {
+	weekNumber, year := getCurrentWeek()
+
+	var errs []error
+	updates := 0
+	for name, value := range scan.values {
+		var err error
+		if scan.kind == scanKindKeys {
+			err = h.db.UpsertPlayerKeys(ctx, scan.playerName, name, value)
+		} else {
+			err = h.db.UpsertQuest(ctx, scan.userID, scan.playerName, weekNumber, year, name, value)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		updates++
+	}
+
+	if updates > 0 {
+		if scan.kind == scanKindKeys {
+			h.notifyDataChange("keys")
+		} else {
+			h.notifyDataChange("quest")
+		}
+	}
+	return updates, errors.Join(errs...)
+}
+
+// updateScanMessage replaces the scan's buttons with a status line, keeping the diff embed
+func updateScanMessage(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Embeds:     i.Message.Embeds,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// downloadAttachment fetches a Discord attachment, failing if it's larger than maxScanImageSize bytes
+func downloadAttachment(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxScanImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxScanImageSize {
+		return nil, fmt.Errorf("attachment is larger than %d bytes", maxScanImageSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,42 @@
+package idleclans
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestDownloadAttachment(t *testing.T) {
+	tests := []struct {
+		size    int
+		wantErr bool
+	}{
+		{size: 1024},
+		{size: maxScanImageSize},
+		{size: maxScanImageSize + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.size), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(make([]byte, tt.size))
+			}))
+			defer server.Close()
+
+			data, err := downloadAttachment(context.Background(), server.URL)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("downloadAttachment() returned %d bytes, want an error", len(data))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("downloadAttachment() returned error: %v", err)
+			}
+			if len(data) != tt.size {
+				t.Errorf("downloadAttachment() returned %d bytes, want %d", len(data), tt.size)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleSync lists quests where manually entered progress disagrees with the API kill counts
+func (h *questsHandler) handleSync(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	var weekNumber, year int
+	var err error
+
+	if len(args) > 0 {
+		weekNumber, year, err = parseWeekOrDate(args[0])
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Invalid week/date format: %s. Use ISO week number (1-53) or date (YYYY-MM-DD)", args[0]))
+			return
+		}
+	} else {
+		weekNumber, year = getCurrentWeek()
+	}
+
+	conflicts, err := h.db.GetQuestSyncConflicts(ctx, weekNumber, year)
+	if err != nil {
+		l.Error("Failed to get quest sync conflicts", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error getting quest sync status: %s", err.Error()))
+		return
+	}
+
+	if len(conflicts) == 0 {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✅ All synced quests match the IdleClans kill counts for week %d of %d", weekNumber, year))
+		return
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(conflicts))
+	for _, c := range conflicts {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%s - %s", c.PlayerName, formatBossNameWithEmoji(c.BossName)),
+			Value:  fmt.Sprintf("Recorded: **%d**/%d • API: **%d** (synced %s UTC)", c.CurrentKills, c.RequiredKills, c.APIKills, c.SyncedAt.UTC().Format("Jan 2 15:04")),
+			Inline: false,
+		})
+	}
+
+	// Discord limit is 25 fields per embed
+	var embeds []*discordgo.MessageEmbed
+	chunkSize := 25
+	for i := 0; i < len(fields); i += chunkSize {
+		end := i + chunkSize
+		if end > len(fields) {
+			end = len(fields)
+		}
+
+		title := fmt.Sprintf("Quest Sync Disagreements (Week %d)", weekNumber)
+		if i > 0 {
+			title += fmt.Sprintf(" - Part %d", i/chunkSize+1)
+		}
+
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			Title:       title,
+			Description: "Recorded kills are higher than the kills the IdleClans API has seen since the week started. This usually means progress was entered by hand or kills happened before tracking began.",
+			Color:       0xe67e22, // Orange color
+			Fields:      fields[i:end],
+		})
+	}
+
+	s.ChannelMessageSendEmbeds(m.ChannelID, embeds)
+}
@@ -0,0 +1,343 @@
+package idleclans
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/quests"
+	"go.uber.org/zap"
+)
+
+const partyUsage = "Usage: `!party schedule <when> <player>[, <player>...] [| title]`, `!party list` or `!party cancel <id>`\n" +
+	"`<when>` is a delay such as `2h` or `1d`, a UTC time such as `19:30`, or a UTC date and time such as `2026-05-01 19:30`"
+
+var clockTimePattern = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+
+func (p *plugin) partyCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if p.questsHandler == nil {
+			return
+		}
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		parts := strings.Fields(m.Content)
+		if len(parts) == 0 || parts[0] != "!party" {
+			return
+		}
+
+		l.Info(
+			"Processing party command",
+			zap.Strings("args", parts[1:]),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		h := p.questsHandler
+		if len(parts) == 1 {
+			h.handlePartyList(ctx, s, m)
+			return
+		}
+
+		switch strings.ToLower(parts[1]) {
+		case "schedule":
+			h.handlePartySchedule(ctx, s, m, parts[2:])
+		case "list":
+			h.handlePartyList(ctx, s, m)
+		case "cancel":
+			h.handlePartyCancel(ctx, s, m, parts[2:])
+		default:
+			s.ChannelMessageSend(m.ChannelID, partyUsage)
+		}
+	}
+}
+
+// handlePartySchedule schedules a party and posts the invitation with RSVP buttons.
+// Usage: !party schedule <when> <player>[, <player>...] [| title]
+func (h *questsHandler) handlePartySchedule(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, partyUsage)
+		return
+	}
+
+	scheduledAt, rest, err := parseScheduleTime(args, time.Now().UTC())
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: %s\n%s", err.Error(), partyUsage))
+		return
+	}
+
+	playerList, title, _ := strings.Cut(strings.Join(rest, " "), "|")
+	var players []string
+	seen := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(playerList, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if key := strings.ToLower(field); !seen[key] {
+			seen[key] = true
+			players = append(players, field)
+		}
+	}
+	if len(players) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "Error: Invite at least one player\n"+partyUsage)
+		return
+	}
+
+	sp := &quests.ScheduledParty{
+		Title:       strings.TrimSpace(title),
+		ScheduledAt: scheduledAt,
+		ChannelID:   m.ChannelID,
+		CreatedBy:   m.Author.ID,
+	}
+	if err := h.db.CreateScheduledParty(ctx, sp, players); err != nil {
+		l.Error("Failed to schedule party", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error scheduling party: %s", err.Error()))
+		return
+	}
+
+	s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Content:    fmt.Sprintf("%s you're invited to a party <t:%d:R>!", h.playerMentions(ctx, players), sp.ScheduledAt.Unix()),
+		Embeds:     []*discordgo.MessageEmbed{buildScheduledPartyEmbed(sp, "📅 Party Scheduled")},
+		Components: rsvpComponents(sp.ID),
+	})
+	h.notifyDataChange("scheduled_party")
+}
+
+// handlePartyList shows the upcoming scheduled parties
+func (h *questsHandler) handlePartyList(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate) {
+	l := ctxzap.Extract(ctx)
+
+	parties, err := h.db.GetUpcomingScheduledParties(ctx, time.Time{})
+	if err != nil {
+		l.Error("Failed to get scheduled parties", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error getting scheduled parties: %s", err.Error()))
+		return
+	}
+	if len(parties) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No parties scheduled. "+partyUsage)
+		return
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for i := range parties {
+		sp := &parties[i]
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("%s (`%s`)", scheduledPartyTitle(sp), sp.ID),
+			Value: fmt.Sprintf("<t:%d:F> (<t:%d:R>)\n✅ %d accepted • ❔ %d pending • ❌ %d declined",
+				sp.ScheduledAt.Unix(), sp.ScheduledAt.Unix(),
+				len(sp.PlayersWithStatus(quests.RSVPAccepted)),
+				len(sp.PlayersWithStatus(quests.RSVPPending)),
+				len(sp.PlayersWithStatus(quests.RSVPDeclined))),
+			Inline: false,
+		})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:  "Scheduled Parties",
+		Color:  0x3498db, // Blue color
+		Fields: fields,
+	}
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{embed})
+}
+
+// handlePartyCancel cancels a scheduled party. Only the person who scheduled it or an officer can.
+// Usage: !party cancel <id>
+func (h *questsHandler) handlePartyCancel(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	if len(args) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!party cancel <id>`")
+		return
+	}
+
+	sp, err := h.db.GetScheduledParty(ctx, args[0])
+	if err != nil {
+		l.Error("Failed to get scheduled party", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error cancelling party")
+		return
+	}
+	if sp == nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Scheduled party `%s` not found", args[0]))
+		return
+	}
+	if sp.CreatedBy != m.Author.ID && !isOfficer(s, m) {
+		s.ChannelMessageSend(m.ChannelID, "Only the person who scheduled this party or an officer can cancel it")
+		return
+	}
+
+	err = h.db.CancelScheduledParty(ctx, sp.ID)
+	if err == sql.ErrNoRows {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("**%s** has already started or been cancelled", scheduledPartyTitle(sp)))
+		return
+	}
+	if err != nil {
+		l.Error("Failed to cancel scheduled party", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error cancelling party")
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%s **%s** has been cancelled", h.playerMentions(ctx, sp.PlayersWithStatus(quests.RSVPAccepted)), scheduledPartyTitle(sp)))
+	h.notifyDataChange("scheduled_party")
+}
+
+// rsvpInteraction handles the Accept and Decline buttons on scheduled party messages
+func (p *plugin) rsvpInteraction(ctx context.Context) bot.InteractionHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if p.questsHandler == nil || i.Type != discordgo.InteractionMessageComponent {
+			return
+		}
+
+		id, status, ok := quests.ParseRSVPButtonID(i.MessageComponentData().CustomID)
+		if !ok {
+			return
+		}
+
+		userID := ""
+		if i.Member != nil && i.Member.User != nil {
+			userID = i.Member.User.ID
+		} else if i.User != nil {
+			userID = i.User.ID
+		}
+
+		h := p.questsHandler
+		updated, err := h.db.SetScheduledPartyRSVP(ctx, id, userID, status)
+		if err != nil {
+			l.Error("Failed to record RSVP", zap.Error(err), zap.String("id", id))
+			respondEphemeral(s, i, fmt.Sprintf("Error recording your response: %s", err.Error()))
+			return
+		}
+		if len(updated) == 0 {
+			respondEphemeral(s, i, "None of your players are invited to this party, or it has already started")
+			return
+		}
+
+		sp, err := h.db.GetScheduledParty(ctx, id)
+		if err != nil || sp == nil {
+			respondEphemeral(s, i, fmt.Sprintf("Recorded %s for %s", status, strings.Join(updated, ", ")))
+			return
+		}
+		h.notifyDataChange("scheduled_party")
+
+		// Refresh the RSVP lists on the message that was clicked
+		title := "📅 Party Scheduled"
+		if i.Message != nil && len(i.Message.Embeds) > 0 {
+			title = i.Message.Embeds[0].Title
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{buildScheduledPartyEmbed(sp, title)},
+				Components: rsvpComponents(sp.ID),
+			},
+		})
+	}
+}
+
+// playerMentions returns a Discord mention for each distinct user owning one of the players
+func (h *questsHandler) playerMentions(ctx context.Context, players []string) string {
+	seen := make(map[string]bool)
+	var mentions []string
+	for _, player := range players {
+		discordID, err := h.db.GetDiscordUserIDForPlayer(ctx, player)
+		if err != nil || discordID == "" || seen[discordID] {
+			continue
+		}
+		seen[discordID] = true
+		mentions = append(mentions, fmt.Sprintf("<@%s>", discordID))
+	}
+	return strings.Join(mentions, " ")
+}
+
+func scheduledPartyTitle(sp *quests.ScheduledParty) string {
+	if sp.Title != "" {
+		return sp.Title
+	}
+	return "Scheduled Party"
+}
+
+// buildScheduledPartyEmbed lists a scheduled party's start time and RSVPs
+func buildScheduledPartyEmbed(sp *quests.ScheduledParty, title string) *discordgo.MessageEmbed {
+	rsvpList := func(status string) string {
+		players := sp.PlayersWithStatus(status)
+		if len(players) == 0 {
+			return "—"
+		}
+		return strings.Join(players, "\n")
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: fmt.Sprintf("**%s** starts <t:%d:F> (<t:%d:R>)\nOnly players who accept are included in the party plan.", scheduledPartyTitle(sp), sp.ScheduledAt.Unix(), sp.ScheduledAt.Unix()),
+		Color:       0x3498db, // Blue color
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "✅ Accepted", Value: rsvpList(quests.RSVPAccepted), Inline: true},
+			{Name: "❔ Pending", Value: rsvpList(quests.RSVPPending), Inline: true},
+			{Name: "❌ Declined", Value: rsvpList(quests.RSVPDeclined), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("ID: %s", sp.ID),
+		},
+	}
+}
+
+// rsvpComponents returns the Accept and Decline buttons for a scheduled party
+func rsvpComponents(scheduledPartyID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Accept",
+					Style:    discordgo.SuccessButton,
+					CustomID: quests.RSVPButtonID(scheduledPartyID, quests.RSVPAccepted),
+				},
+				discordgo.Button{
+					Label:    "Decline",
+					Style:    discordgo.DangerButton,
+					CustomID: quests.RSVPButtonID(scheduledPartyID, quests.RSVPDeclined),
+				},
+			},
+		},
+	}
+}
+
+// parseScheduleTime parses when a party starts from the start of args and returns the remaining args.
+// Accepts a delay ("2h", "1d12h"), a UTC clock time ("19:30", today or tomorrow) or a UTC date and
+// time ("2026-05-01 19:30" or "2026-05-01T19:30").
+func parseScheduleTime(args []string, now time.Time) (time.Time, []string, error) {
+	if len(args) >= 2 {
+		if t, err := time.Parse("2006-01-02 15:04", args[0]+" "+args[1]); err == nil {
+			return t, args[2:], nil
+		}
+	}
+	if t, err := time.Parse("2006-01-02T15:04", args[0]); err == nil {
+		return t, args[1:], nil
+	}
+	if clockTimePattern.MatchString(args[0]) {
+		clock, err := time.Parse("15:04", args[0])
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("invalid time: %s", args[0])
+		}
+		t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.UTC)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, args[1:], nil
+	}
+
+	delay, err := parseDayDuration(args[0])
+	if err != nil || delay <= 0 {
+		return time.Time{}, nil, fmt.Errorf("invalid time: %s", args[0])
+	}
+	return now.Add(delay), args[1:], nil
+}
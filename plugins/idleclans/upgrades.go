@@ -0,0 +1,136 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/upgrades"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// upgradeRecommendationsShown is how many next-tier recommendations !upgrades lists
+const upgradeRecommendationsShown = 5
+
+func (p *plugin) upgradesCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		if !strings.HasPrefix(m.Content, "!upgrades") {
+			return
+		}
+
+		if p.upgradesDB == nil {
+			s.ChannelMessageSend(m.ChannelID, "Error: Upgrade data unavailable")
+			return
+		}
+
+		playerName := strings.TrimSpace(strings.TrimPrefix(m.Content, "!upgrades"))
+		if playerName == "" {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!upgrades <player>`")
+			return
+		}
+
+		l.Info(
+			"Processing upgrades command",
+			zap.String("player", playerName),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		player, err := p.client.GetPlayer(ctx, playerName)
+		if err != nil {
+			l.Error("Error getting player profile", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, "Error getting player profile")
+			return
+		}
+
+		report, err := p.upgradesDB.BuildReport(ctx, p.marketDB, playerName, player)
+		if err != nil {
+			l.Error("Error building upgrade report", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, "Error building upgrade report")
+			return
+		}
+
+		s.ChannelMessageSendEmbeds(m.ChannelID, buildUpgradesEmbeds(report))
+	}
+}
+
+// buildUpgradesEmbeds renders tiers for every upgrade followed by the cheapest next tiers
+func buildUpgradesEmbeds(report *upgrades.Report) []*discordgo.MessageEmbed {
+	printer := message.NewPrinter(language.English)
+
+	var sb strings.Builder
+	for _, u := range report.Upgrades {
+		switch {
+		case u.Maxed:
+			sb.WriteString(fmt.Sprintf("✅ **%s**: %d/%d\n", u.DisplayName, u.Tier, u.MaxTier))
+		case u.MaxTier > 0:
+			sb.WriteString(fmt.Sprintf("**%s**: %d/%d\n", u.DisplayName, u.Tier, u.MaxTier))
+		default:
+			sb.WriteString(fmt.Sprintf("**%s**: %d\n", u.DisplayName, u.Tier))
+		}
+	}
+
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       fmt.Sprintf("Upgrades: %s", report.PlayerName),
+		Description: sb.String(),
+		Color:       0x3498db, // Blue color
+	}}
+
+	if len(report.Recommendations) == 0 {
+		embeds[0].Footer = &discordgo.MessageEmbedFooter{
+			Text: "No next-tier costs recorded yet. Officers can add them through the admin upgrades API.",
+		}
+		return embeds
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, upgradeRecommendationsShown)
+	for i, u := range report.Recommendations {
+		if i >= upgradeRecommendationsShown {
+			break
+		}
+
+		var value strings.Builder
+		if u.Next.GoldCost > 0 {
+			value.WriteString(printer.Sprintf("Gold: %dg\n", u.Next.GoldCost))
+		}
+		for _, material := range u.Next.Materials {
+			if material.Priced {
+				value.WriteString(printer.Sprintf("%dx %s (%dg)\n", material.Quantity, material.Name, material.Total))
+			} else {
+				value.WriteString(printer.Sprintf("%dx %s (*no market price*)\n", material.Quantity, material.Name))
+			}
+		}
+		total := printer.Sprintf("**Total: %dg**", u.Next.TotalCost)
+		if !u.Next.FullyPriced {
+			total += " (partial)"
+		}
+		value.WriteString(total)
+
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%d. %s → tier %d", i+1, u.DisplayName, u.Next.Tier),
+			Value:  value.String(),
+			Inline: false,
+		})
+	}
+
+	embeds = append(embeds, &discordgo.MessageEmbed{
+		Title:  "Cheapest Next Upgrades",
+		Color:  0x2ecc71, // Green color
+		Fields: fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Materials priced at the lowest market sell price",
+		},
+	})
+	return embeds
+}
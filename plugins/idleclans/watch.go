@@ -0,0 +1,228 @@
+package idleclans
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/market"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const watchUsage = "Usage: `!watch add <item> buy|sell <price> [dm|channel]`, `!watch list` or `!watch rm <id>`"
+
+func (p *plugin) watchCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		parts := strings.Fields(m.Content)
+		if len(parts) == 0 || parts[0] != "!watch" {
+			return
+		}
+
+		if !p.marketEnabled || p.marketDB == nil {
+			s.ChannelMessageSend(m.ChannelID, "Error: Market tracking is not enabled")
+			return
+		}
+
+		if len(parts) < 2 {
+			s.ChannelMessageSend(m.ChannelID, watchUsage)
+			return
+		}
+
+		l.Info(
+			"Processing watch command",
+			zap.Strings("args", parts[1:]),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		switch strings.ToLower(parts[1]) {
+		case "add":
+			p.handleWatchAdd(ctx, s, m, parts[2:])
+		case "list":
+			p.handleWatchList(ctx, s, m)
+		case "rm", "remove", "delete":
+			p.handleWatchRemove(ctx, s, m, parts[2:])
+		default:
+			s.ChannelMessageSend(m.ChannelID, watchUsage)
+		}
+	}
+}
+
+// handleWatchAdd parses `<item> buy|sell <price> [dm|channel]`. Item names may contain spaces,
+// so the arguments are read from the end.
+func (p *plugin) handleWatchAdd(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	l := ctxzap.Extract(ctx)
+
+	delivery := market.WatchDeliveryChannel
+	if len(args) > 0 {
+		switch strings.ToLower(args[len(args)-1]) {
+		case market.WatchDeliveryDM:
+			delivery = market.WatchDeliveryDM
+			args = args[:len(args)-1]
+		case market.WatchDeliveryChannel:
+			args = args[:len(args)-1]
+		}
+	}
+	if len(args) < 3 {
+		s.ChannelMessageSend(m.ChannelID, watchUsage)
+		return
+	}
+
+	threshold, err := parseGoldAmount(args[len(args)-1])
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Invalid price %q", args[len(args)-1]))
+		return
+	}
+	watchType := strings.ToLower(args[len(args)-2])
+	if watchType != "buy" && watchType != "sell" {
+		s.ChannelMessageSend(m.ChannelID, "Error: Watch type must be `buy` or `sell`")
+		return
+	}
+	itemName := strings.Join(args[:len(args)-2], " ")
+
+	item, err := p.findMarketItem(ctx, itemName)
+	if err != nil {
+		l.Error("Error looking up market item", zap.Error(err), zap.String("item", itemName))
+	}
+	if item == nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: No market item found for %q", itemName))
+		return
+	}
+
+	count, err := p.marketDB.GetWatchCountByUser(ctx, m.Author.ID)
+	if err != nil {
+		l.Error("Error getting watch count", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error creating watch")
+		return
+	}
+	if count >= market.MaxWatchesPerUser {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Maximum of %d active watches allowed. Remove one with `!watch rm <id>`", market.MaxWatchesPerUser))
+		return
+	}
+
+	// Channel delivery posts back to the channel the watch was created in
+	channelID := ""
+	if delivery == market.WatchDeliveryChannel {
+		channelID = m.ChannelID
+	}
+
+	watch, err := p.marketDB.CreateWatchWithDelivery(ctx, m.Author.ID, item.ID, watchType, threshold, delivery, channelID)
+	if err != nil {
+		l.Error("Error creating watch", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error creating watch")
+		return
+	}
+
+	printer := message.NewPrinter(language.English)
+	condition := "buy price rises to"
+	if watchType == "sell" {
+		condition = "sell price drops to"
+	}
+	where := "here"
+	if delivery == market.WatchDeliveryDM {
+		where = "by DM"
+	}
+	s.ChannelMessageSend(m.ChannelID, printer.Sprintf(
+		"Watch #%d created: you'll be notified %s when the %s %dg for **%s**",
+		watch.ID, where, condition, threshold, item.DisplayName,
+	))
+}
+
+func (p *plugin) handleWatchList(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate) {
+	l := ctxzap.Extract(ctx)
+
+	watches, err := p.marketDB.GetWatchesByUser(ctx, m.Author.ID)
+	if err != nil {
+		l.Error("Error getting watches", zap.Error(err))
+		s.ChannelMessageSend(m.ChannelID, "Error getting watches")
+		return
+	}
+	if len(watches) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "You have no watches. Add one with `!watch add <item> buy|sell <price>`")
+		return
+	}
+
+	printer := message.NewPrinter(language.English)
+	var sb strings.Builder
+	for _, w := range watches {
+		current := w.CurrentBuyPrice
+		if w.WatchType == "sell" {
+			current = w.CurrentSellPrice
+		}
+		status := "⏳"
+		if w.Triggered {
+			status = "✅"
+		}
+		sb.WriteString(printer.Sprintf(
+			"%s **#%d** [%s](%s) — %s at %dg (now %dg) · %s\n",
+			status, w.ID, w.ItemDisplayName, p.marketItemURL(w.ItemID),
+			w.WatchType, w.Threshold, current, w.Delivery,
+		))
+	}
+
+	s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{{
+		Title:       fmt.Sprintf("%s's Market Watches", m.Author.Username),
+		Description: sb.String(),
+		Color:       0x10B981, // Emerald green
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "⏳ waiting · ✅ triggered (removed after 24 hours)",
+		},
+	}})
+}
+
+func (p *plugin) handleWatchRemove(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) != 1 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!watch rm <id>`")
+		return
+	}
+	watchID, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Invalid watch ID %q", args[0]))
+		return
+	}
+
+	if err := p.marketDB.DeleteWatch(ctx, watchID, m.Author.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error: Watch #%d not found", watchID))
+			return
+		}
+		ctxzap.Extract(ctx).Error("Error deleting watch", zap.Error(err), zap.Int("watch_id", watchID))
+		s.ChannelMessageSend(m.ChannelID, "Error deleting watch")
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Watch #%d removed", watchID))
+}
+
+// parseGoldAmount parses a price such as "1500", "1,500", "1.5k" or "2m"
+func parseGoldAmount(s string) (int, error) {
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSuffix(s, "g"), ",", ""))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"):
+		multiplier, s = 1e3, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		multiplier, s = 1e6, strings.TrimSuffix(s, "m")
+	case strings.HasSuffix(s, "b"):
+		multiplier, s = 1e9, strings.TrimSuffix(s, "b")
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return int(value * multiplier), nil
+}
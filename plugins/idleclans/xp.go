@@ -0,0 +1,213 @@
+package idleclans
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jirwin/idleclans/pkg/bot"
+	"github.com/jirwin/idleclans/pkg/idleclans"
+	"github.com/jirwin/idleclans/pkg/tracker"
+	"go.uber.org/zap"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+func (p *plugin) xpCmd(ctx context.Context) bot.MessageHandler {
+	l := ctxzap.Extract(ctx)
+
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+
+		if !strings.HasPrefix(m.Content, "!xp") {
+			return
+		}
+
+		parts := strings.Fields(strings.TrimPrefix(m.Content, "!xp"))
+		if len(parts) < 3 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!xp <player> <skill> <target_level> [xp_per_hour]`\nExample: `!xp MyPlayer mining 100 150k`")
+			return
+		}
+
+		playerName := parts[0]
+		skillName := strings.ToLower(parts[1])
+		targetLevel, err := strconv.Atoi(parts[2])
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Invalid target level: %s", parts[2]))
+			return
+		}
+
+		var xpPerHour float64
+		if len(parts) > 3 {
+			xpPerHour, err = parseXPRate(parts[3])
+			if err != nil {
+				s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Invalid XP/hour rate: %s", parts[3]))
+				return
+			}
+		}
+
+		l.Info(
+			"Processing xp command",
+			zap.String("player", playerName),
+			zap.String("skill", skillName),
+			zap.Int("target", targetLevel),
+			zap.String("from", m.Author.Username),
+			zap.String("channel", m.ChannelID),
+		)
+
+		player, err := p.client.GetSimplePlayer(ctx, playerName)
+		if err != nil {
+			l.Error("Error getting player profile", zap.Error(err))
+			s.ChannelMessageSend(m.ChannelID, "Error getting player profile")
+			return
+		}
+
+		var exp float64
+		found := false
+		for k, v := range player.Skills {
+			if strings.EqualFold(k, skillName) {
+				exp = v
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Unknown skill: %s", skillName))
+			return
+		}
+
+		// Fall back to the player's recorded rate over the last week
+		rateSource := "entered"
+		if xpPerHour == 0 && p.trackerDB != nil {
+			gains, err := p.trackerDB.GetGains(ctx, playerName, tracker.PeriodWeek)
+			if err != nil {
+				l.Warn("Error getting recorded gains", zap.Error(err))
+			} else if gains != nil {
+				if rate, ok := gains.SkillXPPerHour(skillName); ok {
+					xpPerHour = rate
+					rateSource = "recorded over the last week"
+				}
+			}
+		}
+
+		progress, err := idleclans.CalculateXPToLevel(int64(exp), targetLevel)
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Invalid target level: %s", err.Error()))
+			return
+		}
+
+		printer := message.NewPrinter(language.English)
+		titleCaser := cases.Title(language.English)
+
+		levelValue := fmt.Sprintf("%d", progress.Level)
+		if progress.VirtualLevel > progress.Level {
+			levelValue = fmt.Sprintf("%d (virtual %d)", progress.Level, progress.VirtualLevel)
+		}
+
+		fields := []*discordgo.MessageEmbedField{
+			{
+				Name:   "Current Level",
+				Value:  levelValue,
+				Inline: true,
+			},
+			{
+				Name:   "Current XP",
+				Value:  printer.Sprintf("%d", progress.Experience),
+				Inline: true,
+			},
+			{
+				Name:   fmt.Sprintf("XP for Level %d", progress.TargetLevel),
+				Value:  printer.Sprintf("%d", progress.TargetExperience),
+				Inline: true,
+			},
+		}
+
+		if progress.Remaining == 0 {
+			fields = append(fields, &discordgo.MessageEmbedField{
+				Name:   "Remaining",
+				Value:  "Target already reached ✅",
+				Inline: false,
+			})
+		} else {
+			fields = append(fields, &discordgo.MessageEmbedField{
+				Name:   "Remaining",
+				Value:  printer.Sprintf("%d XP", progress.Remaining),
+				Inline: false,
+			})
+
+			if duration, ok := idleclans.EstimateTimeToLevel(progress.Remaining, xpPerHour); ok {
+				fields = append(fields, &discordgo.MessageEmbedField{
+					Name:   "Estimated Time",
+					Value:  printer.Sprintf("%s at %.0f XP/h (%s)", formatDuration(duration), xpPerHour, rateSource),
+					Inline: false,
+				})
+			} else {
+				fields = append(fields, &discordgo.MessageEmbedField{
+					Name:   "Estimated Time",
+					Value:  "No recorded gains yet - add an XP/hour rate to estimate time, e.g. `150k`",
+					Inline: false,
+				})
+			}
+		}
+
+		description := fmt.Sprintf("%s → level %d", titleCaser.String(skillName), progress.TargetLevel)
+		if progress.TargetLevel > idleclans.MaxSkillLevel {
+			description += fmt.Sprintf("\n*Levels above %d are virtual*", idleclans.MaxSkillLevel)
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("XP Calculator: %s", playerName),
+			Description: description,
+			Color:       0x3498db, // Blue color
+			Fields:      fields,
+		}
+
+		s.ChannelMessageSendEmbeds(m.ChannelID, []*discordgo.MessageEmbed{embed})
+	}
+}
+
+// parseXPRate parses an XP/hour rate with an optional k/m suffix (e.g. "150k", "1.2m")
+func parseXPRate(input string) (float64, error) {
+	input = strings.TrimSuffix(strings.ToLower(input), "/h")
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(input, "k"):
+		multiplier = 1_000
+		input = strings.TrimSuffix(input, "k")
+	case strings.HasSuffix(input, "m"):
+		multiplier = 1_000_000
+		input = strings.TrimSuffix(input, "m")
+	}
+
+	rate, err := strconv.ParseFloat(input, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate <= 0 {
+		return 0, fmt.Errorf("rate must be positive")
+	}
+	return rate * multiplier, nil
+}
+
+// formatDuration formats a duration as days, hours and minutes
+func formatDuration(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -5,6 +5,9 @@ import { Clan } from './pages/Clan';
 import { Party } from './pages/Party';
 import { Admin } from './pages/Admin';
 import { Market } from './pages/Market';
+import { Competitions } from './pages/Competitions';
+import { Upgrades } from './pages/Upgrades';
+import { Player } from './pages/Player';
 
 // Declare the global admin mode flag injected by the server
 declare global {
@@ -36,6 +39,9 @@ function App() {
         <Route path="/clan" element={<Clan />} />
         <Route path="/party/:partyId" element={<Party />} />
         <Route path="/market" element={<Market />} />
+        <Route path="/competitions" element={<Competitions />} />
+        <Route path="/upgrades" element={<Upgrades />} />
+        <Route path="/player" element={<Player />} />
         <Route path="*" element={<Navigate to="/" replace />} />
       </Routes>
     </BrowserRouter>
@@ -1,4 +1,4 @@
-import type { UserData, PlayerData, ClanBossData, ClanKeysData, PlanData, PartySession, PartySummary } from './types';
+import type { UserData, PlayerData, ClanBossData, ClanKeysData, PlanData, PartySession, PartySummary, ScheduledParty, KeyForecast, Competition, CompetitionDetail, UpgradeReport } from './types';
 
 const API_BASE = '/api';
 
@@ -168,6 +168,63 @@ export async function fetchClanPlayers(): Promise<string[]> {
   return data.players || [];
 }
 
+// Upgrade API functions
+
+export async function fetchPlayerUpgrades(playerName: string): Promise<UpgradeReport> {
+  const res = await fetch(`${API_BASE}/players/${encodeURIComponent(playerName)}/upgrades`, {
+    credentials: 'include',
+  });
+
+  if (res.status === 401) {
+    throw new Error('Unauthorized');
+  }
+
+  if (!res.ok) {
+    throw new Error(`Failed to fetch upgrades: ${res.statusText}`);
+  }
+
+  return res.json();
+}
+
+// Competition API functions
+
+export async function fetchCompetitions(): Promise<Competition[]> {
+  const res = await fetch(`${API_BASE}/competitions`, {
+    credentials: 'include',
+  });
+
+  if (res.status === 401) {
+    throw new Error('Unauthorized');
+  }
+
+  if (!res.ok) {
+    throw new Error(`Failed to fetch competitions: ${res.statusText}`);
+  }
+
+  const data = await res.json();
+  return data ?? [];
+}
+
+export async function fetchCompetition(id: number): Promise<CompetitionDetail> {
+  const res = await fetch(`${API_BASE}/competitions/${id}`, {
+    credentials: 'include',
+  });
+
+  if (res.status === 401) {
+    throw new Error('Unauthorized');
+  }
+
+  if (res.status === 404) {
+    throw new Error('Competition not found');
+  }
+
+  if (!res.ok) {
+    throw new Error(`Failed to fetch competition: ${res.statusText}`);
+  }
+
+  return res.json();
+}
+
 export async function sendPlanToDiscord(players: string[], noPing: boolean = false): Promise<void> {
   const res = await fetch(`${API_BASE}/clan/plan/send`, {
     method: 'POST',
@@ -536,6 +593,94 @@ export async function endParty(partyId: string): Promise<void> {
   }
 }
 
+// Scheduled party API functions
+
+export async function getScheduledParties(): Promise<ScheduledParty[]> {
+  const res = await fetch(`${API_BASE}/scheduled-parties`, {
+    credentials: 'include',
+  });
+
+  if (res.status === 401) {
+    throw new Error('Unauthorized');
+  }
+
+  if (!res.ok) {
+    throw new Error(`Failed to get scheduled parties: ${res.statusText}`);
+  }
+
+  return res.json();
+}
+
+export async function getKeyForecast(): Promise<KeyForecast> {
+  const res = await fetch(`${API_BASE}/clan/key-forecast`, {
+    credentials: 'include',
+  });
+
+  if (res.status === 401) {
+    throw new Error('Unauthorized');
+  }
+
+  if (!res.ok) {
+    throw new Error(`Failed to get key forecast: ${res.statusText}`);
+  }
+
+  return res.json();
+}
+
+export async function scheduleParty(players: string[], scheduledAt: Date, title: string = ''): Promise<ScheduledParty> {
+  const res = await fetch(`${API_BASE}/scheduled-parties`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    credentials: 'include',
+    body: JSON.stringify({ title, scheduled_at: scheduledAt.toISOString(), players }),
+  });
+
+  if (res.status === 401) {
+    throw new Error('Unauthorized');
+  }
+
+  if (!res.ok) {
+    const text = await res.text();
+    throw new Error(text || `Failed to schedule party: ${res.statusText}`);
+  }
+
+  return res.json();
+}
+
+export async function rsvpScheduledParty(id: string, status: 'accepted' | 'declined'): Promise<void> {
+  const res = await fetch(`${API_BASE}/scheduled-parties/${id}/rsvp`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    credentials: 'include',
+    body: JSON.stringify({ status }),
+  });
+
+  if (res.status === 401) {
+    throw new Error('Unauthorized');
+  }
+
+  if (!res.ok) {
+    const text = await res.text();
+    throw new Error(text || `Failed to RSVP: ${res.statusText}`);
+  }
+}
+
+export async function cancelScheduledParty(id: string): Promise<void> {
+  const res = await fetch(`${API_BASE}/scheduled-parties/${id}`, {
+    method: 'DELETE',
+    credentials: 'include',
+  });
+
+  if (res.status === 401) {
+    throw new Error('Unauthorized');
+  }
+
+  if (!res.ok) {
+    const text = await res.text();
+    throw new Error(text || `Failed to cancel party: ${res.statusText}`);
+  }
+}
+
 // Market API functions
 
 export interface MarketItem {
@@ -825,6 +970,8 @@ export interface MarketWatch {
   triggered_at?: string;
   created_at: string;
   expires_at?: string;
+  delivery: 'channel' | 'dm';
+  channel_id?: string;
 }
 
 export interface MarketWatchWithItem extends MarketWatch {
@@ -0,0 +1,88 @@
+import { useEffect, useState, useCallback } from 'react';
+import type { KeyForecast as KeyForecastData } from '../types';
+import { BOSSES, KEY_TYPES } from '../types';
+import { getKeyForecast } from '../api';
+import { useSSE } from '../hooks/useSSE';
+
+export function KeyForecast() {
+  const [forecast, setForecast] = useState<KeyForecastData | null>(null);
+
+  const loadForecast = useCallback(async () => {
+    try {
+      setForecast(await getKeyForecast());
+    } catch (err) {
+      console.error('Failed to load key forecast:', err);
+    }
+  }, []);
+
+  useSSE({
+    onUpdate: (eventType) => {
+      if (!eventType || eventType === 'keys' || eventType === 'quest') loadForecast();
+    },
+  });
+
+  useEffect(() => {
+    loadForecast();
+  }, [loadForecast]);
+
+  if (!forecast) return null;
+
+  const shortCount = forecast.keys.filter((item) => item.short > 0).length;
+
+  return (
+    <div className="bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] overflow-hidden mb-4">
+      <div className="px-4 py-3 border-b border-[var(--color-border)]">
+        <h2 className="font-semibold text-white flex items-center gap-2">
+          <span>📈</span>
+          Key Forecast
+        </h2>
+        <p className="text-xs text-gray-500 mt-1">
+          Keys needed for this week's remaining quests before the reset in {forecast.days_left.toFixed(1)} days.
+          Drop rates come from the last {forecast.window_days} days of key updates.
+        </p>
+      </div>
+
+      {forecast.keys.length === 0 ? (
+        <p className="p-4 text-sm text-gray-500">No keys needed for this week's remaining quests</p>
+      ) : (
+        <div className="divide-y divide-[var(--color-border)]">
+          {shortCount === 0 && (
+            <p className="px-4 py-2 text-sm text-emerald-400">Every key is covered this week</p>
+          )}
+          {forecast.keys.map((item) => {
+            const keyInfo = KEY_TYPES.find((k) => k.type === item.key_type);
+            const bosses = item.bosses
+              .map((name) => BOSSES.find((b) => b.name === name)?.label ?? name)
+              .join(', ');
+            return (
+              <div key={item.key_type} className="px-4 py-2 flex items-center justify-between flex-wrap gap-2">
+                <div className="flex items-center gap-2">
+                  <div
+                    className="w-3 h-3 rounded-sm"
+                    style={{ backgroundColor: keyInfo?.color ?? '#666' }}
+                  />
+                  <span className="text-sm font-medium text-white">{keyInfo?.label ?? item.key_type}</span>
+                  {bosses && <span className="text-xs text-gray-500">{bosses}</span>}
+                </div>
+                <div className="flex items-center gap-3 text-xs text-gray-400">
+                  <span>need {item.keys_needed}</span>
+                  <span>have {item.keys_held}</span>
+                  <span title={`${item.drops_per_day.toFixed(1)} per day`}>+{item.expected_drops} expected</span>
+                  {item.short > 0 ? (
+                    <span className="px-2 py-0.5 rounded bg-red-900/30 text-red-300 border border-red-800">
+                      short by {item.short}
+                    </span>
+                  ) : (
+                    <span className="px-2 py-0.5 rounded bg-emerald-600/30 text-emerald-300 border border-emerald-700">
+                      covered
+                    </span>
+                  )}
+                </div>
+              </div>
+            );
+          })}
+        </div>
+      )}
+    </div>
+  );
+}
@@ -0,0 +1,189 @@
+import { useEffect, useState, useCallback } from 'react';
+import { Link } from 'react-router-dom';
+import type { ScheduledParty } from '../types';
+import { getScheduledParties, scheduleParty, rsvpScheduledParty, cancelScheduledParty } from '../api';
+import { useSSE } from '../hooks/useSSE';
+
+interface ScheduledPartiesProps {
+  selectedPlayers: string[];
+}
+
+const RSVP_STYLES: Record<string, string> = {
+  accepted: 'bg-emerald-600/30 text-emerald-300 border-emerald-700',
+  pending: 'bg-[var(--color-bg-input)] text-gray-400 border-[var(--color-border)]',
+  declined: 'bg-red-900/30 text-red-300 border-red-800 line-through',
+};
+
+// Format a date for a datetime-local input in the browser's time zone
+function toLocalInputValue(date: Date): string {
+  const offset = date.getTimezoneOffset() * 60000;
+  return new Date(date.getTime() - offset).toISOString().slice(0, 16);
+}
+
+export function ScheduledParties({ selectedPlayers }: ScheduledPartiesProps) {
+  const [parties, setParties] = useState<ScheduledParty[]>([]);
+  const [title, setTitle] = useState('');
+  const [scheduledAt, setScheduledAt] = useState(() => toLocalInputValue(new Date(Date.now() + 60 * 60 * 1000)));
+  const [scheduling, setScheduling] = useState(false);
+  const [busyId, setBusyId] = useState<string | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  const loadParties = useCallback(async () => {
+    try {
+      setParties(await getScheduledParties());
+    } catch (err) {
+      console.error('Failed to load scheduled parties:', err);
+    }
+  }, []);
+
+  useSSE({
+    onUpdate: (eventType) => {
+      if (!eventType || eventType === 'scheduled_party') loadParties();
+    },
+  });
+
+  useEffect(() => {
+    loadParties();
+  }, [loadParties]);
+
+  const handleSchedule = async () => {
+    if (selectedPlayers.length === 0 || !scheduledAt) return;
+    setScheduling(true);
+    setError(null);
+    try {
+      await scheduleParty(selectedPlayers, new Date(scheduledAt), title.trim());
+      setTitle('');
+      await loadParties();
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to schedule party');
+    } finally {
+      setScheduling(false);
+    }
+  };
+
+  const handleAction = async (id: string, action: () => Promise<void>) => {
+    setBusyId(id);
+    setError(null);
+    try {
+      await action();
+      await loadParties();
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Request failed');
+    } finally {
+      setBusyId(null);
+    }
+  };
+
+  return (
+    <div className="bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] overflow-hidden">
+      <div className="px-4 py-3 border-b border-[var(--color-border)]">
+        <h3 className="font-semibold text-white text-sm flex items-center gap-2">
+          <span>📅</span>
+          Scheduled Parties
+        </h3>
+        <p className="text-xs text-gray-500">
+          Invitees RSVP here or in Discord and are reminded before the start. The party starts with everyone who accepted.
+        </p>
+      </div>
+
+      {/* Schedule form */}
+      <div className="px-4 py-3 border-b border-[var(--color-border)] flex items-center gap-2 flex-wrap">
+        <input
+          type="text"
+          value={title}
+          onChange={(e) => setTitle(e.target.value)}
+          placeholder="Title (optional)"
+          className="px-3 py-1.5 text-sm bg-[var(--color-bg-input)] border border-[var(--color-border)] rounded-lg text-white placeholder-gray-500 focus:outline-none focus:border-pink-500"
+        />
+        <input
+          type="datetime-local"
+          value={scheduledAt}
+          onChange={(e) => setScheduledAt(e.target.value)}
+          className="px-3 py-1.5 text-sm bg-[var(--color-bg-input)] border border-[var(--color-border)] rounded-lg text-white focus:outline-none focus:border-pink-500"
+        />
+        <button
+          onClick={handleSchedule}
+          disabled={scheduling || selectedPlayers.length === 0 || !scheduledAt}
+          className="px-4 py-1.5 text-sm font-medium rounded-lg transition-colors bg-pink-600 hover:bg-pink-700 disabled:bg-gray-600 text-white"
+        >
+          {scheduling ? 'Scheduling...' : 'Schedule Selected'}
+        </button>
+        {selectedPlayers.length === 0 && (
+          <span className="text-xs text-gray-500">Select players above to invite them</span>
+        )}
+      </div>
+
+      {error && (
+        <div className="px-4 py-2 text-sm text-red-400 border-b border-[var(--color-border)]">{error}</div>
+      )}
+
+      {/* Upcoming parties */}
+      {parties.length === 0 ? (
+        <p className="p-4 text-sm text-gray-500">No parties scheduled</p>
+      ) : (
+        <div className="divide-y divide-[var(--color-border)]">
+          {parties.map((party) => {
+            const myRSVPs = party.rsvps.filter((rsvp) => party.my_players.includes(rsvp.player_name));
+            const busy = busyId === party.id;
+            return (
+              <div key={party.id} className="px-4 py-3 space-y-2">
+                <div className="flex items-center justify-between flex-wrap gap-2">
+                  <div>
+                    <span className="font-medium text-white">{party.title || 'Scheduled Party'}</span>
+                    <span className="ml-2 text-xs text-gray-400">
+                      {new Date(party.scheduled_at).toLocaleString()}
+                    </span>
+                  </div>
+                  <div className="flex items-center gap-2">
+                    {myRSVPs.length > 0 && (
+                      <>
+                        <button
+                          onClick={() => handleAction(party.id, () => rsvpScheduledParty(party.id, 'accepted'))}
+                          disabled={busy || myRSVPs.every((rsvp) => rsvp.status === 'accepted')}
+                          className="px-3 py-1 text-xs rounded bg-emerald-600 hover:bg-emerald-700 disabled:bg-gray-600 text-white transition-colors"
+                        >
+                          Accept
+                        </button>
+                        <button
+                          onClick={() => handleAction(party.id, () => rsvpScheduledParty(party.id, 'declined'))}
+                          disabled={busy || myRSVPs.every((rsvp) => rsvp.status === 'declined')}
+                          className="px-3 py-1 text-xs rounded bg-red-600 hover:bg-red-700 disabled:bg-gray-600 text-white transition-colors"
+                        >
+                          Decline
+                        </button>
+                      </>
+                    )}
+                    {party.can_cancel && (
+                      <button
+                        onClick={() => handleAction(party.id, () => cancelScheduledParty(party.id))}
+                        disabled={busy}
+                        className="px-3 py-1 text-xs text-gray-400 hover:text-white border border-gray-600 rounded transition-colors"
+                      >
+                        Cancel
+                      </button>
+                    )}
+                    {party.party_id && (
+                      <Link to={`/party/${party.party_id}`} className="text-xs text-pink-400 hover:text-pink-300">
+                        Open party →
+                      </Link>
+                    )}
+                  </div>
+                </div>
+                <div className="flex flex-wrap gap-1">
+                  {party.rsvps.map((rsvp) => (
+                    <span
+                      key={rsvp.player_name}
+                      className={`px-2 py-0.5 text-xs rounded-full border ${RSVP_STYLES[rsvp.status]}`}
+                    >
+                      {rsvp.player_name}
+                    </span>
+                  ))}
+                </div>
+              </div>
+            );
+          })}
+        </div>
+      )}
+    </div>
+  );
+}
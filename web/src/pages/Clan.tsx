@@ -4,6 +4,8 @@ import type { ClanBossData, ClanKeysData, PlanData, PartySummary } from '../type
 import { BOSSES, KEY_TYPES } from '../types';
 import { fetchClanBosses, fetchClanKeys, fetchClanPlan, fetchClanPlayers, sendPlanToDiscord, createParty, getUserParties } from '../api';
 import { useSSE } from '../hooks/useSSE';
+import { ScheduledParties } from '../components/ScheduledParties';
+import { KeyForecast } from '../components/KeyForecast';
 
 type TabType = 'bosses' | 'keys' | 'plan';
 const MAX_PARTY_SIZE = 3;
@@ -203,6 +205,12 @@ export function Clan() {
             </div>
           </div>
           <div className="flex items-center gap-2">
+            <button
+              onClick={() => navigate('/competitions')}
+              className="px-4 py-2 text-sm text-gray-400 hover:text-white border border-gray-600 hover:border-gray-500 rounded-lg transition-colors"
+            >
+              Competitions
+            </button>
             <button
               onClick={() => navigate('/market')}
               className="px-4 py-2 text-sm text-gray-400 hover:text-white border border-gray-600 hover:border-gray-500 rounded-lg transition-colors"
@@ -383,6 +391,7 @@ export function Clan() {
         )}
 
         {/* Keys Tab */}
+        {activeTab === 'keys' && <KeyForecast />}
         {activeTab === 'keys' && keysData && (
           <div className="bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] overflow-hidden">
             <div className="px-4 py-3 border-b border-[var(--color-border)]">
@@ -522,6 +531,8 @@ export function Clan() {
               </div>
             </div>
 
+            <ScheduledParties selectedPlayers={Array.from(selectedPlayers)} />
+
             {/* Plan Results */}
             {planLoading ? (
               <div className="bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] p-8 text-center">
@@ -601,6 +612,9 @@ export function Clan() {
                               <div
                                 key={taskIndex}
                                 className="flex items-center justify-between text-sm"
+                                title={task.explanation
+                                  ? [task.explanation.reason, ...(task.explanation.alternatives ?? [])].join('\n')
+                                  : undefined}
                               >
                                 <div className="flex items-center gap-2">
                                   <div
@@ -615,15 +629,32 @@ export function Clan() {
                                 {task.no_keys ? (
                                   <span className="text-xs text-red-400 flex items-center gap-1">
                                     ⚠️ No keys
+                                    {task.explanation?.alternatives?.length ? (
+                                      <span className="text-gray-500">
+                                        (try {task.explanation.alternatives.slice(0, 2).join(', ')})
+                                      </span>
+                                    ) : null}
                                   </span>
                                 ) : (
                                   <span className="text-xs text-gray-500">
-                                    Key: {task.key_holder}
+                                    {task.key_holder ? `Key: ${task.key_holder}` : 'No key needed'}
                                   </span>
                                 )}
                               </div>
                             );
                           })}
+                          {party.violations?.map((violation) => {
+                            const bossInfo = getBossInfo(violation.boss_name);
+                            return (
+                              <div key={violation.boss_name} className="text-xs text-amber-400">
+                                ⚠️ {bossInfo?.label || violation.boss_name} allows{' '}
+                                {violation.min === violation.max
+                                  ? violation.max
+                                  : `${violation.min}-${violation.max}`}{' '}
+                                players
+                              </div>
+                            );
+                          })}
                         </div>
                       </div>
                     ))}
@@ -648,6 +679,15 @@ export function Clan() {
                                   })
                                   .join(', ')}
                               </span>
+                              {leftover.reasons &&
+                                Object.entries(leftover.reasons).map(([boss, explanation]) => (
+                                  <div key={boss} className="text-xs text-gray-500 ml-4">
+                                    {getBossInfo(boss)?.label || boss}: {explanation.reason}
+                                    {explanation.alternatives?.length
+                                      ? ` Keys elsewhere: ${explanation.alternatives.join(', ')}`
+                                      : ''}
+                                  </div>
+                                ))}
                             </div>
                           ))}
                           {planData.leftovers.length > 10 && (
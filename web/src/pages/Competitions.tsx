@@ -0,0 +1,220 @@
+import { useEffect, useState, useCallback } from 'react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+import type { Competition, CompetitionDetail } from '../types';
+import { fetchCompetitions, fetchCompetition } from '../api';
+import { useSSE } from '../hooks/useSSE';
+
+const STATUS_STYLES: Record<Competition['status'], string> = {
+  scheduled: 'bg-gray-700 text-gray-300',
+  active: 'bg-emerald-600 text-white',
+  finished: 'bg-violet-600 text-white',
+  cancelled: 'bg-red-900/50 text-red-300',
+};
+
+function formatNumber(n: number): string {
+  return Math.round(n).toLocaleString('en-US');
+}
+
+function formatMetric(c: Competition): string {
+  const label = c.metric.charAt(0).toUpperCase() + c.metric.slice(1);
+  return c.metric_type === 'boss' ? `${label} kills` : `${label} XP`;
+}
+
+export function Competitions() {
+  const navigate = useNavigate();
+  const [searchParams, setSearchParams] = useSearchParams();
+  const [competitions, setCompetitions] = useState<Competition[]>([]);
+  const [detail, setDetail] = useState<CompetitionDetail | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [lastRefresh, setLastRefresh] = useState<Date>(new Date());
+
+  const selectedParam = searchParams.get('id');
+
+  const loadData = useCallback(async () => {
+    try {
+      const list = await fetchCompetitions();
+      setCompetitions(list);
+
+      // Default to the most recent competition
+      const selectedId = selectedParam ? parseInt(selectedParam, 10) : list[0]?.id;
+      if (selectedId) {
+        setDetail(await fetchCompetition(selectedId));
+      } else {
+        setDetail(null);
+      }
+      setError(null);
+      setLastRefresh(new Date());
+    } catch (err) {
+      if (err instanceof Error && err.message === 'Unauthorized') {
+        navigate('/');
+        return;
+      }
+      setError(err instanceof Error ? err.message : 'Failed to load competitions');
+    } finally {
+      setLoading(false);
+    }
+  }, [navigate, selectedParam]);
+
+  // Reload the leaderboard when standings or snapshots change
+  useSSE({
+    onUpdate: (eventType) => {
+      if (!eventType || eventType === 'competition' || eventType === 'snapshots') {
+        loadData();
+      }
+    },
+    enabled: !loading,
+  });
+
+  useEffect(() => {
+    loadData();
+  }, [loadData]);
+
+  if (loading) {
+    return (
+      <div className="min-h-screen flex items-center justify-center">
+        <div className="text-center">
+          <div className="w-12 h-12 border-4 border-violet-500 border-t-transparent rounded-full animate-spin mx-auto mb-4" />
+          <p className="text-gray-400">Loading competitions...</p>
+        </div>
+      </div>
+    );
+  }
+
+  if (error) {
+    return (
+      <div className="min-h-screen flex items-center justify-center">
+        <div className="text-center">
+          <p className="text-red-400 mb-4">{error}</p>
+          <button
+            onClick={() => loadData()}
+            className="px-4 py-2 bg-violet-600 hover:bg-violet-700 text-white rounded-lg transition-colors"
+          >
+            Retry
+          </button>
+        </div>
+      </div>
+    );
+  }
+
+  const competition = detail?.competition;
+  const standings = detail?.standings ?? [];
+  const topGain = standings.length > 0 ? Math.max(standings[0].gain, 1) : 1;
+
+  return (
+    <div className="min-h-screen p-4 md:p-8">
+      <div className="max-w-4xl mx-auto">
+        {/* Header */}
+        <header className="flex items-center justify-between mb-6">
+          <div className="flex items-center gap-3">
+            <div className="w-10 h-10 rounded-lg bg-gradient-to-br from-violet-600 to-purple-700 flex items-center justify-center">
+              <span className="text-xl">🏆</span>
+            </div>
+            <div>
+              <h1 className="text-xl font-bold text-white">Competitions</h1>
+              <p className="text-xs text-gray-500">
+                Updated: {lastRefresh.toLocaleTimeString()}
+              </p>
+            </div>
+          </div>
+          <button
+            onClick={() => navigate('/clan')}
+            className="px-4 py-2 text-sm text-gray-400 hover:text-white border border-gray-600 hover:border-gray-500 rounded-lg transition-colors"
+          >
+            Clan
+          </button>
+        </header>
+
+        {competitions.length === 0 ? (
+          <div className="bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] p-8 text-center">
+            <p className="text-gray-400">No competitions yet.</p>
+            <p className="text-xs text-gray-500 mt-2">
+              Officers can start one in Discord with <code>!comp start &lt;skill|boss&gt; &lt;duration&gt;</code>
+            </p>
+          </div>
+        ) : (
+          <div className="grid gap-6 md:grid-cols-3">
+            {/* Competition list */}
+            <div className="bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] overflow-hidden">
+              <div className="px-4 py-3 border-b border-[var(--color-border)]">
+                <h2 className="font-semibold text-white text-sm">Events</h2>
+              </div>
+              <div className="p-2 space-y-1">
+                {competitions.map((c) => (
+                  <button
+                    key={c.id}
+                    onClick={() => setSearchParams({ id: String(c.id) })}
+                    className={`w-full text-left p-3 rounded-lg transition-colors ${
+                      competition?.id === c.id
+                        ? 'bg-violet-900/30 border border-violet-700/50'
+                        : 'hover:bg-[var(--color-bg-dark)] border border-transparent'
+                    }`}
+                  >
+                    <div className="flex items-center justify-between gap-2">
+                      <span className="text-sm font-medium text-gray-200 truncate">{c.name}</span>
+                      <span className={`px-2 py-0.5 text-xs rounded-full ${STATUS_STYLES[c.status]}`}>
+                        {c.status}
+                      </span>
+                    </div>
+                    <p className="text-xs text-gray-500 mt-1">{formatMetric(c)}</p>
+                  </button>
+                ))}
+              </div>
+            </div>
+
+            {/* Leaderboard */}
+            <div className="md:col-span-2 bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] overflow-hidden">
+              {competition ? (
+                <>
+                  <div className="px-4 py-3 border-b border-[var(--color-border)]">
+                    <div className="flex items-center justify-between gap-2">
+                      <h2 className="font-semibold text-white">{competition.name}</h2>
+                      <span className={`px-2 py-0.5 text-xs rounded-full ${STATUS_STYLES[competition.status]}`}>
+                        {competition.status}
+                      </span>
+                    </div>
+                    <p className="text-xs text-gray-500 mt-1">
+                      {formatMetric(competition)} • {new Date(competition.starts_at).toLocaleString()} – {new Date(competition.ends_at).toLocaleString()}
+                    </p>
+                  </div>
+                  <div className="p-4">
+                    {standings.length === 0 ? (
+                      <p className="text-sm text-gray-500 text-center py-4">
+                        Start values are captured shortly after the competition begins.
+                      </p>
+                    ) : (
+                      <div className="space-y-2">
+                        {standings.map((s) => (
+                          <div
+                            key={s.player_name}
+                            className="relative flex items-center justify-between p-3 rounded-lg bg-[var(--color-bg-dark)] border border-[var(--color-border)] overflow-hidden"
+                          >
+                            <div
+                              className="absolute inset-y-0 left-0 bg-violet-600/15"
+                              style={{ width: `${Math.max(0, (s.gain / topGain) * 100)}%` }}
+                            />
+                            <div className="relative flex items-center gap-3">
+                              <span className={`w-6 text-sm font-bold ${s.rank === 1 ? 'text-yellow-400' : 'text-gray-400'}`}>
+                                {s.rank}
+                              </span>
+                              <span className="text-sm text-gray-200">{s.player_name}</span>
+                            </div>
+                            <span className="relative text-sm font-medium text-white">
+                              +{formatNumber(s.gain)}
+                            </span>
+                          </div>
+                        ))}
+                      </div>
+                    )}
+                  </div>
+                </>
+              ) : (
+                <p className="text-sm text-gray-500 text-center p-8">Select a competition</p>
+              )}
+            </div>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
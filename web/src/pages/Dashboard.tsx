@@ -370,6 +370,13 @@ export function Dashboard() {
             >
               📈
             </button>
+            <button
+              onClick={() => navigate(`/upgrades?player=${encodeURIComponent(userData.player_name)}`)}
+              className="p-2 text-white bg-gradient-to-r from-amber-600 to-orange-600 hover:from-amber-500 hover:to-orange-500 rounded-lg transition-all"
+              title="Upgrades"
+            >
+              ⬆️
+            </button>
             <button
               onClick={() => navigate('/clan')}
               className="p-2 text-white bg-gradient-to-r from-violet-600 to-purple-600 hover:from-violet-500 hover:to-purple-500 rounded-lg transition-all"
@@ -0,0 +1,79 @@
+import { useState } from 'react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+
+export function Player() {
+  const navigate = useNavigate();
+  const [searchParams, setSearchParams] = useSearchParams();
+  const playerName = searchParams.get('name') ?? '';
+  const [input, setInput] = useState(playerName);
+  const [imageError, setImageError] = useState(false);
+
+  const handleSubmit = (e: React.FormEvent) => {
+    e.preventDefault();
+    if (input.trim()) {
+      setImageError(false);
+      setSearchParams({ name: input.trim() });
+    }
+  };
+
+  return (
+    <div className="min-h-screen p-4 md:p-8">
+      <div className="max-w-4xl mx-auto">
+        {/* Header */}
+        <header className="flex items-center justify-between mb-6">
+          <div className="flex items-center gap-3">
+            <div className="w-10 h-10 rounded-lg bg-gradient-to-br from-violet-600 to-purple-700 flex items-center justify-center">
+              <span className="text-xl">🪪</span>
+            </div>
+            <h1 className="text-xl font-bold text-white">Player</h1>
+          </div>
+          <button
+            onClick={() => navigate('/dashboard')}
+            className="px-4 py-2 text-sm text-gray-400 hover:text-white border border-gray-600 hover:border-gray-500 rounded-lg transition-colors"
+          >
+            My Quests
+          </button>
+        </header>
+
+        <form onSubmit={handleSubmit} className="flex gap-2 mb-6">
+          <input
+            type="text"
+            value={input}
+            onChange={(e) => setInput(e.target.value)}
+            placeholder="Player name"
+            className="flex-1 px-4 py-2 bg-[var(--color-bg-dark)] border border-[var(--color-border)] rounded-lg text-white placeholder-gray-500 focus:outline-none focus:border-violet-500"
+          />
+          <button
+            type="submit"
+            className="px-4 py-2 bg-violet-600 hover:bg-violet-700 text-white rounded-lg transition-colors"
+          >
+            Look up
+          </button>
+        </form>
+
+        {playerName && !imageError && (
+          <div className="space-y-4">
+            <img
+              src={`/api/players/${encodeURIComponent(playerName)}/card.png`}
+              alt={`${playerName}'s player card`}
+              onError={() => setImageError(true)}
+              className="w-full rounded-xl border border-[var(--color-border)]"
+            />
+            <button
+              onClick={() => navigate(`/upgrades?player=${encodeURIComponent(playerName)}`)}
+              className="px-4 py-2 text-sm text-gray-400 hover:text-white border border-gray-600 hover:border-gray-500 rounded-lg transition-colors"
+            >
+              View upgrades
+            </button>
+          </div>
+        )}
+
+        {playerName && imageError && (
+          <div className="text-center py-12">
+            <p className="text-red-400">Failed to load player card for {playerName}</p>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,170 @@
+import { useEffect, useState, useCallback } from 'react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+import type { UpgradeReport } from '../types';
+import { fetchPlayerUpgrades } from '../api';
+
+function formatGold(n: number): string {
+  return `${Math.round(n).toLocaleString('en-US')}g`;
+}
+
+export function Upgrades() {
+  const navigate = useNavigate();
+  const [searchParams, setSearchParams] = useSearchParams();
+  const playerName = searchParams.get('player') ?? '';
+  const [input, setInput] = useState(playerName);
+  const [report, setReport] = useState<UpgradeReport | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const loadData = useCallback(async () => {
+    if (!playerName) return;
+    setLoading(true);
+    try {
+      setReport(await fetchPlayerUpgrades(playerName));
+      setError(null);
+    } catch (err) {
+      if (err instanceof Error && err.message === 'Unauthorized') {
+        navigate('/');
+        return;
+      }
+      setError(err instanceof Error ? err.message : 'Failed to load upgrades');
+    } finally {
+      setLoading(false);
+    }
+  }, [navigate, playerName]);
+
+  useEffect(() => {
+    loadData();
+  }, [loadData]);
+
+  const handleSubmit = (e: React.FormEvent) => {
+    e.preventDefault();
+    if (input.trim()) {
+      setSearchParams({ player: input.trim() });
+    }
+  };
+
+  return (
+    <div className="min-h-screen p-4 md:p-8">
+      <div className="max-w-4xl mx-auto">
+        {/* Header */}
+        <header className="flex items-center justify-between mb-6">
+          <div className="flex items-center gap-3">
+            <div className="w-10 h-10 rounded-lg bg-gradient-to-br from-amber-600 to-orange-700 flex items-center justify-center">
+              <span className="text-xl">⬆️</span>
+            </div>
+            <h1 className="text-xl font-bold text-white">Upgrades</h1>
+          </div>
+          <button
+            onClick={() => navigate('/dashboard')}
+            className="px-4 py-2 text-sm text-gray-400 hover:text-white border border-gray-600 hover:border-gray-500 rounded-lg transition-colors"
+          >
+            My Quests
+          </button>
+        </header>
+
+        <form onSubmit={handleSubmit} className="flex gap-2 mb-6">
+          <input
+            type="text"
+            value={input}
+            onChange={(e) => setInput(e.target.value)}
+            placeholder="Player name"
+            className="flex-1 px-4 py-2 bg-[var(--color-bg-dark)] border border-[var(--color-border)] rounded-lg text-white placeholder-gray-500 focus:outline-none focus:border-violet-500"
+          />
+          <button
+            type="submit"
+            className="px-4 py-2 bg-violet-600 hover:bg-violet-700 text-white rounded-lg transition-colors"
+          >
+            Look up
+          </button>
+        </form>
+
+        {loading && (
+          <div className="text-center py-12">
+            <div className="w-12 h-12 border-4 border-violet-500 border-t-transparent rounded-full animate-spin mx-auto mb-4" />
+            <p className="text-gray-400">Loading upgrades...</p>
+          </div>
+        )}
+
+        {!loading && error && (
+          <div className="text-center py-12">
+            <p className="text-red-400 mb-4">{error}</p>
+            <button
+              onClick={() => loadData()}
+              className="px-4 py-2 bg-violet-600 hover:bg-violet-700 text-white rounded-lg transition-colors"
+            >
+              Retry
+            </button>
+          </div>
+        )}
+
+        {!loading && !error && report && (
+          <div className="grid gap-6 md:grid-cols-2">
+            {/* Recommendations */}
+            <div className="bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] overflow-hidden">
+              <div className="px-4 py-3 border-b border-[var(--color-border)]">
+                <h2 className="font-semibold text-white text-sm">Cheapest Next Upgrades</h2>
+              </div>
+              <div className="p-4 space-y-3">
+                {report.recommendations.length === 0 ? (
+                  <p className="text-sm text-gray-500">No next-tier costs have been recorded yet.</p>
+                ) : (
+                  report.recommendations.map((u, i) => (
+                    <div key={u.key} className="p-3 rounded-lg bg-[var(--color-bg-dark)] border border-[var(--color-border)]">
+                      <div className="flex items-center justify-between gap-2">
+                        <span className="text-sm font-medium text-gray-200">
+                          {i + 1}. {u.display_name} → tier {u.next!.tier}
+                        </span>
+                        <span className="text-sm font-semibold text-amber-400">
+                          {formatGold(u.next!.total_cost)}
+                          {!u.next!.fully_priced && <span className="text-xs text-gray-500"> (partial)</span>}
+                        </span>
+                      </div>
+                      <ul className="mt-2 space-y-1 text-xs text-gray-400">
+                        {u.next!.gold_cost > 0 && <li>Gold: {formatGold(u.next!.gold_cost)}</li>}
+                        {u.next!.materials.map((m) => (
+                          <li key={m.name_id}>
+                            {m.quantity.toLocaleString('en-US')}x {m.name}{' '}
+                            {m.priced ? `(${formatGold(m.total)})` : <span className="text-gray-600">(no market price)</span>}
+                          </li>
+                        ))}
+                      </ul>
+                    </div>
+                  ))
+                )}
+              </div>
+            </div>
+
+            {/* All upgrades */}
+            <div className="bg-[var(--color-bg-card)] rounded-xl border border-[var(--color-border)] overflow-hidden">
+              <div className="px-4 py-3 border-b border-[var(--color-border)]">
+                <h2 className="font-semibold text-white text-sm">{report.player_name}'s Tiers</h2>
+              </div>
+              <div className="p-4 space-y-2">
+                {report.upgrades.map((u) => (
+                  <div key={u.key}>
+                    <div className="flex items-center justify-between text-sm">
+                      <span className={u.maxed ? 'text-emerald-400' : 'text-gray-300'}>{u.display_name}</span>
+                      <span className="text-gray-400">
+                        {u.tier}
+                        {u.max_tier > 0 && `/${u.max_tier}`}
+                      </span>
+                    </div>
+                    {u.max_tier > 0 && (
+                      <div className="h-1.5 mt-1 rounded-full bg-[var(--color-bg-dark)] overflow-hidden">
+                        <div
+                          className={`h-full ${u.maxed ? 'bg-emerald-500' : 'bg-violet-500'}`}
+                          style={{ width: `${Math.min(100, (u.tier / u.max_tier) * 100)}%` }}
+                        />
+                      </div>
+                    )}
+                  </div>
+                ))}
+              </div>
+            </div>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -52,16 +52,31 @@ export interface PlanPartyTask {
   key_holder?: string;
   key_type: string;
   no_keys: boolean;
+  explanation?: PlanExplanation; // Why the task is planned this way
+}
+
+export interface PlanExplanation {
+  code: 'key_holder' | 'no_keys' | 'not_enough_keys' | 'not_selected' | 'no_party';
+  reason: string;
+  alternatives?: string[];
 }
 
 export interface PlanParty {
   players: string[];
   tasks: PlanPartyTask[];
+  violations?: PlanSizeViolation[]; // Bosses the party is too small or too large for
+}
+
+export interface PlanSizeViolation {
+  boss_name: string;
+  min: number;
+  max: number;
 }
 
 export interface PlanLeftover {
   player_name: string;
   needs: Record<string, number>;
+  reasons?: Record<string, PlanExplanation>; // boss -> why the kills weren't planned
 }
 
 export interface PlanData {
@@ -125,3 +140,108 @@ export interface PartySummary {
   created_at: string;
 }
 
+// Scheduled party types
+export type RSVPStatus = 'pending' | 'accepted' | 'declined';
+
+export interface ScheduledPartyRSVP {
+  player_name: string;
+  status: RSVPStatus;
+  responded_at: string | null;
+}
+
+export interface ScheduledParty {
+  id: string;
+  title: string;
+  scheduled_at: string;
+  created_by: string;
+  status: 'scheduled' | 'started' | 'cancelled';
+  reminder_sent_at: string | null;
+  party_id: string | null;
+  created_at: string;
+  rsvps: ScheduledPartyRSVP[];
+  my_players: string[];
+  can_cancel: boolean;
+}
+
+export interface KeyForecastItem {
+  key_type: string;
+  bosses: string[];
+  required_kills: number;
+  keys_needed: number;
+  keys_held: number;
+  drops_per_day: number;
+  expected_drops: number;
+  short: number;
+}
+
+export interface KeyForecast {
+  week: number;
+  year: number;
+  reset_at: string;
+  days_left: number;
+  window_days: number;
+  keys: KeyForecastItem[];
+}
+
+
+// Competition types
+export interface Competition {
+  id: number;
+  name: string;
+  metric_type: 'skill' | 'boss';
+  metric: string;
+  starts_at: string;
+  ends_at: string;
+  created_by: string;
+  status: 'scheduled' | 'active' | 'finished' | 'cancelled';
+  standings_interval_hours: number;
+  last_standings_at: string | null;
+  created_at: string;
+}
+
+export interface CompetitionStanding {
+  rank: number;
+  player_name: string;
+  start_value: number;
+  current_value: number;
+  gain: number;
+  updated_at: string;
+}
+
+export interface CompetitionDetail {
+  competition: Competition;
+  standings: CompetitionStanding[];
+}
+
+// Upgrade types
+export interface UpgradeMaterialCost {
+  name_id: string;
+  name: string;
+  quantity: number;
+  unit_price: number;
+  total: number;
+  priced: boolean;
+}
+
+export interface UpgradeNextTier {
+  tier: number;
+  gold_cost: number;
+  materials: UpgradeMaterialCost[];
+  total_cost: number;
+  fully_priced: boolean;
+}
+
+export interface UpgradeProgress {
+  key: string;
+  display_name: string;
+  tier: number;
+  max_tier: number;
+  maxed: boolean;
+  next?: UpgradeNextTier;
+}
+
+export interface UpgradeReport {
+  player_name: string;
+  upgrades: UpgradeProgress[];
+  recommendations: UpgradeProgress[];
+}